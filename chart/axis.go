@@ -0,0 +1,272 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Axis is the horizontal or vertical scale of a Chart.
+type Axis struct {
+	Title string
+	// Min and Max fix the range when Min < Max. Otherwise it is taken from
+	// the data and widened to round tick values.
+	Min, Max float64
+	// Time axes hold Unix times in seconds (see TimeValue) and place their
+	// ticks on round seconds, minutes, hours, days, months or years.
+	Time bool
+	// Format labels the ticks and the hover tooltip; nil picks a format
+	// from the tick step.
+	Format func(v float64) string
+	Grid   bool // Draw gridlines at the ticks
+	// TickSpacing is the minimum distance between ticks in pixels.
+	TickSpacing int32
+
+	lo, hi   float64 // Range shown
+	zoomed   bool    // lo and hi set by zooming or panning
+	ticks    []float64
+	step     float64
+	timeStep timeStep
+}
+
+func newAxis(spacing int32) *Axis {
+	return &Axis{Grid: true, TickSpacing: spacing}
+}
+
+// TimeValue converts a time to the value of a Time axis.
+func TimeValue(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// ValueTime converts the value of a Time axis back to a time.
+func ValueTime(v float64) time.Time {
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+// IsZoomed reports whether the range was changed by zooming or panning.
+func (a *Axis) IsZoomed() bool {
+	return a.zoomed
+}
+
+// Range returns the range shown.
+func (a *Axis) Range() (lo, hi float64) {
+	return a.lo, a.hi
+}
+
+// update sets the range from the data unless fixed or zoomed, and
+// computes the ticks for an axis length in pixels.
+func (a *Axis) update(dataLo, dataHi float64, length int32, nice bool) {
+	n := max(2, int(length/max(1, a.TickSpacing)))
+	switch {
+	case a.zoomed:
+	case a.Min < a.Max:
+		a.lo, a.hi = a.Min, a.Max
+	default:
+		if dataLo > dataHi { // No data
+			dataLo, dataHi = 0, 1
+		}
+		if dataLo == dataHi {
+			pad := math.Max(1, math.Abs(dataLo)*0.1)
+			if a.Time {
+				pad = 30
+			}
+			dataLo, dataHi = dataLo-pad, dataHi+pad
+		}
+		a.lo, a.hi = dataLo, dataHi
+		if nice && !a.Time {
+			_, step := niceTicks(dataLo, dataHi, n)
+			a.lo = math.Floor(dataLo/step) * step
+			a.hi = math.Ceil(dataHi/step) * step
+		}
+	}
+	if a.Time {
+		a.timeStep = pickTimeStep(a.hi-a.lo, n)
+		a.ticks = timeTicks(a.lo, a.hi, a.timeStep)
+	} else {
+		a.ticks, a.step = niceTicks(a.lo, a.hi, n)
+	}
+}
+
+// label formats a tick value.
+func (a *Axis) label(v float64) string {
+	if a.Format != nil {
+		return a.Format(v)
+	}
+	if a.Time {
+		return ValueTime(v).Format(a.timeStep.layout)
+	}
+	return formatTick(v, a.step)
+}
+
+// detail formats a value for the hover tooltip, more precisely than the
+// ticks.
+func (a *Axis) detail(v float64) string {
+	if a.Format != nil {
+		return a.Format(v)
+	}
+	if a.Time {
+		layout := "Jan 2 15:04:05"
+		if a.timeStep.months > 0 || a.timeStep.d >= 24*time.Hour {
+			layout = "Jan 2 2006 15:04"
+		}
+		return ValueTime(v).Format(layout)
+	}
+	return formatValue(v)
+}
+
+// niceNum returns a round number (1, 2 or 5 times a power of ten) close
+// to x, rounded or above it.
+func niceNum(x float64, round bool) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	var nf float64
+	switch {
+	case round && f < 1.5, !round && f <= 1:
+		nf = 1
+	case round && f < 3, !round && f <= 2:
+		nf = 2
+	case round && f < 7, !round && f <= 5:
+		nf = 5
+	default:
+		nf = 10
+	}
+	return nf * math.Pow(10, exp)
+}
+
+// niceTicks returns round tick values inside [lo, hi], about n intervals
+// apart, and their step.
+func niceTicks(lo, hi float64, n int) ([]float64, float64) {
+	if !(hi > lo) || n < 1 {
+		return nil, 1
+	}
+	step := niceNum(niceNum(hi-lo, false)/float64(n), true)
+	var ticks []float64
+	for v := math.Ceil(lo/step-1e-9) * step; v <= hi+step*1e-9; v += step {
+		if math.Abs(v) < step*1e-9 {
+			v = 0 // Not -0 or 1e-17
+		}
+		ticks = append(ticks, v)
+	}
+	return ticks, step
+}
+
+// formatTick formats a tick with as many decimals as the step needs, and
+// large values with a k, M or G suffix.
+func formatTick(v, step float64) string {
+	for _, u := range []struct {
+		div    float64
+		suffix string
+	}{{1e9, "G"}, {1e6, "M"}, {1e3, "k"}} {
+		if step >= u.div/10 && math.Abs(v) >= u.div {
+			return formatTick(v/u.div, step/u.div) + u.suffix
+		}
+	}
+	decimals := max(0, int(-math.Floor(math.Log10(step)+1e-9)))
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// formatValue formats a data value with up to four decimals.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// timeStep is a round interval between time ticks: a duration, or a
+// number of months.
+type timeStep struct {
+	d      time.Duration
+	months int
+	layout string // Tick label
+}
+
+func (s timeStep) seconds() float64 {
+	if s.months > 0 {
+		return float64(s.months) * 30.44 * 86400
+	}
+	return s.d.Seconds()
+}
+
+var timeSteps = []timeStep{
+	{d: time.Second, layout: "15:04:05"},
+	{d: 2 * time.Second, layout: "15:04:05"},
+	{d: 5 * time.Second, layout: "15:04:05"},
+	{d: 10 * time.Second, layout: "15:04:05"},
+	{d: 15 * time.Second, layout: "15:04:05"},
+	{d: 30 * time.Second, layout: "15:04:05"},
+	{d: time.Minute, layout: "15:04"},
+	{d: 2 * time.Minute, layout: "15:04"},
+	{d: 5 * time.Minute, layout: "15:04"},
+	{d: 10 * time.Minute, layout: "15:04"},
+	{d: 15 * time.Minute, layout: "15:04"},
+	{d: 30 * time.Minute, layout: "15:04"},
+	{d: time.Hour, layout: "15:04"},
+	{d: 2 * time.Hour, layout: "15:04"},
+	{d: 3 * time.Hour, layout: "15:04"},
+	{d: 6 * time.Hour, layout: "Jan 2 15:04"},
+	{d: 12 * time.Hour, layout: "Jan 2 15:04"},
+	{d: 24 * time.Hour, layout: "Jan 2"},
+	{d: 2 * 24 * time.Hour, layout: "Jan 2"},
+	{d: 7 * 24 * time.Hour, layout: "Jan 2"},
+	{months: 1, layout: "Jan 2006"},
+	{months: 3, layout: "Jan 2006"},
+	{months: 6, layout: "Jan 2006"},
+	{months: 12, layout: "2006"},
+	{months: 24, layout: "2006"},
+	{months: 60, layout: "2006"},
+	{months: 120, layout: "2006"},
+}
+
+// pickTimeStep returns the smallest step giving at most n intervals.
+func pickTimeStep(span float64, n int) timeStep {
+	for _, s := range timeSteps {
+		if span/s.seconds() <= float64(n) {
+			return s
+		}
+	}
+	last := timeSteps[len(timeSteps)-1]
+	years := int(math.Ceil(span/last.seconds()/float64(n))) * 10
+	return timeStep{months: years * 12, layout: "2006"}
+}
+
+// timeTicks returns the ticks of a step inside [lo, hi], aligned on round
+// local times.
+func timeTicks(lo, hi float64, s timeStep) []float64 {
+	start := ValueTime(lo)
+	y, m, d := start.Date()
+	loc := start.Location()
+	var t time.Time
+	switch {
+	case s.months >= 12:
+		years := s.months / 12
+		t = time.Date(y-y%years, 1, 1, 0, 0, 0, 0, loc)
+	case s.months > 0:
+		t = time.Date(y, m-(m-1)%time.Month(s.months), 1, 0, 0, 0, 0, loc)
+	case s.d >= 24*time.Hour:
+		t = time.Date(y, m, d, 0, 0, 0, 0, loc)
+	case s.d >= time.Hour:
+		hours := int(s.d / time.Hour)
+		t = time.Date(y, m, d, start.Hour()-start.Hour()%hours, 0, 0, 0, loc)
+	default:
+		t = start.Truncate(s.d)
+	}
+
+	var ticks []float64
+	for i := 0; i < 1000; i++ {
+		v := TimeValue(t)
+		if v > hi {
+			break
+		}
+		if v >= lo {
+			ticks = append(ticks, v)
+		}
+		switch {
+		case s.months > 0:
+			t = t.AddDate(0, s.months, 0)
+		case s.d >= 24*time.Hour:
+			t = t.AddDate(0, 0, int(s.d/(24*time.Hour)))
+		default:
+			t = t.Add(s.d)
+		}
+	}
+	return ticks
+}
@@ -0,0 +1,612 @@
+// Package chart draws line, area, bar, scatter and pie charts, with axes,
+// legends and a hover tooltip. Charts are components: add them to a panel
+// like any other.
+package chart
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// zoomStep is the zoom factor of one wheel notch.
+const zoomStep = 0.8
+
+// Chart plots series against an X and a Y axis. With Interactive set, a
+// crosshair follows the pointer with the values under it, the wheel zooms
+// the X axis around the pointer (the Y axis with Ctrl), dragging pans and
+// a double click resets the view.
+type Chart struct {
+	component.BaseComponent
+	Title  string
+	Series []*Series
+	XAxis  *Axis
+	YAxis  *Axis
+	// Categories label the X values 0, 1, 2…, e.g. the groups of a bar
+	// chart.
+	Categories  []string
+	ShowLegend  bool // Clicking an entry hides or shows its series
+	Interactive bool
+	Font        *render.Font // If nil, the title is in the theme's title font
+	// Colours are the theme's if 0.
+	BgColor   uint32
+	TextColor uint32
+	GridColor uint32
+	AxisColor uint32
+	Palette   []uint32 // DefaultPalette if empty
+
+	plot      layout.Rect
+	drawn     []*stacked
+	groups    int     // Bar groups per category
+	slot      float64 // Width of a category of bars, in X units
+	legend    []legendItem
+	hovering  bool
+	mx, my    int32
+	dragging  bool
+	dragX     int32
+	dragY     int32
+	dragRange [4]float64 // X and Y ranges when the drag started
+}
+
+func New(width, height int32) *Chart {
+	c := &Chart{
+		XAxis:       newAxis(80),
+		YAxis:       newAxis(40),
+		ShowLegend:  true,
+		Interactive: true,
+	}
+	c.XAxis.Grid = false
+	c.SetBounds(0, 0, width, height)
+	c.Visible = true
+	return c
+}
+
+// AddSeries adds series to the chart.
+func (c *Chart) AddSeries(series ...*Series) {
+	c.Series = append(c.Series, series...)
+	c.RequestRepaint()
+}
+
+// ResetZoom returns both axes to their fixed or automatic range.
+func (c *Chart) ResetZoom() {
+	c.XAxis.zoomed, c.YAxis.zoomed = false, false
+	c.RequestRepaint()
+}
+
+// SetXView shows the X range lo to hi until the zoom is reset, e.g. to
+// follow the latest points of a live chart.
+func (c *Chart) SetXView(lo, hi float64) {
+	c.XAxis.lo, c.XAxis.hi, c.XAxis.zoomed = lo, hi, true
+	c.RequestRepaint()
+}
+
+func (c *Chart) px(x float64) float64 {
+	return float64(c.plot.X) + (x-c.XAxis.lo)/(c.XAxis.hi-c.XAxis.lo)*float64(c.plot.Width)
+}
+
+func (c *Chart) py(y float64) float64 {
+	return float64(c.plot.Y+c.plot.Height) - (y-c.YAxis.lo)/(c.YAxis.hi-c.YAxis.lo)*float64(c.plot.Height)
+}
+
+// valueAt converts a pixel position to X and Y values.
+func (c *Chart) valueAt(x, y int32) (float64, float64) {
+	vx := c.XAxis.lo + float64(x-c.plot.X)/float64(max(1, c.plot.Width))*(c.XAxis.hi-c.XAxis.lo)
+	vy := c.YAxis.lo + float64(c.plot.Y+c.plot.Height-y)/float64(max(1, c.plot.Height))*(c.YAxis.hi-c.YAxis.lo)
+	return vx, vy
+}
+
+// barSlot returns the smallest distance between the X values of the bars,
+// which is the width each category of bars gets.
+func (c *Chart) barSlot() float64 {
+	var xs []float64
+	for _, s := range c.drawn {
+		if s.Kind == Bar {
+			for _, p := range s.Points {
+				xs = append(xs, p.X)
+			}
+		}
+	}
+	sort.Float64s(xs)
+	slot := math.Inf(1)
+	for i := 1; i < len(xs); i++ {
+		if d := xs[i] - xs[i-1]; d > 0 {
+			slot = math.Min(slot, d)
+		}
+	}
+	if math.IsInf(slot, 1) {
+		return 1
+	}
+	return slot
+}
+
+// xRange returns the extent of the data along X, with room for the bars.
+func (c *Chart) xRange() (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, s := range c.drawn {
+		pad := 0.0
+		if s.Kind == Bar {
+			pad = c.slot / 2
+		}
+		for _, p := range s.Points {
+			lo, hi = math.Min(lo, p.X-pad), math.Max(hi, p.X+pad)
+		}
+	}
+	if len(c.Categories) > 0 {
+		lo, hi = math.Min(lo, -0.5), math.Max(hi, float64(len(c.Categories))-0.5)
+	}
+	return lo, hi
+}
+
+// yRange returns the extent of the data along Y, of the points in view if
+// the X axis is zoomed. Bars and areas start at 0.
+func (c *Chart) yRange() (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, s := range c.drawn {
+		for j, p := range s.Points {
+			if c.XAxis.zoomed && (p.X < c.XAxis.lo || p.X > c.XAxis.hi) {
+				continue
+			}
+			lo, hi = math.Min(lo, s.y1[j]), math.Max(hi, s.y1[j])
+			if s.Kind == Bar || s.Kind == Area {
+				lo, hi = math.Min(lo, s.y0[j]), math.Max(hi, s.y0[j])
+			}
+		}
+	}
+	return lo, hi
+}
+
+// xTicks returns the X ticks; whole numbers for categories.
+func (c *Chart) xTicks() []float64 {
+	a := c.XAxis
+	if len(c.Categories) == 0 || a.Time {
+		return a.ticks
+	}
+	n := max(1, int(c.plot.Width/max(1, a.TickSpacing)))
+	step := math.Max(1, math.Ceil((a.hi-a.lo)/float64(n)))
+	var ticks []float64
+	for v := math.Ceil(a.lo); v <= a.hi; v += step {
+		ticks = append(ticks, v)
+	}
+	return ticks
+}
+
+func (c *Chart) xLabel(v float64, detail bool) string {
+	if i := int(math.Round(v)); len(c.Categories) > 0 && !c.XAxis.Time && float64(i) == v {
+		if i >= 0 && i < len(c.Categories) {
+			return c.Categories[i]
+		}
+		return ""
+	}
+	if detail {
+		return c.XAxis.detail(v)
+	}
+	return c.XAxis.label(v)
+}
+
+// layoutLegend places the entries of all series, hidden ones included, in
+// rows from x, y, and returns the height used.
+func (c *Chart) layoutLegend(x, y, width int32) int32 {
+	c.legend = c.legend[:0]
+	_, lineH := render.MeasureText("Ag", c.Font)
+	cx, cy := x, y
+	for i, s := range c.Series {
+		tw, _ := render.MeasureText(s.Name, c.Font)
+		w := 14 + tw
+		if cx > x && cx+w > x+width {
+			cx, cy = x, cy+lineH+2
+		}
+		c.legend = append(c.legend, legendItem{layout.Rect{X: cx, Y: cy, Width: w, Height: lineH}, i})
+		cx += w + 16
+	}
+	if len(c.legend) == 0 {
+		return 0
+	}
+	return cy + lineH - y
+}
+
+func (c *Chart) textColor() uint32 {
+	return theme.Or(c.TextColor, theme.Current().Text)
+}
+
+func (c *Chart) gridColor() uint32 {
+	t := theme.Current()
+	return theme.Or(c.GridColor, theme.Mix(t.Background, t.Text, 0.1))
+}
+
+func (c *Chart) axisColor() uint32 {
+	t := theme.Current()
+	return theme.Or(c.AxisColor, theme.Mix(t.Background, t.Text, 0.47))
+}
+
+func (c *Chart) drawLegend(canvas *render.Canvas) {
+	t := theme.Current()
+	for _, item := range c.legend {
+		s := c.Series[item.index]
+		color, text := s.Color, c.textColor()
+		if color == 0 {
+			color = paletteColor(c.Palette, item.index)
+		}
+		if s.Hidden {
+			color, text = t.Divider, t.TextDisabled
+		}
+		r := item.rect
+		canvas.FillRect(r.X, r.Y+(r.Height-10)/2, 10, 10, color)
+		canvas.DrawText(r.X+14, r.Y, s.Name, text)
+	}
+}
+
+func (c *Chart) Render(canvas *render.Canvas) {
+	if !c.Visible {
+		return
+	}
+	b := c.Bounds
+	t := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(c.BgColor, t.Background))
+	drawOutline(canvas, b, t.Border)
+	canvas.SetFont(c.Font)
+	canvas.PushClip(b.X, b.Y, b.Width, b.Height)
+	defer canvas.PopClip()
+
+	c.drawn, c.groups = stackSeries(c.Series, c.Palette)
+	c.slot = c.barSlot()
+	_, lineH := render.MeasureText("Ag", c.Font)
+
+	// Title, legend and Y axis title stack up at the top
+	top := b.Y + 8
+	if c.Title != "" {
+		font := c.Font
+		if font == nil {
+			font = t.Font(theme.Title)
+		}
+		tw, titleH := render.MeasureText(c.Title, font)
+		canvas.SetFont(font)
+		canvas.DrawText(b.X+(b.Width-tw)/2, top, c.Title, c.textColor())
+		canvas.SetFont(c.Font)
+		top += titleH + 6
+	}
+	c.legend = c.legend[:0]
+	if c.ShowLegend {
+		if h := c.layoutLegend(b.X+12, top, b.Width-24); h > 0 {
+			c.drawLegend(canvas)
+			top += h + 8
+		}
+	}
+	if c.YAxis.Title != "" {
+		canvas.DrawText(b.X+8, top, c.YAxis.Title, c.textColor())
+		top += lineH + 4
+	}
+	top += lineH / 2 // Room for the top tick label
+	bottom := b.Y + b.Height - 8 - lineH - 4
+	if c.XAxis.Title != "" {
+		bottom -= lineH + 2
+	}
+
+	// The Y ticks give the width of their labels, and so where the plot
+	// starts
+	ylo, yhi := c.yRange()
+	c.YAxis.update(ylo, yhi, bottom-top, true)
+	var labelW int32
+	for _, v := range c.YAxis.ticks {
+		w, _ := render.MeasureText(c.YAxis.label(v), c.Font)
+		labelW = max(labelW, w)
+	}
+	left := b.X + 10 + labelW + 6
+	right := b.X + b.Width - 16
+	c.plot = layout.Rect{X: left, Y: top, Width: max(1, right-left), Height: max(1, bottom-top)}
+	xlo, xhi := c.xRange()
+	c.XAxis.update(xlo, xhi, c.plot.Width, len(c.Categories) == 0 && c.groups == 0)
+
+	c.drawAxes(canvas, lineH)
+
+	canvas.PushClip(c.plot.X, c.plot.Y, c.plot.Width, c.plot.Height+1)
+	for _, s := range c.drawn {
+		switch s.Kind {
+		case Bar:
+			c.drawBars(canvas, s)
+		case Area:
+			c.drawArea(canvas, s)
+		}
+	}
+	for _, s := range c.drawn {
+		switch s.Kind {
+		case Line, Area:
+			c.drawLine(canvas, s)
+		case Scatter:
+			c.drawMarkers(canvas, s)
+		}
+	}
+	canvas.PopClip()
+
+	if c.Interactive && c.hovering && !c.dragging && c.plot.Contains(c.mx, c.my) {
+		c.drawHover(canvas)
+	}
+	c.RepaintRequested = false
+}
+
+// drawAxes draws the gridlines, the axis lines, the tick labels and the X
+// axis title.
+func (c *Chart) drawAxes(canvas *render.Canvas, lineH int32) {
+	p := c.plot
+	text, grid, axis := c.textColor(), c.gridColor(), c.axisColor()
+	bottom := p.Y + p.Height
+	for _, v := range c.YAxis.ticks {
+		y := int32(math.Round(c.py(v)))
+		if c.YAxis.Grid {
+			canvas.FillRect(p.X, y, p.Width, 1, grid)
+		}
+		label := c.YAxis.label(v)
+		w, _ := render.MeasureText(label, c.Font)
+		canvas.DrawText(p.X-6-w, y-lineH/2, label, text)
+	}
+	lastRight := int32(math.MinInt32)
+	for _, v := range c.xTicks() {
+		x := int32(math.Round(c.px(v)))
+		if c.XAxis.Grid {
+			canvas.FillRect(x, p.Y, 1, p.Height, grid)
+		}
+		canvas.FillRect(x, bottom, 1, 4, axis)
+		label := c.xLabel(v, false)
+		w, _ := render.MeasureText(label, c.Font)
+		lx := x - w/2
+		if lx < lastRight+8 { // Would overlap the previous label
+			continue
+		}
+		canvas.DrawText(lx, bottom+4, label, text)
+		lastRight = lx + w
+	}
+	canvas.FillRect(p.X, p.Y, 1, p.Height+1, axis)
+	canvas.FillRect(p.X, bottom, p.Width, 1, axis)
+	if c.XAxis.Title != "" {
+		w, _ := render.MeasureText(c.XAxis.Title, c.Font)
+		canvas.DrawText(p.X+(p.Width-w)/2, bottom+6+lineH, c.XAxis.Title, text)
+	}
+}
+
+// visible reports whether point j of s is drawn: in view along X, or next
+// to a point in view so that lines leave the plot at its edges.
+func (c *Chart) visible(s *stacked, j int) bool {
+	in := func(j int) bool {
+		return j >= 0 && j < len(s.Points) && s.Points[j].X >= c.XAxis.lo && s.Points[j].X <= c.XAxis.hi
+	}
+	return in(j-1) || in(j) || in(j+1)
+}
+
+func (c *Chart) drawLine(canvas *render.Canvas, s *stacked) {
+	var pts []render.Point
+	for j, p := range s.Points {
+		if c.visible(s, j) {
+			pts = append(pts, render.Point{X: c.px(p.X), Y: c.py(s.y1[j])})
+		} else if len(pts) > 0 {
+			break
+		}
+	}
+	canvas.StrokePolyline(pts, s.LineWidth, s.color)
+	if s.MarkerSize > 0 {
+		for _, pt := range pts {
+			canvas.FillEllipse(pt.X, pt.Y, s.MarkerSize, s.MarkerSize, s.color)
+		}
+	}
+}
+
+func (c *Chart) drawArea(canvas *render.Canvas, s *stacked) {
+	var tops, bases []render.Point
+	for j, p := range s.Points {
+		if !c.visible(s, j) {
+			continue
+		}
+		x := c.px(p.X)
+		tops = append(tops, render.Point{X: x, Y: c.py(s.y1[j])})
+		bases = append(bases, render.Point{X: x, Y: c.py(s.y0[j])})
+	}
+	for i := len(bases) - 1; i >= 0; i-- {
+		tops = append(tops, bases[i])
+	}
+	canvas.FillPolygon(render.NonZero, withAlpha(s.color, 0x50), tops)
+}
+
+func (c *Chart) drawMarkers(canvas *render.Canvas, s *stacked) {
+	for j, p := range s.Points {
+		if p.X >= c.XAxis.lo && p.X <= c.XAxis.hi {
+			canvas.FillEllipse(c.px(p.X), c.py(s.y1[j]), s.MarkerSize, s.MarkerSize, s.color)
+		}
+	}
+}
+
+// barSpan returns the X range of the bar of s at x.
+func (c *Chart) barSpan(s *stacked, x float64) (float64, float64) {
+	w := c.slot * 0.8 / float64(max(1, c.groups))
+	x0 := x - c.slot*0.4 + float64(s.group)*w
+	return x0, x0 + w
+}
+
+func (c *Chart) drawBars(canvas *render.Canvas, s *stacked) {
+	for j, p := range s.Points {
+		lo, hi := c.barSpan(s, p.X)
+		if hi < c.XAxis.lo || lo > c.XAxis.hi {
+			continue
+		}
+		x0, x1 := int32(math.Round(c.px(lo))), int32(math.Round(c.px(hi)))
+		if x1-x0 > 3 {
+			x1-- // A gap between neighbouring bars
+		}
+		ya, yb := int32(math.Round(c.py(s.y0[j]))), int32(math.Round(c.py(s.y1[j])))
+		canvas.FillRect(x0, min(ya, yb), max(1, x1-x0), max(1, abs(yb-ya)), s.color)
+	}
+}
+
+// nearestX returns the X value of the point closest to the pointer along
+// X, of all series.
+func (c *Chart) nearestX() (float64, bool) {
+	best, found := math.Inf(1), false
+	var x float64
+	for _, s := range c.drawn {
+		for _, p := range s.Points {
+			if p.X < c.XAxis.lo || p.X > c.XAxis.hi {
+				continue
+			}
+			if d := math.Abs(c.px(p.X) - float64(c.mx)); d < best {
+				best, x, found = d, p.X, true
+			}
+		}
+	}
+	return x, found
+}
+
+// drawHover draws the crosshair at the point nearest to the pointer, and
+// the values of the series there.
+func (c *Chart) drawHover(canvas *render.Canvas) {
+	hx, ok := c.nearestX()
+	if !ok {
+		return
+	}
+	x := c.px(hx)
+	t := theme.Current()
+	canvas.PushClip(c.plot.X, c.plot.Y, c.plot.Width, c.plot.Height)
+	if c.groups > 0 {
+		lo, hi := c.px(hx-c.slot/2), c.px(hx+c.slot/2)
+		canvas.BlendRect(int32(lo), c.plot.Y, int32(hi-lo), c.plot.Height, withAlpha(c.textColor(), 0x14))
+	} else {
+		canvas.FillRect(int32(math.Round(x)), c.plot.Y, 1, c.plot.Height, t.TextDisabled)
+	}
+
+	var rows []tipRow
+	for _, s := range c.drawn {
+		j := nearestPoint(s.Points, hx)
+		if j < 0 {
+			continue
+		}
+		if s.Kind != Bar {
+			y := c.py(s.y1[j])
+			canvas.FillEllipse(c.px(s.Points[j].X), y, 4.5, 4.5, theme.Or(c.BgColor, t.Background))
+			canvas.FillEllipse(c.px(s.Points[j].X), y, 3.5, 3.5, s.color)
+		}
+		rows = append(rows, tipRow{s.color, s.Name + ": " + c.YAxis.detail(s.Points[j].Y)})
+	}
+	canvas.PopClip()
+	drawTip(canvas, c.Font, int32(x), c.my, c.Bounds, c.xLabel(hx, true), rows)
+}
+
+// nearestPoint returns the index of the point with the X value closest
+// to x, or -1 if there are none.
+func nearestPoint(points []Point, x float64) int {
+	best, j := math.Inf(1), -1
+	for i, p := range points {
+		if d := math.Abs(p.X - x); d < best {
+			best, j = d, i
+		}
+	}
+	return j
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// zoom scales the range of a around the value at by f.
+func zoom(a *Axis, at, f float64) {
+	a.lo = at - (at-a.lo)*f
+	a.hi = at + (a.hi-at)*f
+	a.zoomed = true
+}
+
+func (c *Chart) CursorAt(x, y int32) component.Cursor {
+	if c.dragging {
+		return component.CursorSizeAll
+	}
+	for _, item := range c.legend {
+		if item.rect.Contains(x, y) {
+			return component.CursorHand
+		}
+	}
+	return component.CursorArrow
+}
+
+func (c *Chart) OnEvent(evt event.Event) bool {
+	if !c.Visible {
+		return false
+	}
+	data, ok := evt.Data.(event.MouseEvent)
+	if !ok {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseMove:
+		if c.dragging {
+			a, b := c.XAxis, c.YAxis
+			dx := float64(data.X-c.dragX) / float64(c.plot.Width) * (c.dragRange[1] - c.dragRange[0])
+			dy := float64(data.Y-c.dragY) / float64(c.plot.Height) * (c.dragRange[3] - c.dragRange[2])
+			a.lo, a.hi, a.zoomed = c.dragRange[0]-dx, c.dragRange[1]-dx, true
+			if b.zoomed || dy != 0 {
+				b.lo, b.hi, b.zoomed = c.dragRange[2]+dy, c.dragRange[3]+dy, true
+			}
+			c.RequestRepaint()
+			return true
+		}
+		hovering := c.Bounds.Contains(data.X, data.Y)
+		if c.Interactive && (hovering || c.hovering) {
+			c.RequestRepaint()
+		}
+		c.hovering, c.mx, c.my = hovering, data.X, data.Y
+
+	case event.EventMouseClick:
+		if !c.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		for _, item := range c.legend {
+			if item.rect.Contains(data.X, data.Y) {
+				s := c.Series[item.index]
+				s.Hidden = !s.Hidden
+				c.RequestRepaint()
+				return true
+			}
+		}
+		if !c.Interactive || !c.plot.Contains(data.X, data.Y) || data.Button != 1 {
+			return true
+		}
+		if data.Clicks == 2 {
+			c.ResetZoom()
+			return true
+		}
+		c.dragging = true
+		c.dragX, c.dragY = data.X, data.Y
+		c.dragRange = [4]float64{c.XAxis.lo, c.XAxis.hi, c.YAxis.lo, c.YAxis.hi}
+		component.SetCapture(c)
+		c.RequestRepaint()
+		return true
+
+	case event.EventMouseRelease:
+		if c.dragging {
+			c.dragging = false
+			component.ReleaseCapture()
+			c.RequestRepaint()
+			return true
+		}
+
+	case event.EventMouseWheel:
+		if !c.Interactive || !c.plot.Contains(data.X, data.Y) {
+			return false
+		}
+		vx, vy := c.valueAt(data.X, data.Y)
+		switch {
+		case data.DeltaX != 0: // Horizontal scrolling pans
+			shift := float64(data.DeltaX) / 120 * 0.1 * (c.XAxis.hi - c.XAxis.lo)
+			c.XAxis.lo, c.XAxis.hi, c.XAxis.zoomed = c.XAxis.lo+shift, c.XAxis.hi+shift, true
+		case data.Modifiers&event.ModCtrl != 0:
+			zoom(c.YAxis, vy, math.Pow(zoomStep, float64(data.Delta)/120))
+		default:
+			zoom(c.XAxis, vx, math.Pow(zoomStep, float64(data.Delta)/120))
+		}
+		c.RequestRepaint()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+func drawOutline(canvas *render.Canvas, b layout.Rect, color uint32) {
+	canvas.FillRect(b.X, b.Y, b.Width, 1, color)
+	canvas.FillRect(b.X, b.Y+b.Height-1, b.Width, 1, color)
+	canvas.FillRect(b.X, b.Y, 1, b.Height, color)
+	canvas.FillRect(b.X+b.Width-1, b.Y, 1, b.Height, color)
+}
+
+// withAlpha replaces the alpha of an ARGB colour.
+func withAlpha(color uint32, alpha uint32) uint32 {
+	return color&0x00FFFFFF | alpha<<24
+}
+
+// legendItem is an entry of a legend, for hit testing.
+type legendItem struct {
+	rect  layout.Rect
+	index int
+}
+
+// tipRow is a line of a hover tooltip, after a colour swatch.
+type tipRow struct {
+	color uint32
+	text  string
+}
+
+// drawTip draws a tooltip box next to the point x, y: to its right, or to
+// its left if there is no room, kept inside bounds.
+func drawTip(canvas *render.Canvas, font *render.Font, x, y int32, bounds layout.Rect, header string, rows []tipRow) {
+	const pad, swatch = 6, 8
+	w, lineH := render.MeasureText(header, font)
+	for _, r := range rows {
+		rw, _ := render.MeasureText(r.text, font)
+		w = max(w, rw+swatch+4)
+	}
+	w += 2 * pad
+	h := lineH*int32(len(rows)+1) + 2*pad - 2
+
+	bx := x + 12
+	if bx+w > bounds.X+bounds.Width {
+		bx = x - 12 - w
+	}
+	bx = max(bounds.X, bx)
+	by := max(bounds.Y, min(y-h/2, bounds.Y+bounds.Height-h))
+
+	canvas.BlendRect(bx+2, by+2, w, h, 0x30000000) // Shadow
+	t := theme.Current()
+	canvas.FillRect(bx, by, w, h, t.Tooltip)
+	ty := by + pad - 1
+	canvas.DrawText(bx+pad, ty, header, t.TooltipText)
+	for _, r := range rows {
+		ty += lineH
+		canvas.FillRect(bx+pad, ty+(lineH-swatch)/2, swatch, swatch, r.color)
+		canvas.DrawText(bx+pad+swatch+4, ty, r.text, t.TooltipText)
+	}
+}
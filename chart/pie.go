@@ -0,0 +1,235 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// Slice is a part of a pie chart.
+type Slice struct {
+	Label string
+	Value float64
+	Color uint32 // 0 picks the next palette colour
+}
+
+// PieChart shows the shares of a whole, as a pie or with HoleRatio as a
+// donut. The slice under the pointer moves out and shows its value.
+type PieChart struct {
+	component.BaseComponent
+	Title  string
+	Slices []Slice
+	// HoleRatio is the radius of the hole relative to the pie: 0 for a
+	// pie, e.g. 0.6 for a donut.
+	HoleRatio  float64
+	CenterText string // In the hole of a donut, e.g. the total
+	ShowLegend bool
+	Format     func(v float64) string // Of the values in the tooltip
+	Font       *render.Font           // If nil, the title is in the theme's title font
+	// Colours are the theme's if 0.
+	BgColor   uint32
+	TextColor uint32
+	Palette   []uint32 // DefaultPalette if empty
+
+	cx, cy float64 // Centre of the pie
+	radius float64
+	hover  int // Slice under the pointer, or -1
+	mx, my int32
+}
+
+// Distance a hovered slice moves out
+const pieExplode = 6
+
+func NewPie(width, height int32) *PieChart {
+	p := &PieChart{
+		ShowLegend: true,
+		hover:      -1,
+	}
+	p.SetBounds(0, 0, width, height)
+	p.Visible = true
+	return p
+}
+
+// AddSlice adds a slice.
+func (p *PieChart) AddSlice(label string, value float64) {
+	p.Slices = append(p.Slices, Slice{Label: label, Value: value})
+	p.RequestRepaint()
+}
+
+func (p *PieChart) total() float64 {
+	var t float64
+	for _, s := range p.Slices {
+		t += math.Max(0, s.Value)
+	}
+	return t
+}
+
+func (p *PieChart) color(i int) uint32 {
+	if c := p.Slices[i].Color; c != 0 {
+		return c
+	}
+	return paletteColor(p.Palette, i)
+}
+
+// angles returns the start and end angle of slice i, clockwise from the
+// top.
+func (p *PieChart) angles(i int, total float64) (float64, float64) {
+	a := -math.Pi / 2
+	for j := 0; j < i; j++ {
+		a += math.Max(0, p.Slices[j].Value) / total * 2 * math.Pi
+	}
+	return a, a + math.Max(0, p.Slices[i].Value)/total*2*math.Pi
+}
+
+func percent(v, total float64) string {
+	return strconv.FormatFloat(v/total*100, 'f', 1, 64) + "%"
+}
+
+func (p *PieChart) Render(canvas *render.Canvas) {
+	if !p.Visible {
+		return
+	}
+	b := p.Bounds
+	t := theme.Current()
+	text := theme.Or(p.TextColor, t.Text)
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(p.BgColor, t.Background))
+	drawOutline(canvas, b, t.Border)
+	canvas.SetFont(p.Font)
+	canvas.PushClip(b.X, b.Y, b.Width, b.Height)
+	defer canvas.PopClip()
+
+	_, lineH := render.MeasureText("Ag", p.Font)
+	area := layout.Rect{X: b.X + 8, Y: b.Y + 8, Width: b.Width - 16, Height: b.Height - 16}
+	if p.Title != "" {
+		font := p.Font
+		if font == nil {
+			font = t.Font(theme.Title)
+		}
+		tw, titleH := render.MeasureText(p.Title, font)
+		canvas.SetFont(font)
+		canvas.DrawText(b.X+(b.Width-tw)/2, area.Y, p.Title, text)
+		canvas.SetFont(p.Font)
+		area.Y += titleH + 6
+		area.Height -= titleH + 6
+	}
+	total := p.total()
+
+	// The legend takes a column on the right, up to half the width
+	if p.ShowLegend && len(p.Slices) > 0 {
+		labels := make([]string, len(p.Slices))
+		var w int32
+		for i, s := range p.Slices {
+			labels[i] = s.Label
+			if total > 0 {
+				labels[i] += " (" + percent(math.Max(0, s.Value), total) + ")"
+			}
+			lw, _ := render.MeasureText(labels[i], p.Font)
+			w = max(w, lw+14)
+		}
+		w = min(w, area.Width/2)
+		lx := area.X + area.Width - w
+		ly := area.Y + max(0, (area.Height-int32(len(labels))*(lineH+4))/2)
+		for i, label := range labels {
+			canvas.FillRect(lx, ly+(lineH-10)/2, 10, 10, p.color(i))
+			canvas.DrawText(lx+14, ly, label, text)
+			ly += lineH + 4
+		}
+		area.Width -= w + 12
+	}
+
+	p.radius = math.Max(4, float64(min(area.Width, area.Height))/2-pieExplode)
+	p.cx = float64(area.X) + float64(area.Width)/2
+	p.cy = float64(area.Y) + float64(area.Height)/2
+	if total > 0 {
+		for i := range p.Slices {
+			p.drawSlice(canvas, i, total)
+		}
+	}
+	if p.HoleRatio > 0 && p.CenterText != "" {
+		tw, _ := render.MeasureText(p.CenterText, p.Font)
+		canvas.DrawText(int32(p.cx)-tw/2, int32(p.cy)-lineH/2, p.CenterText, text)
+	}
+
+	if p.hover >= 0 && p.hover < len(p.Slices) && total > 0 {
+		s := p.Slices[p.hover]
+		value := formatValue(s.Value)
+		if p.Format != nil {
+			value = p.Format(s.Value)
+		}
+		drawTip(canvas, p.Font, p.mx, p.my, b, s.Label,
+			[]tipRow{{p.color(p.hover), value + " (" + percent(s.Value, total) + ")"}})
+	}
+	p.RepaintRequested = false
+}
+
+func (p *PieChart) drawSlice(canvas *render.Canvas, i int, total float64) {
+	a0, a1 := p.angles(i, total)
+	if a1 <= a0 {
+		return
+	}
+	cx, cy := p.cx, p.cy
+	if i == p.hover {
+		mid := (a0 + a1) / 2
+		cx += math.Cos(mid) * pieExplode
+		cy += math.Sin(mid) * pieExplode
+	}
+	shape := render.EllipsePoints(cx, cy, p.radius, p.radius, a0, a1)
+	if hole := p.radius * p.HoleRatio; hole > 0 {
+		inner := render.EllipsePoints(cx, cy, hole, hole, a0, a1)
+		for j := len(inner) - 1; j >= 0; j-- {
+			shape = append(shape, inner[j])
+		}
+	} else if a1-a0 < 2*math.Pi-1e-9 {
+		shape = append(shape, render.Point{X: cx, Y: cy})
+	}
+	canvas.FillPolygon(render.NonZero, p.color(i), shape)
+	// A thin line between neighbouring slices
+	if len(p.Slices) > 1 {
+		canvas.StrokeLine(cx+math.Cos(a0)*p.radius*p.HoleRatio, cy+math.Sin(a0)*p.radius*p.HoleRatio,
+			cx+math.Cos(a0)*p.radius, cy+math.Sin(a0)*p.radius, 1.5, theme.Or(p.BgColor, theme.Current().Background))
+	}
+}
+
+// sliceAt returns the slice at a point, or -1.
+func (p *PieChart) sliceAt(x, y int32) int {
+	total := p.total()
+	dx, dy := float64(x)-p.cx, float64(y)-p.cy
+	r := math.Hypot(dx, dy)
+	if total <= 0 || r > p.radius+pieExplode || r < p.radius*p.HoleRatio {
+		return -1
+	}
+	a := math.Atan2(dy, dx)
+	for a < -math.Pi/2 {
+		a += 2 * math.Pi
+	}
+	for i := range p.Slices {
+		if a0, a1 := p.angles(i, total); a >= a0 && a < a1 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *PieChart) OnEvent(evt event.Event) bool {
+	if !p.Visible || evt.Type != event.EventMouseMove {
+		return false
+	}
+	data, ok := evt.Data.(event.MouseEvent)
+	if !ok {
+		return false
+	}
+	hover := -1
+	if p.Bounds.Contains(data.X, data.Y) {
+		hover = p.sliceAt(data.X, data.Y)
+	}
+	if hover != p.hover || hover >= 0 {
+		p.RequestRepaint()
+	}
+	p.hover, p.mx, p.my = hover, data.X, data.Y
+	return false
+}
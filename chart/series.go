@@ -0,0 +1,129 @@
+package chart
+
+import "time"
+
+// Kind is how a series is drawn.
+type Kind int
+
+const (
+	Line Kind = iota
+	Area
+	Bar
+	Scatter
+)
+
+// Point is a data point.
+type Point struct {
+	X, Y float64
+}
+
+// Series is a named list of points drawn in one colour.
+type Series struct {
+	Name   string
+	Kind   Kind
+	Points []Point
+	Color  uint32 // 0 picks the next palette colour
+	// Stack puts bar or area series with the same non-empty name on top of
+	// each other. Bar series in different stacks are grouped side by side.
+	Stack      string
+	LineWidth  float64 // Of lines and area outlines
+	MarkerSize float64 // Radius of scatter points, and of dots on lines if > 0
+	MaxPoints  int     // Add drops the oldest points beyond this, if > 0
+	Hidden     bool    // Toggled by clicking the legend
+}
+
+func NewSeries(name string, kind Kind) *Series {
+	s := &Series{Name: name, Kind: kind, LineWidth: 2}
+	if kind == Scatter {
+		s.MarkerSize = 3.5
+	}
+	return s
+}
+
+// Add appends a point.
+func (s *Series) Add(x, y float64) {
+	s.Points = append(s.Points, Point{x, y})
+	if s.MaxPoints > 0 && len(s.Points) > s.MaxPoints {
+		s.Points = s.Points[len(s.Points)-s.MaxPoints:]
+	}
+}
+
+// AddTime appends a point for a Time axis.
+func (s *Series) AddTime(t time.Time, y float64) {
+	s.Add(TimeValue(t), y)
+}
+
+// DefaultPalette colours the series and slices that have no colour of
+// their own.
+var DefaultPalette = []uint32{
+	0xFF0078D7, // Blue
+	0xFFFF8C00, // Orange
+	0xFF107C10, // Green
+	0xFFD13438, // Red
+	0xFF8764B8, // Purple
+	0xFF00B7C3, // Teal
+	0xFFC239B3, // Magenta
+	0xFF767676, // Grey
+}
+
+func paletteColor(palette []uint32, i int) uint32 {
+	if len(palette) == 0 {
+		palette = DefaultPalette
+	}
+	return palette[i%len(palette)]
+}
+
+// stacked is a series ready to draw: each point spans y0 to y1, on top of
+// the series below it in its stack.
+type stacked struct {
+	*Series
+	color  uint32
+	index  int // In Chart.Series
+	group  int // Bar slot within a category
+	y0, y1 []float64
+}
+
+// stackSeries stacks the visible series and numbers the bar groups.
+func stackSeries(series []*Series, palette []uint32) (out []*stacked, groups int) {
+	tops := map[string]map[float64]float64{} // Stack -> x -> top so far
+	groupOf := map[string]int{}
+	for i, s := range series {
+		color := s.Color
+		if color == 0 {
+			color = paletteColor(palette, i)
+		}
+		if s.Hidden {
+			continue
+		}
+		st := &stacked{Series: s, color: color, index: i,
+			y0: make([]float64, len(s.Points)), y1: make([]float64, len(s.Points))}
+		top := tops[s.Stack]
+		if s.Stack != "" && (s.Kind == Bar || s.Kind == Area) && top == nil {
+			top = map[float64]float64{}
+			tops[s.Stack] = top
+		}
+		for j, p := range s.Points {
+			st.y0[j], st.y1[j] = 0, p.Y
+			if top != nil && (s.Kind == Bar || s.Kind == Area) {
+				st.y0[j] = top[p.X]
+				st.y1[j] = st.y0[j] + p.Y
+				top[p.X] = st.y1[j]
+			}
+		}
+		if s.Kind == Bar {
+			key := s.Stack
+			if key == "" {
+				key = "\x00" + string(rune(i)) // A group of its own
+			}
+			g, ok := groupOf[key]
+			if !ok {
+				g = groups
+				groupOf[key] = g
+				groups++
+			}
+			st.group = g
+		}
+		out = append(out, st)
+	}
+	return out, groups
+}
@@ -0,0 +1,84 @@
+package component
+
+import (
+	"sync"
+	"time"
+)
+
+// Animator is implemented by components that change over time, such as
+// smooth scrolling. Tick is called on every window timer tick (~60 fps)
+// and returns false once the animation has finished.
+type Animator interface {
+	Tick(now time.Time) bool
+}
+
+// Running animations. Like the rest of the component tree they are only
+// touched from the UI thread.
+var animators []Animator
+
+// StartAnimation registers a for ticking until its Tick returns false.
+// Starting an already running animator has no effect.
+func StartAnimation(a Animator) {
+	for _, other := range animators {
+		if other == a {
+			return
+		}
+	}
+	animators = append(animators, a)
+}
+
+// StopAnimation unregisters a.
+func StopAnimation(a Animator) {
+	for i, other := range animators {
+		if other == a {
+			animators = append(animators[:i], animators[i+1:]...)
+			return
+		}
+	}
+}
+
+// TickAnimations advances all running animations and reports whether any
+// of them was ticked, i.e. whether a repaint is needed. The window calls
+// it from its timer.
+func TickAnimations(now time.Time) bool {
+	if len(animators) == 0 {
+		return false
+	}
+	// Tick a snapshot so animators may start or stop others while ticking
+	running := append([]Animator(nil), animators...)
+	for _, a := range running {
+		if !a.Tick(now) {
+			StopAnimation(a)
+		}
+	}
+	return true
+}
+
+// Functions posted from other goroutines, run on the UI thread.
+var (
+	postedMu sync.Mutex
+	posted   []func()
+)
+
+// Post queues f to run on the UI thread, where it may safely change
+// components. It is safe to call from any goroutine; f runs on the next
+// window timer tick at the latest.
+func Post(f func()) {
+	postedMu.Lock()
+	posted = append(posted, f)
+	postedMu.Unlock()
+}
+
+// RunPosted runs the queued functions and reports whether there were any.
+// The window calls it on the UI thread.
+func RunPosted() bool {
+	postedMu.Lock()
+	queue := posted
+	posted = nil
+	postedMu.Unlock()
+
+	for _, f := range queue {
+		f()
+	}
+	return len(queue) > 0
+}
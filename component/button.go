@@ -2,7 +2,9 @@ package component
 
 import (
 	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type Button struct {
@@ -14,12 +16,14 @@ type Button struct {
 	// State
 	isHovered bool
 	isPressed bool
+	isFocused bool
 }
 
 func NewButton(text string) *Button {
 	b := &Button{
 		Text: text,
 	}
+	b.Padding = layout.SymmetricInsets(5, 10)
 	// Default size based on text + padding
 	w, h := b.GetPreferredSize()
 	b.SetBounds(0, 0, w, h)
@@ -30,7 +34,7 @@ func NewButton(text string) *Button {
 func (b *Button) GetPreferredSize() (int32, int32) {
 	w, h := render.MeasureText(b.Text, b.Font)
 	// Add padding
-	return w + 20, h + 10
+	return w + b.Padding.Horizontal(), h + b.Padding.Vertical()
 }
 
 func (b *Button) Render(canvas *render.Canvas) {
@@ -38,30 +42,47 @@ func (b *Button) Render(canvas *render.Canvas) {
 		return
 	}
 
-	bgColor := uint32(0xFFDDDDDD) // Light gray
+	t := theme.Current()
+	bgColor := t.Control
 	if b.isPressed {
-		bgColor = 0xFFAAAAAA // Darker gray
+		bgColor = t.ControlPressed
 	} else if b.isHovered {
-		bgColor = 0xFFEEEEEE // Lighter gray
+		bgColor = t.ControlHover
+	}
+	borderColor := bgColor
+	if b.isFocused {
+		borderColor = t.Focus
 	}
 
-	canvas.FillRect(b.Bounds.X, b.Bounds.Y, b.Bounds.Width, b.Bounds.Height, bgColor)
+	drawFrame(canvas, b.Bounds.X, b.Bounds.Y, b.Bounds.Width, b.Bounds.Height, bgColor, borderColor)
 
 	// Draw text centered
-	if b.Font != nil {
-		canvas.SetFont(b.Font)
-	}
+	canvas.SetFont(b.Font)
 	
 	textW, textH := render.MeasureText(b.Text, b.Font)
 	
 	textX := b.Bounds.X + (b.Bounds.Width-textW)/2
 	textY := b.Bounds.Y + (b.Bounds.Height-textH)/2
 
-	canvas.DrawText(textX, textY, b.Text, 0xFF000000)
+	canvas.DrawText(textX, textY, b.Text, t.Text)
 
 	b.RepaintRequested = false
 }
 
+func (b *Button) OnFocus() {
+	b.isFocused = true
+	b.RequestRepaint()
+}
+
+func (b *Button) OnBlur() {
+	b.isFocused = false
+	b.RequestRepaint()
+}
+
+func (b *Button) AcceptsFocus() bool {
+	return true
+}
+
 func (b *Button) OnEvent(evt event.Event) bool {
 	if !b.Visible {
 		return false
@@ -99,6 +120,16 @@ func (b *Button) OnEvent(evt event.Event) bool {
 				return true // State changed
 			}
 		}
+
+	case event.EventKeyPress:
+		if data, ok := evt.Data.(event.KeyEvent); ok && b.isFocused {
+			if data.VirtualKeyCode == 0x20 || data.VirtualKeyCode == 0x0D { // Space, Enter
+				if b.OnClick != nil {
+					b.OnClick()
+				}
+				return true
+			}
+		}
 	}
 	return false
 }
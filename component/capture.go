@@ -0,0 +1,23 @@
+package component
+
+// The component that captured the pointer. Like the rest of the component
+// tree it is only touched from the UI thread.
+var captured Component
+
+// SetCapture sends all mouse moves and releases to c, even when the
+// pointer leaves c or the window, until ReleaseCapture is called or the
+// mouse button is released. Components call it on mouse down to keep
+// receiving a drag.
+func SetCapture(c Component) {
+	captured = c
+}
+
+// ReleaseCapture ends the pointer capture.
+func ReleaseCapture() {
+	captured = nil
+}
+
+// Captured returns the component that captured the pointer, or nil.
+func Captured() Component {
+	return captured
+}
@@ -4,26 +4,26 @@ import (
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/layout"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type Card struct {
 	BaseComponent
 	Title      string
-	BgColor    uint32
+	BgColor    uint32       // 0 for the theme's background colour
+	Font       *render.Font // Of the title; the theme's title font if nil
 	InnerPanel *Panel
 }
 
 func NewCard(width, height int32, title string) *Card {
 	c := &Card{
-		Title:   title,
-		BgColor: 0xFFFFFFFF,
+		Title: title,
 	}
 	c.SetBounds(0, 0, width, height)
 	c.Visible = true
 
 	// Inner Panel for content
 	c.InnerPanel = NewPanel(10, 35, width-20, height-45)
-	c.InnerPanel.BgColor = 0xFFFFFFFF // Transparent? Or same as Card
 
 	// Default layout for inner panel
 	c.InnerPanel.SetLayout(&layout.VBoxLayout{Spacing: 5})
@@ -35,6 +35,10 @@ func (c *Card) Add(comp Component) {
 	c.InnerPanel.Add(comp)
 }
 
+func (c *Card) ChildComponents() []Component {
+	return []Component{c.InnerPanel}
+}
+
 func (c *Card) SetLayout(l layout.Layout) {
 	c.InnerPanel.SetLayout(l)
 }
@@ -44,24 +48,21 @@ func (c *Card) Render(canvas *render.Canvas) {
 		return
 	}
 
-	// Draw Background
-	canvas.FillRect(c.Bounds.X, c.Bounds.Y, c.Bounds.Width, c.Bounds.Height, c.BgColor)
+	t := theme.Current()
 
-	// Draw Border (Soft Gray)
-	borderColor := uint32(0xFFDDDDDD)
-	canvas.FillRect(c.Bounds.X, c.Bounds.Y, c.Bounds.Width, 1, borderColor)
-	canvas.FillRect(c.Bounds.X, c.Bounds.Y+c.Bounds.Height-1, c.Bounds.Width, 1, borderColor)
-	canvas.FillRect(c.Bounds.X, c.Bounds.Y, 1, c.Bounds.Height, borderColor)
-	canvas.FillRect(c.Bounds.X+c.Bounds.Width-1, c.Bounds.Y, 1, c.Bounds.Height, borderColor)
+	// Background with a soft border
+	drawFrame(canvas, c.Bounds.X, c.Bounds.Y, c.Bounds.Width, c.Bounds.Height, theme.Or(c.BgColor, t.Background), t.Divider)
 
 	// Draw Title
 	if c.Title != "" {
-		// Draw Title Text
-		// Assuming default font is set in Renderer for now
-		canvas.DrawText(c.Bounds.X+10, c.Bounds.Y+8, c.Title, 0xFF333333)
+		font := themeFont(c.Font, theme.Title)
+		canvas.SetFont(font)
+		_, th := render.MeasureText(c.Title, font)
+		canvas.DrawText(c.Bounds.X+10, c.Bounds.Y+(30-th)/2, c.Title, t.Text)
+		canvas.SetFont(nil)
 
 		// Separator line
-		canvas.FillRect(c.Bounds.X, c.Bounds.Y+30, c.Bounds.Width, 1, 0xFFEEEEEE)
+		canvas.FillRect(c.Bounds.X+1, c.Bounds.Y+30, c.Bounds.Width-2, 1, t.Divider)
 	}
 
 	// Update InnerPanel bounds relative to Card
@@ -73,3 +74,13 @@ func (c *Card) OnEvent(evt event.Event) bool {
 	// Pass events to InnerPanel
 	return c.InnerPanel.OnEvent(evt)
 }
+
+func (c *Card) FindComponentAt(x, y int32) Component {
+	if !c.Visible || !c.Bounds.Contains(x, y) {
+		return nil
+	}
+	if found := c.InnerPanel.FindComponentAt(x, y); found != nil && found != Component(c.InnerPanel) {
+		return found
+	}
+	return c
+}
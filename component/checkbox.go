@@ -3,15 +3,17 @@ package component
 import (
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type CheckBox struct {
 	BaseComponent
-	Text      string
-	Checked   bool
-	OnCheck   func(checked bool)
-	Font      *render.Font
-	isHovered bool
+	Text     string
+	Checked  bool
+	Disabled bool
+	OnCheck  func(checked bool)
+	Font     *render.Font
+	toggleInput
 }
 
 func NewCheckBox(text string) *CheckBox {
@@ -23,12 +25,8 @@ func NewCheckBox(text string) *CheckBox {
 }
 
 func (c *CheckBox) GetPreferredSize() (int32, int32) {
-	w, h := render.MeasureText(c.Text, c.Font)
 	boxSize := int32(16)
-	if h < boxSize {
-		h = boxSize
-	}
-	return w + boxSize + 8, h
+	return toggleSize(c.Text, c.Font, boxSize, boxSize)
 }
 
 func (c *CheckBox) Render(canvas *render.Canvas) {
@@ -41,17 +39,15 @@ func (c *CheckBox) Render(canvas *render.Canvas) {
 	boxX := c.Bounds.X
 	boxY := c.Bounds.Y + (c.Bounds.Height-boxSize)/2
 
-	// Box background
-	bgColor := uint32(0xFFFFFFFF)
-	if c.isHovered {
-		bgColor = 0xFFEEEEEE
+	// Box background and border
+	t := theme.Current()
+	bgColor := t.Background
+	if c.Disabled {
+		bgColor = t.Surface
+	} else if c.isHovered {
+		bgColor = t.ControlHover
 	}
-	canvas.FillRect(boxX, boxY, boxSize, boxSize, bgColor)
-
-	// Box Border (simulated with inner rect for now, or just fill slightly smaller)
-	// TODO: Add proper DrawRect (stroke) to Canvas
-	// For now, let's just fill a smaller white rect inside a gray rect if we want border
-	// But let's keep it simple: just a filled rect.
+	drawFrame(canvas, boxX, boxY, boxSize, boxSize, bgColor, t.Border)
 
 	// Draw Checkmark if checked
 	if c.Checked {
@@ -59,49 +55,44 @@ func (c *CheckBox) Render(canvas *render.Canvas) {
 		innerSize := int32(10)
 		innerX := boxX + (boxSize-innerSize)/2
 		innerY := boxY + (boxSize-innerSize)/2
-		canvas.FillRect(innerX, innerY, innerSize, innerSize, 0xFF000000)
+		markColor := t.Primary
+		if c.Disabled {
+			markColor = t.TextDisabled
+		}
+		canvas.FillRoundRect(innerX, innerY, innerSize, innerSize, t.CornerRadius/2, markColor)
 	}
 
 	// Draw Text
-	if c.Font != nil {
-		canvas.SetFont(c.Font)
-	}
-	textX := boxX + boxSize + 8
-	textY := c.Bounds.Y + (c.Bounds.Height-16)/2
-	// Centering text vertically relative to box might be tricky if font size varies
-	// Better to use MeasureText to center
-	_, textH := render.MeasureText(c.Text, c.Font)
-	textY = c.Bounds.Y + (c.Bounds.Height-textH)/2
-
-	canvas.DrawText(textX, textY, c.Text, 0xFF000000)
+	drawToggleLabel(canvas, c.Bounds, boxX+boxSize+toggleLabelGap(), c.Text, c.Font, c.Disabled, c.isFocused)
 	c.RepaintRequested = false
 }
 
+func (c *CheckBox) OnFocus() {
+	c.toggleInput.OnFocus()
+	c.RequestRepaint()
+}
+
+func (c *CheckBox) OnBlur() {
+	c.toggleInput.OnBlur()
+	c.RequestRepaint()
+}
+
+func (c *CheckBox) AcceptsFocus() bool {
+	return !c.Disabled
+}
+
 func (c *CheckBox) OnEvent(evt event.Event) bool {
 	if !c.Visible {
 		return false
 	}
 
-	switch evt.Type {
-	case event.EventMouseMove:
-		if data, ok := evt.Data.(event.MouseEvent); ok {
-			wasHovered := c.isHovered
-			c.isHovered = c.Bounds.Contains(data.X, data.Y)
-			if wasHovered != c.isHovered {
-				return true
-			}
-		}
-
-	case event.EventMouseClick: // Mouse Down
-		if data, ok := evt.Data.(event.MouseEvent); ok {
-			if c.Bounds.Contains(data.X, data.Y) {
-				c.Checked = !c.Checked
-				if c.OnCheck != nil {
-					c.OnCheck(c.Checked)
-				}
-				return true
-			}
+	handled, activated := c.handle(evt, c.Bounds, c.Disabled)
+	if activated {
+		c.Checked = !c.Checked
+		if c.OnCheck != nil {
+			c.OnCheck(c.Checked)
 		}
+		c.RequestRepaint()
 	}
-	return false
+	return handled
 }
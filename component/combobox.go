@@ -0,0 +1,470 @@
+package component
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	comboButtonWidth  = 20
+	typeAheadTimeout  = time.Second
+	comboDefaultItems = 8
+)
+
+// ComboBox lets the user pick one of Items from a dropdown list. An
+// Editable combo box also accepts free text and suggests the items
+// starting with what has been typed.
+type ComboBox struct {
+	BaseComponent
+	Items           []string
+	Selected        int    // Index into Items, or -1
+	Text            string // Selected item, or the typed text when Editable
+	Editable        bool
+	Placeholder     string
+	Font            *render.Font
+	MaxVisibleItems int // Rows shown in the dropdown before it scrolls
+
+	OnChange func(index int, text string)
+
+	editor   *TextBox
+	list     *ListView
+	source   *StringList
+	popup    *Popup
+	filtered []int // Items shown in the dropdown
+
+	isFocused bool
+	isHovered bool // Over the arrow button
+
+	typed     string // Type-ahead prefix
+	lastTyped time.Time
+}
+
+func NewComboBox(width int32, items []string) *ComboBox {
+	c := &ComboBox{
+		Items:           items,
+		Selected:        -1,
+		MaxVisibleItems: comboDefaultItems,
+		editor:          NewTextBox(width - comboButtonWidth),
+		source:          &StringList{},
+	}
+	c.list = NewListView(width, 0, c.source)
+	c.list.ActivateOnClick = true
+	c.list.OnActivate = func(i int) {
+		c.commit(c.filtered[i])
+		c.Close()
+	}
+	c.popup = NewPopup(c.list, c)
+	c.popup.OnClose = func() {
+		c.list.OnBlur()
+		c.RequestRepaint()
+	}
+	_, h := c.editor.GetPreferredSize()
+	c.SetBounds(0, 0, width, h)
+	c.Visible = true
+	return c
+}
+
+func (c *ComboBox) GetPreferredSize() (int32, int32) {
+	c.editor.Font = c.Font
+	_, h := c.editor.GetPreferredSize()
+	return c.Bounds.Width, h
+}
+
+func (c *ComboBox) SetBounds(x, y, width, height int32) {
+	c.BaseComponent.SetBounds(x, y, width, height)
+	c.editor.SetBounds(x, y, max(width-comboButtonWidth, 0), height)
+}
+
+// SetSelected selects item i (or nothing for -1) without calling OnChange.
+func (c *ComboBox) SetSelected(i int) {
+	if i < -1 || i >= len(c.Items) {
+		return
+	}
+	c.Selected = i
+	c.Text = ""
+	if i >= 0 {
+		c.Text = c.Items[i]
+	}
+	c.syncEditor()
+	c.RequestRepaint()
+}
+
+// SetText sets the text of an editable combo box, selecting the item that
+// matches it exactly, if any. OnChange is not called.
+func (c *ComboBox) SetText(text string) {
+	c.Text = text
+	c.Selected = c.indexOf(text)
+	c.syncEditor()
+	c.RequestRepaint()
+}
+
+func (c *ComboBox) indexOf(text string) int {
+	for i, item := range c.Items {
+		if item == text {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *ComboBox) syncEditor() {
+	c.editor.Text = c.Text
+	c.editor.SelectAll()
+}
+
+// commit makes item i the selection and reports the change.
+func (c *ComboBox) commit(i int) {
+	if i < 0 || i >= len(c.Items) {
+		return
+	}
+	changed := i != c.Selected || c.Text != c.Items[i]
+	c.Selected = i
+	c.Text = c.Items[i]
+	c.syncEditor()
+	c.RequestRepaint()
+	if changed && c.OnChange != nil {
+		c.OnChange(i, c.Text)
+	}
+}
+
+// commitText reports the typed text of an editable combo box.
+func (c *ComboBox) commitText() {
+	c.Selected = c.indexOf(c.Text)
+	if c.OnChange != nil {
+		c.OnChange(c.Selected, c.Text)
+	}
+}
+
+// Dropdown
+
+// IsOpen reports whether the dropdown is shown.
+func (c *ComboBox) IsOpen() bool {
+	return c.popup.IsOpen()
+}
+
+// Open shows the dropdown with all items.
+func (c *ComboBox) Open() {
+	c.openFiltered("")
+}
+
+// Close hides the dropdown.
+func (c *ComboBox) Close() {
+	c.popup.Close()
+}
+
+// openFiltered shows the items starting with prefix (case-insensitive),
+// closing the dropdown if there are none.
+func (c *ComboBox) openFiltered(prefix string) {
+	overlay := ActiveOverlay()
+	if overlay == nil {
+		return
+	}
+	prefix = strings.ToLower(prefix)
+	c.filtered = c.filtered[:0]
+	items := make([]string, 0, len(c.Items))
+	current := -1
+	for i, item := range c.Items {
+		if strings.HasPrefix(strings.ToLower(item), prefix) {
+			if i == c.Selected {
+				current = len(c.filtered)
+			}
+			c.filtered = append(c.filtered, i)
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		c.Close()
+		return
+	}
+
+	c.source.Items = items
+	c.source.Font = c.Font
+	c.list.SetSource(c.source)
+	if current >= 0 {
+		c.list.Select(current)
+	}
+	c.list.OnFocus() // Draws the keyboard cursor while the combo has the focus
+
+	rows := min(len(items), max(c.MaxVisibleItems, 1))
+	h := int32(rows)*c.list.RowHeight + 2
+	overlay.ShowAt(c.popup, c.Bounds, c.Bounds.Width, h)
+	c.RequestRepaint()
+}
+
+func (c *ComboBox) toggle() {
+	if c.IsOpen() {
+		c.Close()
+	} else {
+		c.Open()
+	}
+}
+
+// step moves the selection of a closed combo box by delta items.
+func (c *ComboBox) step(delta int) {
+	if len(c.Items) == 0 {
+		return
+	}
+	i := c.Selected + delta
+	if c.Selected < 0 {
+		i = 0
+	}
+	c.commit(max(0, min(i, len(c.Items)-1)))
+}
+
+// typeAhead jumps to the next item starting with the typed characters.
+func (c *ComboBox) typeAhead(r rune) {
+	now := time.Now()
+	if now.Sub(c.lastTyped) > typeAheadTimeout {
+		c.typed = ""
+	}
+	c.lastTyped = now
+	c.typed += strings.ToLower(string(r))
+
+	// A new prefix starts after the current item so that typing the same
+	// letter again cycles through the items starting with it.
+	current := c.Selected
+	if c.IsOpen() {
+		if i := c.list.CurrentIndex(); i >= 0 {
+			current = c.filtered[i]
+		}
+	}
+	start := current
+	if len([]rune(c.typed)) == 1 {
+		start++
+	}
+	n := len(c.Items)
+	for k := 0; k < n; k++ {
+		i := ((start+k)%n + n) % n
+		if !strings.HasPrefix(strings.ToLower(c.Items[i]), c.typed) {
+			continue
+		}
+		if !c.IsOpen() {
+			c.commit(i)
+			return
+		}
+		for j, f := range c.filtered {
+			if f == i {
+				c.list.Select(j)
+			}
+		}
+		return
+	}
+}
+
+// Rendering
+
+func (c *ComboBox) Render(canvas *render.Canvas) {
+	if !c.Visible {
+		return
+	}
+	b := c.Bounds
+	bx := b.X + b.Width - comboButtonWidth
+	t := theme.Current()
+
+	if c.Editable {
+		c.editor.Font = c.Font
+		c.editor.Placeholder = c.Placeholder
+		c.editor.Render(canvas)
+		fillInFrame(canvas, b, bx, b.Y, comboButtonWidth, b.Height, t.Background)
+	} else {
+		bgColor := t.Background
+		if c.isFocused {
+			bgColor = theme.Mix(t.Background, t.Hover, 0.5)
+		}
+		canvas.FillRoundRect(b.X, b.Y, b.Width, b.Height, t.CornerRadius, bgColor)
+
+		canvas.SetFont(c.Font)
+		text, color := c.Text, t.Text
+		if text == "" {
+			text, color = c.Placeholder, t.TextDisabled
+		}
+		_, th := render.MeasureText(text, c.Font)
+		canvas.PushClip(b.X+1, b.Y, max(bx-b.X-2, 0), b.Height)
+		canvas.DrawText(b.X+10, b.Y+(b.Height-th)/2, text, color)
+		canvas.PopClip()
+	}
+
+	if c.isHovered || c.IsOpen() {
+		fillInFrame(canvas, b, bx, b.Y+1, comboButtonWidth-1, b.Height-2, t.Hover)
+	}
+	drawArrowGlyph(canvas, bx, b.Y, comboButtonWidth, b.Height, Vertical, true, t.Icon)
+
+	borderColor := t.Border
+	if c.isFocused || c.IsOpen() {
+		borderColor = t.Focus
+	}
+	canvas.DrawRoundRect(b.X, b.Y, b.Width, b.Height, t.CornerRadius, borderColor)
+	c.RepaintRequested = false
+}
+
+// Events
+
+func (c *ComboBox) OnFocus() {
+	c.isFocused = true
+	if c.Editable {
+		c.editor.OnFocus()
+	}
+	c.RequestRepaint()
+}
+
+func (c *ComboBox) OnBlur() {
+	c.isFocused = false
+	if c.Editable {
+		c.editor.OnBlur()
+		if c.Text != c.itemText(c.Selected) {
+			c.commitText()
+		}
+	}
+	c.Close()
+	c.RequestRepaint()
+}
+
+func (c *ComboBox) AcceptsFocus() bool {
+	return true
+}
+
+func (c *ComboBox) itemText(i int) string {
+	if i < 0 || i >= len(c.Items) {
+		return ""
+	}
+	return c.Items[i]
+}
+
+// overButton reports whether a point is on the arrow button.
+func (c *ComboBox) overButton(x, y int32) bool {
+	b := c.Bounds
+	return c.Bounds.Contains(x, y) && x >= b.X+b.Width-comboButtonWidth
+}
+
+func (c *ComboBox) OnEvent(evt event.Event) bool {
+	if !c.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !c.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if c.Editable && !c.overButton(data.X, data.Y) {
+			return c.editor.OnEvent(evt)
+		}
+		c.toggle()
+		return true
+
+	case event.EventMouseMove, event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if hovered := c.overButton(data.X, data.Y); hovered != c.isHovered {
+			c.isHovered = hovered
+			c.RequestRepaint()
+		}
+		if c.Editable {
+			return c.editor.OnEvent(evt)
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !c.isFocused {
+			return false
+		}
+		return c.onKey(evt, data)
+
+	case event.EventChar:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !c.isFocused || data.Rune < 32 {
+			return false
+		}
+		if !c.Editable {
+			c.typeAhead(data.Rune)
+			return true
+		}
+		if c.editor.OnEvent(evt) {
+			c.textEdited()
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ComboBox) onKey(evt event.Event, data event.KeyEvent) bool {
+	key := data.VirtualKeyCode
+	alt := data.Modifiers&event.ModAlt != 0
+
+	if (key == 0x28 && alt) || key == 0x73 { // Alt+Down, F4
+		c.toggle()
+		return true
+	}
+
+	if c.IsOpen() {
+		switch key {
+		case 0x26, 0x28, 0x21, 0x22: // Up, Down, PgUp, PgDn
+			return c.list.OnEvent(evt)
+		case 0x24, 0x23: // Home, End
+			if !c.Editable {
+				return c.list.OnEvent(evt)
+			}
+		case 0x0D: // Enter
+			if i := c.list.CurrentIndex(); i >= 0 {
+				c.commit(c.filtered[i])
+			} else if c.Editable {
+				c.commitText()
+			}
+			c.Close()
+			return true
+		}
+	} else {
+		switch key {
+		case 0x26: // Up
+			c.step(-1)
+			return true
+		case 0x28: // Down
+			c.step(1)
+			return true
+		}
+		if !c.Editable {
+			switch key {
+			case 0x24: // Home
+				c.commit(0)
+				return true
+			case 0x23: // End
+				c.commit(len(c.Items) - 1)
+				return true
+			case 0x20: // Space
+				c.Open()
+				return true
+			}
+		} else if key == 0x0D { // Enter
+			c.commitText()
+			return true
+		}
+	}
+
+	if c.Editable && c.editor.OnEvent(evt) {
+		if key == 0x08 { // Backspace
+			c.textEdited()
+		}
+		return true
+	}
+	return false
+}
+
+// textEdited updates the suggestions after the user typed.
+func (c *ComboBox) textEdited() {
+	if c.editor.Text == c.Text {
+		return
+	}
+	c.Text = c.editor.Text
+	if c.Text == "" {
+		c.Close()
+		return
+	}
+	c.openFiltered(c.Text)
+}
@@ -1,6 +1,8 @@
 package component
 
 import (
+	"time"
+
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/layout"
 	"github.com/jacksalad/goui_v0/render"
@@ -19,10 +21,73 @@ type Component interface {
 	GetPreferredSize() (int32, int32)
 }
 
+// HitTester is implemented by containers so that hit testing (e.g. for
+// focus) can descend into their children.
+type HitTester interface {
+	FindComponentAt(x, y int32) Component
+}
+
+// Container is implemented by components that hold others, so that the
+// component tree can be walked, e.g. to find the containers around the
+// focused component.
+type Container interface {
+	ChildComponents() []Component
+}
+
+// ShortcutHandler is implemented by containers that handle keys for
+// their whole content, such as Ctrl+Tab in a TabView. The window offers
+// them the key presses the focused component left unhandled, innermost
+// container first.
+type ShortcutHandler interface {
+	HandleShortcut(key event.KeyEvent) bool
+}
+
+// Ancestors returns the containers from root down to the parent of c, or
+// nil if c is not inside root.
+func Ancestors(root, c Component) []Component {
+	container, ok := root.(Container)
+	if !ok || root == c {
+		return nil
+	}
+	for _, child := range container.ChildComponents() {
+		if child == c {
+			return []Component{root}
+		}
+		if path := Ancestors(child, c); path != nil {
+			return append([]Component{root}, path...)
+		}
+	}
+	return nil
+}
+
 type BaseComponent struct {
 	Bounds           layout.Rect
 	Visible          bool
 	RepaintRequested bool // Simplistic dirty flag
+
+	// Layout hints, honoured by every layout in the layout package.
+	// Padding only affects containers and components that draw content
+	// inside it; zero Min/Max sizes mean unconstrained.
+	Margin    layout.Insets
+	Padding   layout.Insets
+	MinWidth  int32
+	MinHeight int32
+	MaxWidth  int32
+	MaxHeight int32
+
+	// ContextMenu is shown by the window on right click or the context
+	// menu key.
+	ContextMenu *Menu
+
+	// Tooltip is shown by the window when the pointer rests on the
+	// component for TooltipDelay (DefaultTooltipDelay if 0). TooltipView
+	// replaces the text with a component.
+	Tooltip      string
+	TooltipView  Component
+	TooltipDelay time.Duration
+
+	hAlign, vAlign layout.FlexAlign
+	alignSet       bool
 }
 
 func (b *BaseComponent) OnFocus() {}
@@ -52,5 +117,34 @@ func (b *BaseComponent) IsVisible() bool {
 	return b.Visible
 }
 
+// SetAlignment sets how the component is positioned inside the slot its
+// layout gives it. layout.AlignAuto leaves the choice to the layout.
+func (b *BaseComponent) SetAlignment(h, v layout.FlexAlign) {
+	b.hAlign, b.vAlign = h, v
+	b.alignSet = true
+}
+
+func (b *BaseComponent) GetAlignment() (layout.FlexAlign, layout.FlexAlign) {
+	if !b.alignSet {
+		return layout.AlignAuto, layout.AlignAuto
+	}
+	return b.hAlign, b.vAlign
+}
+
+// LayoutHints implements layout.Hinted.
+func (b *BaseComponent) LayoutHints() layout.Hints {
+	h, v := b.GetAlignment()
+	return layout.Hints{
+		Margin:    b.Margin,
+		Padding:   b.Padding,
+		HAlign:    h,
+		VAlign:    v,
+		MinWidth:  b.MinWidth,
+		MinHeight: b.MinHeight,
+		MaxWidth:  b.MaxWidth,
+		MaxHeight: b.MaxHeight,
+	}
+}
+
 func (b *BaseComponent) Render(canvas *render.Canvas)   {}
 func (b *BaseComponent) OnEvent(event event.Event) bool { return false }
@@ -0,0 +1,20 @@
+package component
+
+// Cursor is a mouse pointer shape.
+type Cursor int
+
+const (
+	CursorArrow Cursor = iota
+	CursorIBeam
+	CursorHand
+	CursorSizeWE // Horizontal resize
+	CursorSizeNS // Vertical resize
+	CursorSizeAll
+)
+
+// CursorProvider is implemented by components that change the pointer
+// shape. The window asks the component under the pointer, or the one that
+// captured it, and shows an arrow for the others.
+type CursorProvider interface {
+	CursorAt(x, y int32) Cursor
+}
@@ -0,0 +1,368 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	dialogTitleHeight = 30
+	dialogButtonWidth = 80 // Minimum
+	dialogMinWidth    = 280
+)
+
+// dialogPadding is the space around the content and the buttons, and
+// dialogButtonGap the space between buttons, from the theme's spacing.
+func dialogPadding() int32 {
+	return theme.Current().Space(4)
+}
+
+func dialogButtonGap() int32 {
+	return theme.Current().Space(2)
+}
+
+// Dialog is a window-like box shown in the window's overlay, with a title
+// bar, a content component and a row of buttons. Modal dialogs dim and
+// block the window below them and keep the focus inside: Tab cycles
+// through the dialog only. Enter clicks DefaultButton and Escape clicks
+// CancelButton (or just closes the dialog). Dialogs can be moved by their
+// title bar.
+type Dialog struct {
+	BaseComponent
+	Title         string
+	Content       Component
+	Buttons       []*Button
+	DefaultButton *Button      // Clicked by Enter
+	CancelButton  *Button      // Clicked by Escape and the close button
+	Modal         bool         // True by default
+	Font          *render.Font // The title uses the theme's title font if nil
+	BgColor       uint32       // 0 for the theme's background colour
+
+	// OnClose is called when the dialog closes, with the result of the
+	// button that closed it, or "" if it was cancelled without one.
+	OnClose func(result string)
+	Result  string
+
+	popup     *Popup
+	overlay   *Overlay // The dialog was shown in
+	prevFocus Component
+
+	dragging     bool
+	dragX, dragY int32 // Pointer position in the dialog
+	closeHovered bool
+}
+
+func NewDialog(title string, content Component) *Dialog {
+	d := &Dialog{
+		Title:   title,
+		Content: content,
+		Modal:   true,
+	}
+	d.Visible = true
+	return d
+}
+
+// AddButton adds a button that closes the dialog with the given result.
+func (d *Dialog) AddButton(text, result string) *Button {
+	b := NewButton(text)
+	b.Font = d.Font
+	b.OnClick = func() { d.Close(result) }
+	d.Buttons = append(d.Buttons, b)
+	return b
+}
+
+// IsOpen reports whether the dialog is shown.
+func (d *Dialog) IsOpen() bool {
+	return d.popup != nil && d.popup.IsOpen()
+}
+
+// Show opens the dialog in the middle of the active window and focuses
+// its first focusable component.
+func (d *Dialog) Show() {
+	o := ActiveOverlay()
+	if o == nil || d.IsOpen() {
+		return
+	}
+	w, h := d.GetPreferredSize()
+	b := o.Bounds
+	d.SetBounds(b.X+(b.Width-w)/2, b.Y+max(0, (b.Height-h)/2), w, h)
+
+	d.Result = ""
+	d.overlay = o
+	d.popup = &Popup{Content: d, Modal: d.Modal, Dim: d.Modal, Shadow: true}
+	d.popup.OnClose = d.closed
+	if o.Focused != nil {
+		d.prevFocus = o.Focused()
+	}
+	o.Show(d.popup)
+
+	if o.Focus != nil {
+		var target Component = d.DefaultButton
+		if chain := FocusChain(d.Content); len(chain) > 0 {
+			target = chain[0]
+		} else if target == nil && len(d.Buttons) > 0 {
+			target = d.Buttons[0]
+		}
+		if target != nil {
+			o.Focus(target)
+		}
+	}
+}
+
+// Close closes the dialog with a result.
+func (d *Dialog) Close(result string) {
+	if !d.IsOpen() {
+		return
+	}
+	d.Result = result
+	d.popup.Close()
+}
+
+// closed gives the focus back and reports the result.
+func (d *Dialog) closed() {
+	d.dragging = false
+	if d.overlay.Focus != nil {
+		d.overlay.Focus(d.prevFocus)
+	}
+	d.prevFocus = nil
+	if d.OnClose != nil {
+		d.OnClose(d.Result)
+	}
+}
+
+// cancel clicks the cancel button, or closes the dialog without a result.
+func (d *Dialog) cancel() {
+	if d.CancelButton != nil && d.CancelButton.OnClick != nil {
+		d.CancelButton.OnClick()
+		return
+	}
+	d.Close("")
+}
+
+func (d *Dialog) buttonSize(b *Button) (int32, int32) {
+	w, h := b.GetPreferredSize()
+	return max(w, dialogButtonWidth), max(h, 28)
+}
+
+func (d *Dialog) GetPreferredSize() (int32, int32) {
+	var cw, ch int32
+	if d.Content != nil {
+		cw, ch = d.Content.GetPreferredSize()
+	}
+	var bw, bh int32
+	for i, b := range d.Buttons {
+		w, h := d.buttonSize(b)
+		if i > 0 {
+			bw += dialogButtonGap()
+		}
+		bw += w
+		bh = max(bh, h)
+	}
+	tw, _ := render.MeasureText(d.Title, themeFont(d.Font, theme.Title))
+
+	pad := dialogPadding()
+	w := max(dialogMinWidth, cw+2*pad, bw+2*pad, tw+2*pad+dialogTitleHeight)
+	h := dialogTitleHeight + pad + ch + pad
+	if bh > 0 {
+		h += bh + pad
+	}
+	return w, h
+}
+
+func (d *Dialog) SetBounds(x, y, width, height int32) {
+	d.BaseComponent.SetBounds(x, y, width, height)
+
+	// Buttons right-aligned along the bottom
+	pad := dialogPadding()
+	bx := x + width - pad
+	for i := len(d.Buttons) - 1; i >= 0; i-- {
+		w, h := d.buttonSize(d.Buttons[i])
+		bx -= w
+		d.Buttons[i].SetBounds(bx, y+height-pad-h, w, h)
+		bx -= dialogButtonGap()
+	}
+
+	if d.Content != nil {
+		top := y + dialogTitleHeight + pad
+		bottom := y + height - pad
+		if len(d.Buttons) > 0 {
+			bottom = d.Buttons[0].Bounds.Y - pad
+		}
+		d.Content.SetBounds(x+pad, top, width-2*pad, max(0, bottom-top))
+	}
+}
+
+// titleRect returns the title bar, and closeRect its close button.
+func (d *Dialog) titleRect() layout.Rect {
+	return layout.Rect{X: d.Bounds.X, Y: d.Bounds.Y, Width: d.Bounds.Width, Height: dialogTitleHeight}
+}
+
+func (d *Dialog) closeRect() layout.Rect {
+	t := d.titleRect()
+	return layout.Rect{X: t.X + t.Width - dialogTitleHeight, Y: t.Y, Width: dialogTitleHeight, Height: dialogTitleHeight}
+}
+
+func (d *Dialog) Render(canvas *render.Canvas) {
+	if !d.Visible {
+		return
+	}
+	b := d.Bounds
+	th := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(d.BgColor, th.Background))
+
+	// Draw Title Bar
+	t := d.titleRect()
+	canvas.FillRect(t.X, t.Y, t.Width, t.Height, th.Surface)
+	titleFont := themeFont(d.Font, theme.Title)
+	canvas.SetFont(titleFont)
+	_, textH := render.MeasureText(d.Title, titleFont)
+	canvas.DrawText(t.X+dialogPadding(), t.Y+(t.Height-textH)/2, d.Title, th.Text)
+	canvas.SetFont(d.Font)
+	c := d.closeRect()
+	if d.closeHovered {
+		canvas.FillRect(c.X, c.Y, c.Width, c.Height, th.Danger) // Red, as in Windows title bars
+		drawCloseGlyph(canvas, c.X+c.Width/2, c.Y+c.Height/2, th.OnPrimary)
+	} else {
+		drawCloseGlyph(canvas, c.X+c.Width/2, c.Y+c.Height/2, th.Icon)
+	}
+	drawRectOutline(canvas, b.X, b.Y, b.Width, b.Height, th.Border)
+
+	if d.Content != nil {
+		r := d.Content.GetBounds()
+		canvas.PushClip(r.X, r.Y, r.Width, r.Height)
+		d.Content.Render(canvas)
+		canvas.PopClip()
+	}
+	for _, btn := range d.Buttons {
+		btn.Render(canvas)
+		if btn == d.DefaultButton {
+			r := btn.Bounds
+			canvas.DrawRoundRect(r.X-1, r.Y-1, r.Width+2, r.Height+2, th.CornerRadius+1, th.Primary)
+		}
+	}
+	d.RepaintRequested = false
+}
+
+func (d *Dialog) ChildComponents() []Component {
+	var children []Component
+	if d.Content != nil {
+		children = append(children, d.Content)
+	}
+	for _, b := range d.Buttons {
+		children = append(children, b)
+	}
+	return children
+}
+
+func (d *Dialog) FindComponentAt(x, y int32) Component {
+	if !d.Visible || !d.Bounds.Contains(x, y) {
+		return nil
+	}
+	for _, child := range d.ChildComponents() {
+		if container, ok := child.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil {
+				return found
+			}
+		} else if child.IsVisible() && child.GetBounds().Contains(x, y) {
+			return child
+		}
+	}
+	return d
+}
+
+// HandleShortcut implements the default and cancel keys, and keeps Tab
+// inside the dialog.
+func (d *Dialog) HandleShortcut(key event.KeyEvent) bool {
+	if key.Modifiers&(event.ModCtrl|event.ModAlt) != 0 {
+		return false
+	}
+	switch key.VirtualKeyCode {
+	case 0x0D: // Enter
+		if d.DefaultButton != nil && d.DefaultButton.OnClick != nil {
+			d.DefaultButton.OnClick()
+			return true
+		}
+	case 0x1B: // Escape
+		d.cancel()
+		return true
+	case 0x09: // Tab
+		o := d.overlay
+		if !d.IsOpen() || o.Focus == nil || o.Focused == nil {
+			return false
+		}
+		if next := NextFocus(d, o.Focused(), key.Modifiers&event.ModShift != 0); next != nil {
+			o.Focus(next)
+		}
+		return true
+	}
+	return false
+}
+
+func (d *Dialog) OnEvent(evt event.Event) bool {
+	if !d.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !d.titleRect().Contains(data.X, data.Y) {
+			break
+		}
+		if d.closeRect().Contains(data.X, data.Y) {
+			d.cancel()
+			return true
+		}
+		d.dragging = true
+		d.dragX, d.dragY = data.X-d.Bounds.X, data.Y-d.Bounds.Y
+		SetCapture(d)
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			break
+		}
+		if d.dragging {
+			d.moveTo(data.X-d.dragX, data.Y-d.dragY)
+			return true
+		}
+		if hovered := d.closeRect().Contains(data.X, data.Y); hovered != d.closeHovered {
+			d.closeHovered = hovered
+			d.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if d.dragging {
+			d.dragging = false
+			ReleaseCapture()
+			return true
+		}
+
+	case event.EventKeyPress:
+		// The dialog itself has the focus after a click on its title
+		if data, ok := evt.Data.(event.KeyEvent); ok && d.HandleShortcut(data) {
+			return true
+		}
+	}
+
+	for _, child := range d.ChildComponents() {
+		if child.OnEvent(evt) {
+			return true
+		}
+	}
+	return false
+}
+
+// moveTo moves the dialog, keeping its title bar inside the window.
+func (d *Dialog) moveTo(x, y int32) {
+	if d.overlay != nil {
+		b := d.overlay.Bounds
+		x = max(b.X-d.Bounds.Width+dialogTitleHeight*2, min(x, b.X+b.Width-dialogTitleHeight*2))
+		y = max(b.Y, min(y, b.Y+b.Height-dialogTitleHeight))
+	}
+	d.SetBounds(x, y, d.Bounds.Width, d.Bounds.Height)
+	d.RequestRepaint()
+}
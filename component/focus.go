@@ -0,0 +1,53 @@
+package component
+
+// Focusable is implemented by components that take the keyboard focus
+// with Tab. AcceptsFocus may return false, e.g. while disabled.
+type Focusable interface {
+	AcceptsFocus() bool
+}
+
+// FocusChain returns the visible components under root that accept the
+// focus, in tree order, which is the order Tab moves through them.
+func FocusChain(root Component) []Component {
+	var chain []Component
+	var walk func(c Component)
+	walk = func(c Component) {
+		if c == nil || !c.IsVisible() {
+			return
+		}
+		if f, ok := c.(Focusable); ok && f.AcceptsFocus() {
+			chain = append(chain, c)
+		}
+		if container, ok := c.(Container); ok {
+			for _, child := range container.ChildComponents() {
+				walk(child)
+			}
+		}
+	}
+	walk(root)
+	return chain
+}
+
+// NextFocus returns the component after current in the focus chain of
+// root, or before it if backward is set, wrapping around. It returns the
+// first (or last) component if current is not in the chain, and nil if
+// the chain is empty.
+func NextFocus(root, current Component, backward bool) Component {
+	chain := FocusChain(root)
+	if len(chain) == 0 {
+		return nil
+	}
+	step := 1
+	if backward {
+		step = len(chain) - 1
+	}
+	for i, c := range chain {
+		if c == current {
+			return chain[(i+step)%len(chain)]
+		}
+	}
+	if backward {
+		return chain[len(chain)-1]
+	}
+	return chain[0]
+}
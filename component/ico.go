@@ -0,0 +1,166 @@
+package component
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Windows icons and cursors hold several images; the largest is decoded.
+// Each is a PNG, or a DIB whose height counts the colour rows and the
+// 1-bit transparency mask below them.
+
+func init() {
+	image.RegisterFormat("ico", "\x00\x00\x01\x00", decodeICO, decodeICOConfig)
+	image.RegisterFormat("cur", "\x00\x00\x02\x00", decodeICO, decodeICOConfig)
+}
+
+var errICO = errors.New("ico: invalid format")
+
+type icoEntry struct {
+	width, height int
+	bitCount      int
+	size, offset  uint32
+}
+
+// readICO reads the whole file and returns the entry of its largest image.
+func readICO(r io.Reader) ([]byte, icoEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, icoEntry{}, err
+	}
+	if len(data) < 6 {
+		return nil, icoEntry{}, errICO
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	var best icoEntry
+	for i := 0; i < count; i++ {
+		e := data[min(len(data), 6+16*i):]
+		if len(e) < 16 {
+			return nil, icoEntry{}, errICO
+		}
+		entry := icoEntry{
+			width:    int(e[0]),
+			height:   int(e[1]),
+			bitCount: int(binary.LittleEndian.Uint16(e[6:])),
+			size:     binary.LittleEndian.Uint32(e[8:]),
+			offset:   binary.LittleEndian.Uint32(e[12:]),
+		}
+		if entry.width == 0 {
+			entry.width = 256
+		}
+		if entry.height == 0 {
+			entry.height = 256
+		}
+		if i == 0 || entry.width*entry.height > best.width*best.height ||
+			entry.width*entry.height == best.width*best.height && entry.bitCount > best.bitCount {
+			best = entry
+		}
+	}
+	if count == 0 || uint64(best.offset)+uint64(best.size) > uint64(len(data)) {
+		return nil, icoEntry{}, errICO
+	}
+	return data[best.offset : best.offset+best.size], best, nil
+}
+
+func decodeICOConfig(r io.Reader) (image.Config, error) {
+	_, e, err := readICO(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.NRGBAModel, Width: e.width, Height: e.height}, nil
+}
+
+func decodeICO(r io.Reader) (image.Image, error) {
+	data, _, err := readICO(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("\x89PNG")) {
+		return png.Decode(bytes.NewReader(data))
+	}
+	return decodeDIB(data)
+}
+
+// decodeDIB decodes the bitmap of an icon: 1, 4, 8, 24 or 32 bits per
+// pixel, bottom-up, followed by the AND mask.
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, errICO
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data))
+	w := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	h := int(int32(binary.LittleEndian.Uint32(data[8:]))) / 2 // Colour rows, then mask rows
+	bpp := int(binary.LittleEndian.Uint16(data[14:]))
+	colors := int(binary.LittleEndian.Uint32(data[32:]))
+	if w <= 0 || h <= 0 || w > 1024 || h > 1024 || headerSize < 40 || headerSize > len(data) {
+		return nil, errICO
+	}
+
+	var palette []color.NRGBA
+	pos := headerSize
+	if bpp <= 8 {
+		if colors == 0 {
+			colors = 1 << bpp
+		}
+		for i := 0; i < colors; i++ {
+			if pos+4 > len(data) {
+				return nil, errICO
+			}
+			palette = append(palette, color.NRGBA{data[pos+2], data[pos+1], data[pos], 0xFF})
+			pos += 4
+		}
+	}
+
+	stride := (w*bpp + 31) / 32 * 4
+	maskStride := (w + 31) / 32 * 4
+	if pos+stride*h > len(data) {
+		return nil, errICO
+	}
+	hasMask := pos+stride*h+maskStride*h <= len(data)
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	anyAlpha := false
+	for y := 0; y < h; y++ {
+		row := data[pos+(h-1-y)*stride:]
+		for x := 0; x < w; x++ {
+			var c color.NRGBA
+			switch bpp {
+			case 32:
+				c = color.NRGBA{row[x*4+2], row[x*4+1], row[x*4], row[x*4+3]}
+				anyAlpha = anyAlpha || c.A != 0
+			case 24:
+				c = color.NRGBA{row[x*3+2], row[x*3+1], row[x*3], 0xFF}
+			case 1, 4, 8:
+				bit := x * bpp
+				i := int(row[bit/8]>>(8-bpp-bit%8)) & (1<<bpp - 1)
+				if i < len(palette) {
+					c = palette[i]
+				}
+			default:
+				return nil, errICO
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	// 32-bit images carry alpha, unless all of it is zero; the others use
+	// the mask
+	if (bpp != 32 || !anyAlpha) && hasMask {
+		mask := data[pos+stride*h:]
+		for y := 0; y < h; y++ {
+			row := mask[(h-1-y)*maskStride:]
+			for x := 0; x < w; x++ {
+				c := img.NRGBAAt(x, y)
+				c.A = 0xFF
+				if row[x/8]>>(7-x%8)&1 == 1 {
+					c.A = 0
+				}
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+	return img, nil
+}
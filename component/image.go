@@ -1,22 +1,67 @@
 package component
 
 import (
-	"github.com/jacksalad/goui_v0/event"
-	"github.com/jacksalad/goui_v0/render"
+	"bytes"
 	"image"
-	"image/draw"
-	_ "image/jpeg"
-	_ "image/png"
+	"io"
+	"io/fs"
+	"math"
 	"os"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
 )
 
+// FitMode is how an Image fills its bounds.
+type FitMode int
+
+const (
+	FitNone      FitMode = iota // At its size from the top left, cropped
+	FitFill                     // Stretched to the bounds
+	FitContain                  // As large as fits, keeping its aspect ratio, centred
+	FitCover                    // Covering the bounds, keeping its aspect ratio, centred and cropped
+	FitScaleDown                // As FitContain, but never enlarged
+	FitTile                     // Repeated from the top left
+)
+
+// Image shows a bitmap, an animation or an SVG drawing. Animations (GIF)
+// start playing when loaded, driven by the window timer; SVG drawings are
+// drawn again at the size they are shown at rather than resampled.
 type Image struct {
 	BaseComponent
-	img       image.Image
-	rgba      *image.RGBA
-	FilePath  string
+	FilePath string
+	Fit      FitMode
+	Filter   render.Filter // Used when scaling; Bilinear by default
+	// NineSlice, when set, draws the image as a frame that fills the
+	// bounds, e.g. the skin of a panel: the corners cut by these insets keep
+	// their size, the edges and the centre stretch. Fit is then ignored.
+	NineSlice layout.Insets
+	// Loops is how many times an animation plays, 0 for ever. It is set
+	// from the file.
+	Loops      int
+	OnFinished func() // When an animation stops after its last loop
+
+	img    image.Image
+	svg    *render.SVG
+	frames []imageFrame
+	frame  int
+	pix    *render.Image // Current frame
+
+	playing   bool
+	played    int       // Loops completed
+	nextFrame time.Time // When to show the next frame
+
+	// The frames scaled for the last bounds, until the size or filter
+	// changes
+	scaled       []*render.Image
+	scaledW      int32
+	scaledH      int32
+	scaledFilter render.Filter
 }
 
+// NewImage loads an image file: PNG, JPEG, GIF, BMP, ICO, WebP or SVG.
 func NewImage(filePath string) (*Image, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -24,76 +69,245 @@ func NewImage(filePath string) (*Image, error) {
 	}
 	defer file.Close()
 
-	img, _, err := image.Decode(file)
+	data, err := decodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+	c := newImage(data)
+	c.FilePath = filePath
+	return c, nil
+}
+
+// NewImageFromReader decodes an image read from r.
+func NewImageFromReader(r io.Reader) (*Image, error) {
+	data, err := decodeImage(r)
 	if err != nil {
 		return nil, err
 	}
+	return newImage(data), nil
+}
+
+// NewImageFromBytes decodes an image held in memory, e.g. embedded in the
+// executable.
+func NewImageFromBytes(data []byte) (*Image, error) {
+	return NewImageFromReader(bytes.NewReader(data))
+}
 
-	// Convert to RGBA for easier access
-	bounds := img.Bounds()
-	rgba := image.NewRGBA(bounds)
-	draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+// NewImageFromFS loads an image from a file system, e.g. an embed.FS.
+func NewImageFromFS(fsys fs.FS, path string) (*Image, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-	c := &Image{
-		img:      img,
-		rgba:     rgba,
-		FilePath: filePath,
+	c, err := NewImageFromReader(file)
+	if err != nil {
+		return nil, err
 	}
+	c.FilePath = path
+	return c, nil
+}
+
+func newImage(data *imageData) *Image {
+	c := &Image{Filter: render.Bilinear}
 	c.Visible = true
+	c.setData(data)
 	// Default size to image size
-	c.SetBounds(0, 0, int32(bounds.Dx()), int32(bounds.Dy()))
-	
-	return c, nil
+	c.SetBounds(0, 0, c.pix.Width, c.pix.Height)
+	return c
+}
+
+func (c *Image) setData(data *imageData) {
+	c.Pause()
+	c.img, c.svg, c.frames, c.Loops = data.img, data.svg, data.frames, data.loops
+	c.scaled = nil
+	c.played = 0
+	c.SetFrame(0)
+	c.Play()
+}
+
+// SetImage replaces the image shown with a still image. The bounds are
+// left as they are.
+func (c *Image) SetImage(img image.Image) {
+	c.setData(&imageData{img: img, frames: []imageFrame{{pix: render.ImageFrom(img)}}})
+}
+
+// Source returns the decoded image, the first frame of an animation, or
+// nil for an SVG drawing.
+func (c *Image) Source() image.Image {
+	return c.img
+}
+
+// FrameCount returns the number of frames: more than one for animations.
+func (c *Image) FrameCount() int {
+	return len(c.frames)
+}
+
+// Frame returns the index of the frame shown.
+func (c *Image) Frame() int {
+	return c.frame
+}
+
+// SetFrame shows a frame of an animation.
+func (c *Image) SetFrame(i int) {
+	if i < 0 || i >= len(c.frames) {
+		return
+	}
+	c.frame = i
+	c.pix = c.frames[i].pix
+	c.RequestRepaint()
+}
+
+// Play plays an animation from the frame shown, starting over once it
+// has finished.
+func (c *Image) Play() {
+	if len(c.frames) < 2 || c.playing {
+		return
+	}
+	if c.Loops > 0 && c.played >= c.Loops {
+		c.played = 0
+		c.SetFrame(0)
+	}
+	c.playing = true
+	c.nextFrame = time.Time{}
+	StartAnimation(c)
+}
+
+// Pause stops an animation at the frame shown.
+func (c *Image) Pause() {
+	c.playing = false
+	StopAnimation(c)
+}
+
+// Stop stops an animation and rewinds it to its first frame.
+func (c *Image) Stop() {
+	c.Pause()
+	c.played = 0
+	c.SetFrame(0)
+}
+
+// IsPlaying reports whether an animation is playing.
+func (c *Image) IsPlaying() bool {
+	return c.playing
+}
+
+// Tick advances the animation on the window timer.
+func (c *Image) Tick(now time.Time) bool {
+	if !c.playing {
+		return false
+	}
+	if c.nextFrame.IsZero() || now.Sub(c.nextFrame) > time.Second {
+		// Just started, or the timer stalled: go on from now
+		c.nextFrame = now.Add(c.frames[c.frame].delay)
+		return true
+	}
+	for !now.Before(c.nextFrame) {
+		next := c.frame + 1
+		if next == len(c.frames) {
+			c.played++
+			if c.Loops > 0 && c.played >= c.Loops {
+				c.playing = false
+				if c.OnFinished != nil {
+					c.OnFinished()
+				}
+				return false
+			}
+			next = 0
+		}
+		c.SetFrame(next)
+		c.nextFrame = c.nextFrame.Add(c.frames[c.frame].delay)
+	}
+	return true
+}
+
+// ImageSize returns the size of the image itself, whatever the bounds.
+func (c *Image) ImageSize() (int32, int32) {
+	if c.pix == nil {
+		return 0, 0
+	}
+	return c.pix.Width, c.pix.Height
+}
+
+// fitSize returns the size the image is drawn at in the bounds.
+func (c *Image) fitSize() (int32, int32) {
+	iw, ih := c.pix.Width, c.pix.Height
+	bw, bh := c.Bounds.Width, c.Bounds.Height
+	if iw <= 0 || ih <= 0 {
+		return 0, 0
+	}
+	sx, sy := float64(bw)/float64(iw), float64(bh)/float64(ih)
+	var s float64
+	switch c.Fit {
+	case FitFill:
+		return bw, bh
+	case FitContain:
+		s = math.Min(sx, sy)
+	case FitCover:
+		s = math.Max(sx, sy)
+	case FitScaleDown:
+		s = math.Min(1, math.Min(sx, sy))
+	default:
+		return iw, ih
+	}
+	return max(1, int32(math.Round(float64(iw)*s))), max(1, int32(math.Round(float64(ih)*s)))
+}
+
+// scaledTo returns the frame shown at a size: an SVG drawn at that size,
+// or the bitmap resampled. Each frame is scaled once and cached.
+func (c *Image) scaledTo(w, h int32) *render.Image {
+	if w == c.pix.Width && h == c.pix.Height {
+		return c.pix
+	}
+	if c.scaled == nil || c.scaledW != w || c.scaledH != h || c.scaledFilter != c.Filter {
+		c.scaled = make([]*render.Image, len(c.frames))
+		c.scaledW, c.scaledH, c.scaledFilter = w, h, c.Filter
+	}
+	if c.scaled[c.frame] == nil {
+		if c.svg != nil {
+			c.scaled[c.frame] = c.svg.Render(w, h)
+		} else {
+			c.scaled[c.frame] = c.pix.Resize(w, h, c.Filter)
+		}
+	}
+	return c.scaled[c.frame]
 }
 
 func (c *Image) Render(canvas *render.Canvas) {
-	if !c.Visible || c.rgba == nil {
+	if !c.Visible || c.pix == nil {
 		return
 	}
+	b := c.Bounds
+	canvas.PushClip(b.X, b.Y, b.Width, b.Height)
+	defer canvas.PopClip()
+
+	switch {
+	case c.NineSlice != layout.Insets{}:
+		s := c.NineSlice
+		canvas.DrawNinePatch(&render.NinePatch{Image: c.pix, Left: s.Left, Top: s.Top, Right: s.Right, Bottom: s.Bottom},
+			image.Rect(int(b.X), int(b.Y), int(b.X+b.Width), int(b.Y+b.Height)))
 
-	// Simple copy for now, no scaling
-	// We need to copy pixels from c.rgba to canvas.Buffer
-	// c.rgba is R G B A
-	// canvas.Buffer is B G R A (0xAARRGGBB in Little Endian uint32)
-	
-	// Destination bounds clipped to component bounds
-	dstX := c.Bounds.X
-	dstY := c.Bounds.Y
-	width := c.Bounds.Width
-	height := c.Bounds.Height
-	
-	srcBounds := c.rgba.Bounds()
-	srcW := int32(srcBounds.Dx())
-	srcH := int32(srcBounds.Dy())
-	
-	// Min width/height
-	if width > srcW { width = srcW }
-	if height > srcH { height = srcH }
-	
-	for y := int32(0); y < height; y++ {
-		for x := int32(0); x < width; x++ {
-			// Get RGBA from source
-			// image.RGBA.Pix is standard Go RGBA
-			srcOff := c.rgba.PixOffset(int(x), int(y))
-			r := c.rgba.Pix[srcOff+0]
-			g := c.rgba.Pix[srcOff+1]
-			b := c.rgba.Pix[srcOff+2]
-			a := c.rgba.Pix[srcOff+3] // Alpha unused for now in DIB unless we blend
-			
-			// Pack to uint32 0xAARRGGBB (Little Endian in memory: B G R A)
-			// So color = (A << 24) | (R << 16) | (G << 8) | B
-			// Wait, Windows DIB 32-bit:
-			// "The bitmap has a maximum of 2^32 colors. If the biCompression member of the BITMAPINFOHEADER is BI_RGB, the bmiColors member of BITMAPINFO is NULL. Each DWORD in the bitmap array represents the relative intensities of blue, green, and red for a pixel. The value for blue is in the least significant 8 bits, followed by 8 bits each for green and red. The high byte in each DWORD is not used."
-			// So memory layout: B G R X
-			// Little Endian uint32: 0xXXRRGGBB
-			// So color = (0 << 24) | (R << 16) | (G << 8) | B
-			
-			color := (uint32(a) << 24) | (uint32(r) << 16) | (uint32(g) << 8) | uint32(b)
-			
-			canvas.SetPixel(dstX+x, dstY+y, color)
+	case c.Fit == FitTile:
+		iw, ih := c.pix.Width, c.pix.Height
+		if iw <= 0 || ih <= 0 {
+			break
 		}
+		for y := b.Y; y < b.Y+b.Height; y += ih {
+			for x := b.X; x < b.X+b.Width; x += iw {
+				canvas.DrawImage(c.pix, c.pix.Bounds(), image.Rect(int(x), int(y), int(x+iw), int(y+ih)))
+			}
+		}
+
+	default:
+		w, h := c.fitSize()
+		x, y := b.X, b.Y
+		if c.Fit != FitNone {
+			x, y = b.X+(b.Width-w)/2, b.Y+(b.Height-h)/2
+		}
+		img := c.scaledTo(w, h)
+		canvas.DrawImage(img, img.Bounds(), image.Rect(int(x), int(y), int(x+w), int(y+h)))
 	}
-	
+
 	c.RepaintRequested = false
 }
 
@@ -0,0 +1,122 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"time"
+
+	"github.com/jacksalad/goui_v0/render"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// imageFrame is a frame of an animation, ready to draw.
+type imageFrame struct {
+	pix   *render.Image
+	delay time.Duration // Before the next frame
+}
+
+// imageData is the content of an image file: a bitmap, the frames of an
+// animation, or a vector drawing.
+type imageData struct {
+	img    image.Image // The first frame of animations
+	frames []imageFrame
+	loops  int // Times to play an animation, 0 for ever
+	svg    *render.SVG
+}
+
+// decodeImage decodes PNG, JPEG, GIF (with all its frames), BMP, ICO,
+// WebP and SVG.
+func decodeImage(r io.Reader) (*imageData, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	switch {
+	case bytes.HasPrefix(head, []byte("GIF8")):
+		g, err := gif.DecodeAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return gifFrames(g), nil
+
+	case isSVG(head):
+		svg, err := render.ParseSVG(br)
+		if err != nil {
+			return nil, err
+		}
+		pix := svg.Render(int32(svg.Width+0.5), int32(svg.Height+0.5))
+		return &imageData{frames: []imageFrame{{pix: pix}}, svg: svg}, nil
+	}
+
+	img, _, err := image.Decode(br)
+	if err != nil {
+		return nil, err
+	}
+	return &imageData{img: img, frames: []imageFrame{{pix: render.ImageFrom(img)}}}, nil
+}
+
+// isSVG reports whether the start of a file looks like an SVG document.
+func isSVG(head []byte) bool {
+	head = bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF"))
+	head = bytes.TrimLeft(head, " \t\r\n")
+	return bytes.HasPrefix(head, []byte("<svg")) ||
+		(bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<!--")) ||
+			bytes.HasPrefix(head, []byte("<!DOCTYPE"))) && bytes.Contains(head, []byte("<svg"))
+}
+
+// gifFrames composes the frames of a GIF on its logical screen, applying
+// each frame's disposal before drawing the next.
+func gifFrames(g *gif.GIF) *imageData {
+	w, h := g.Config.Width, g.Config.Height
+	if w == 0 || h == 0 {
+		b := g.Image[0].Bounds()
+		w, h = b.Max.X, b.Max.Y
+	}
+	screen := image.NewNRGBA(image.Rect(0, 0, w, h))
+	data := &imageData{}
+	switch {
+	case g.LoopCount == 0:
+		data.loops = 0 // For ever
+	case g.LoopCount < 0:
+		data.loops = 1
+	default:
+		data.loops = g.LoopCount + 1
+	}
+
+	var saved *image.NRGBA
+	for i, frame := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			saved = image.NewNRGBA(screen.Rect)
+			copy(saved.Pix, screen.Pix)
+		}
+		draw.Draw(screen, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		delay := 100 * time.Millisecond // What browsers use for 0 or 1
+		if i < len(g.Delay) && g.Delay[i] > 1 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		data.frames = append(data.frames, imageFrame{pix: render.ImageFrom(screen), delay: delay})
+		if i == 0 {
+			first := image.NewNRGBA(screen.Rect)
+			copy(first.Pix, screen.Pix)
+			data.img = first
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(screen, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(screen.Pix, saved.Pix)
+		}
+	}
+	return data
+}
@@ -2,19 +2,19 @@ package component
 
 import (
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type Label struct {
 	BaseComponent
 	Text    string
-	FgColor uint32
+	FgColor uint32 // 0 for the theme's text colour
 	Font    *render.Font
 }
 
 func NewLabel(text string) *Label {
 	l := &Label{
-		Text:    text,
-		FgColor: 0xFF000000, // Black
+		Text: text,
 	}
 	l.Visible = true
 	// Default size based on text
@@ -28,10 +28,8 @@ func (l *Label) Render(canvas *render.Canvas) {
 		return
 	}
 	// If label has specific font, set it
-	if l.Font != nil {
-		canvas.SetFont(l.Font)
-	}
-	canvas.DrawText(l.Bounds.X, l.Bounds.Y, l.Text, l.FgColor)
+	canvas.SetFont(l.Font)
+	canvas.DrawText(l.Bounds.X, l.Bounds.Y, l.Text, theme.Or(l.FgColor, theme.Current().Text))
 	l.RepaintRequested = false
 }
 
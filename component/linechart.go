@@ -2,6 +2,7 @@ package component
 
 import (
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type LineChart struct {
@@ -10,8 +11,8 @@ type LineChart struct {
 	MaxPoints int
 	MinY      float64
 	MaxY      float64
-	Color     uint32
-	BgColor   uint32
+	Color     uint32 // 0 for the theme's primary colour
+	BgColor   uint32 // 0 for the theme's background colour
 }
 
 func NewLineChart(width, height int32) *LineChart {
@@ -20,8 +21,6 @@ func NewLineChart(width, height int32) *LineChart {
 		MaxPoints: 100,
 		MinY:      0,
 		MaxY:      100,
-		Color:     0xFF0078D7, // Blue
-		BgColor:   0xFFFFFFFF, // White
 	}
 	l.SetBounds(0, 0, width, height)
 	l.Visible = true
@@ -41,11 +40,13 @@ func (l *LineChart) Render(canvas *render.Canvas) {
 		return
 	}
 
+	t := theme.Current()
+
 	// Background
-	canvas.FillRect(l.Bounds.X, l.Bounds.Y, l.Bounds.Width, l.Bounds.Height, l.BgColor)
+	canvas.FillRect(l.Bounds.X, l.Bounds.Y, l.Bounds.Width, l.Bounds.Height, theme.Or(l.BgColor, t.Background))
 
 	// Border
-	borderColor := uint32(0xFFAAAAAA)
+	borderColor := t.Border
 	canvas.FillRect(l.Bounds.X, l.Bounds.Y, l.Bounds.Width, 1, borderColor)
 	canvas.FillRect(l.Bounds.X, l.Bounds.Y+l.Bounds.Height-1, l.Bounds.Width, 1, borderColor)
 	canvas.FillRect(l.Bounds.X, l.Bounds.Y, 1, l.Bounds.Height, borderColor)
@@ -87,6 +88,6 @@ func (l *LineChart) Render(canvas *render.Canvas) {
 		}
 		y1 := l.Bounds.Height - int32(norm1*float64(l.Bounds.Height))
 
-		canvas.DrawLine(l.Bounds.X+x0, l.Bounds.Y+y0, l.Bounds.X+x1, l.Bounds.Y+y1, l.Color)
+		canvas.DrawLine(l.Bounds.X+x0, l.Bounds.Y+y0, l.Bounds.X+x1, l.Bounds.Y+y1, theme.Or(l.Color, t.Primary))
 	}
 }
@@ -0,0 +1,510 @@
+package component
+
+import (
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// ListDataSource supplies the items of a ListView. Rows are components
+// created by CreateRow and recycled: BindRow is called whenever a row is
+// (re)used to show an item, so only the visible rows ever exist.
+type ListDataSource interface {
+	Count() int
+	CreateRow() Component
+	BindRow(row Component, index int, selected bool)
+}
+
+// ListRowHeights is implemented by data sources whose rows differ in
+// height. Heights are read on Reload.
+type ListRowHeights interface {
+	RowHeight(index int) int32
+}
+
+// ListView is a virtualized, scrollable list of items.
+type ListView struct {
+	BaseComponent
+	Source        ListDataSource
+	RowHeight     int32 // Height of every row unless Source implements ListRowHeights
+	SelectionMode SelectionMode
+	WheelStep     int32
+	Smooth        bool
+	BgColor       uint32 // Colours are the theme's if 0
+	SelectedColor uint32
+	HoverColor    uint32
+
+	ActivateOnClick bool // A single click activates, as in dropdown lists
+
+	OnSelectionChanged func()
+	OnActivate         func(index int) // Double click or Enter
+
+	bar       *ScrollBar
+	metrics   rowMetrics
+	selection itemSelection
+	hover     int
+	isFocused bool
+
+	offset, target float64
+	lastTick       time.Time
+
+	rows   map[int]Component // Bound rows by item index
+	pool   []Component       // Unused rows
+	rebind bool
+}
+
+func NewListView(width, height int32, source ListDataSource) *ListView {
+	l := &ListView{
+		RowHeight: 24,
+		WheelStep: 48,
+		Smooth:    true,
+		bar:       NewScrollBar(Vertical),
+		selection: newItemSelection(),
+		hover:     -1,
+		rows:      make(map[int]Component),
+	}
+	l.bar.OnScroll = func(v float64) {
+		l.offset, l.target = v, v
+		StopAnimation(l)
+	}
+	l.SetBounds(0, 0, width, height)
+	l.Visible = true
+	l.SetSource(source)
+	return l
+}
+
+// SetSource replaces the data source, dropping all rows and the selection.
+func (l *ListView) SetSource(source ListDataSource) {
+	l.Source = source
+	l.rows = make(map[int]Component)
+	l.pool = nil
+	l.selection = newItemSelection()
+	l.offset, l.target = 0, 0
+	l.Reload()
+}
+
+// Reload re-reads the item count and row heights and rebinds the visible
+// rows. Call it after the data changed.
+func (l *ListView) Reload() {
+	count := 0
+	var height func(int) int32
+	if l.Source != nil {
+		count = l.Source.Count()
+		if h, ok := l.Source.(ListRowHeights); ok {
+			height = h.RowHeight
+		}
+	}
+	l.metrics.reset(count, l.RowHeight, height)
+	if l.selection.truncate(count) {
+		l.selectionChanged()
+	}
+	l.rebind = true
+	l.RequestRepaint()
+}
+
+// viewport is the area rows are drawn in: inside the border, left of the
+// scroll bar.
+func (l *ListView) viewport() (x, y, w, h int32) {
+	x, y, w, h = l.Bounds.X+1, l.Bounds.Y+1, l.Bounds.Width-2, l.Bounds.Height-2
+	if l.metrics.total() > int64(h) {
+		w -= scrollBarThickness
+	}
+	return x, y, max(w, 0), max(h, 0)
+}
+
+func (l *ListView) maxOffset() float64 {
+	_, _, _, h := l.viewport()
+	return float64(max(0, l.metrics.total()-int64(h)))
+}
+
+// scrollBy moves the scroll target and reports whether it changed.
+func (l *ListView) scrollBy(d float64) bool {
+	t := clampScroll(l.target+d, l.maxOffset())
+	if t == l.target {
+		return false
+	}
+	l.target = t
+	if !l.Smooth {
+		l.offset = t
+		l.RequestRepaint()
+		return true
+	}
+	l.lastTick = time.Now()
+	StartAnimation(l)
+	return true
+}
+
+func (l *ListView) Tick(now time.Time) bool {
+	var done bool
+	l.offset, done = easeScroll(l.offset, l.target, now.Sub(l.lastTick))
+	l.lastTick = now
+	l.RequestRepaint()
+	return !done
+}
+
+// ScrollToIndex scrolls just enough for item i to become visible.
+func (l *ListView) ScrollToIndex(i int) {
+	if i < 0 || i >= l.metrics.count {
+		return
+	}
+	_, _, _, h := l.viewport()
+	t := ensureSpan(l.target, float64(l.metrics.top(i)), float64(l.metrics.height(i)), float64(h))
+	l.scrollBy(t - l.target)
+}
+
+// Selection
+
+func (l *ListView) IsSelected(i int) bool {
+	return l.selection.set.contains(i)
+}
+
+// SelectedIndex returns the first selected item, or -1.
+func (l *ListView) SelectedIndex() int {
+	return l.selection.set.first()
+}
+
+func (l *ListView) SelectedIndices() []int {
+	return l.selection.set.indices()
+}
+
+// CurrentIndex returns the item with the keyboard focus, or -1.
+func (l *ListView) CurrentIndex() int {
+	return l.selection.current
+}
+
+// Select selects only item i and scrolls to it.
+func (l *ListView) Select(i int) {
+	if i < 0 || i >= l.metrics.count {
+		return
+	}
+	if l.selection.selectAt(SelectionSingle, i, 0) {
+		l.selectionChanged()
+	}
+	l.ScrollToIndex(i)
+}
+
+// SetSelected adds or removes item i in SelectionMulti mode.
+func (l *ListView) SetSelected(i int, selected bool) {
+	if i < 0 || i >= l.metrics.count || l.IsSelected(i) == selected {
+		return
+	}
+	if l.SelectionMode != SelectionMulti {
+		if selected {
+			l.Select(i)
+		} else {
+			l.ClearSelection()
+		}
+		return
+	}
+	l.selection.toggle(i)
+	l.selectionChanged()
+}
+
+func (l *ListView) SelectAll() {
+	if l.SelectionMode != SelectionMulti || l.metrics.count == 0 {
+		return
+	}
+	l.selection.set.clear()
+	l.selection.set.add(0, l.metrics.count)
+	l.selectionChanged()
+}
+
+func (l *ListView) ClearSelection() {
+	if l.selection.set.len() == 0 {
+		return
+	}
+	l.selection.set.clear()
+	l.selectionChanged()
+}
+
+func (l *ListView) selectionChanged() {
+	l.rebind = true
+	l.RequestRepaint()
+	if l.OnSelectionChanged != nil {
+		l.OnSelectionChanged()
+	}
+}
+
+// moveTo applies a click or keyboard move to item i.
+func (l *ListView) moveTo(i int, mods uint32) {
+	if l.SelectionMode == SelectionNone {
+		l.selection.current = i
+	} else if l.selection.selectAt(l.SelectionMode, i, mods) {
+		l.selectionChanged()
+	}
+	l.ScrollToIndex(i)
+	l.RequestRepaint()
+}
+
+func (l *ListView) activate(i int) {
+	if l.OnActivate != nil && i >= 0 && i < l.metrics.count {
+		l.OnActivate(i)
+	}
+}
+
+// Rows
+
+// layoutRows binds and positions a row for every visible item, recycling
+// the rows of items that scrolled out of view.
+func (l *ListView) layoutRows() {
+	if l.Source == nil {
+		return
+	}
+	if n := l.Source.Count(); n != l.metrics.count {
+		l.Reload()
+	}
+	l.offset = clampScroll(l.offset, l.maxOffset())
+	l.target = clampScroll(l.target, l.maxOffset())
+
+	x, y, w, h := l.viewport()
+	first, last := -1, -2
+	if l.metrics.count > 0 {
+		first = l.metrics.indexAt(int64(l.offset))
+		last = l.metrics.indexAt(int64(l.offset) + int64(h) - 1)
+	}
+
+	for i, row := range l.rows {
+		if i < first || i > last || l.rebind {
+			delete(l.rows, i)
+			l.pool = append(l.pool, row)
+		}
+	}
+	l.rebind = false
+
+	for i := first; i <= last; i++ {
+		row, ok := l.rows[i]
+		if !ok {
+			if n := len(l.pool); n > 0 {
+				row = l.pool[n-1]
+				l.pool = l.pool[:n-1]
+			} else {
+				row = l.Source.CreateRow()
+			}
+			l.Source.BindRow(row, i, l.IsSelected(i))
+			l.rows[i] = row
+		}
+		top := y + int32(l.metrics.top(i)-int64(l.offset))
+		if b := row.GetBounds(); b.X != x || b.Y != top || b.Width != w || b.Height != l.metrics.height(i) {
+			row.SetBounds(x, top, w, l.metrics.height(i))
+		}
+	}
+
+	l.bar.Visible = w < l.Bounds.Width-2
+	l.bar.SetBounds(x+w, y, scrollBarThickness, h)
+	l.bar.Max, l.bar.PageSize, l.bar.Value = float64(l.metrics.total()), float64(h), l.offset
+	l.bar.SmallStep = float64(l.WheelStep)
+}
+
+// indexAtPoint returns the item under a window position, or -1.
+func (l *ListView) indexAtPoint(px, py int32) int {
+	x, y, w, h := l.viewport()
+	if px < x || px >= x+w || py < y || py >= y+h {
+		return -1
+	}
+	pos := int64(py-y) + int64(l.offset)
+	if pos >= l.metrics.total() {
+		return -1
+	}
+	return l.metrics.indexAt(pos)
+}
+
+func (l *ListView) Render(canvas *render.Canvas) {
+	if !l.Visible {
+		return
+	}
+	l.layoutRows()
+
+	b := l.Bounds
+	t := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(l.BgColor, t.Background))
+
+	x, y, w, h := l.viewport()
+	canvas.PushClip(x, y, w, h)
+	for i, row := range l.rows {
+		rb := row.GetBounds()
+		if l.IsSelected(i) {
+			canvas.FillRect(rb.X, rb.Y, rb.Width, rb.Height, theme.Or(l.SelectedColor, t.Selection))
+		} else if i == l.hover {
+			canvas.FillRect(rb.X, rb.Y, rb.Width, rb.Height, theme.Or(l.HoverColor, t.Hover))
+		}
+		row.Render(canvas)
+		if l.isFocused && i == l.selection.current {
+			drawRectOutline(canvas, rb.X, rb.Y, rb.Width, rb.Height, t.Focus)
+		}
+	}
+	canvas.PopClip()
+
+	l.bar.Render(canvas)
+
+	borderColor := t.Border
+	if l.isFocused {
+		borderColor = t.Focus
+	}
+	drawRectOutline(canvas, b.X, b.Y, b.Width, b.Height, borderColor)
+	l.RepaintRequested = false
+}
+
+// drawRectOutline draws a 1px rectangle border.
+func drawRectOutline(canvas *render.Canvas, x, y, w, h int32, color uint32) {
+	canvas.FillRect(x, y, w, 1, color)
+	canvas.FillRect(x, y+h-1, w, 1, color)
+	canvas.FillRect(x, y, 1, h, color)
+	canvas.FillRect(x+w-1, y, 1, h, color)
+}
+
+func (l *ListView) OnFocus() {
+	l.isFocused = true
+	l.RequestRepaint()
+}
+
+func (l *ListView) OnBlur() {
+	l.isFocused = false
+	l.RequestRepaint()
+}
+
+func (l *ListView) AcceptsFocus() bool {
+	return true
+}
+
+// forwardToRows passes a mouse event to the visible rows. Pointers outside
+// the viewport are reported just outside all rows, so clipped row parts do
+// not react.
+func (l *ListView) forwardToRows(evt event.Event, data event.MouseEvent) bool {
+	x, y, w, h := l.viewport()
+	if data.X < x || data.X >= x+w || data.Y < y || data.Y >= y+h {
+		data.X, data.Y = x-1, y-1
+		evt.Data = data
+	}
+	for _, row := range l.rows {
+		if row.OnEvent(evt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *ListView) OnEvent(evt event.Event) bool {
+	if !l.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !l.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		return l.scrollBy(-float64(data.Delta) * float64(l.WheelStep) / 120)
+
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !l.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if l.bar.OnEvent(evt) {
+			return true
+		}
+		if i := l.indexAtPoint(data.X, data.Y); i >= 0 {
+			l.moveTo(i, data.Modifiers)
+			if data.Clicks == 2 || l.ActivateOnClick {
+				l.activate(i)
+			}
+		}
+		l.forwardToRows(evt, data)
+		return true
+
+	case event.EventMouseMove, event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if l.bar.OnEvent(evt) {
+			return true
+		}
+		if evt.Type == event.EventMouseMove {
+			if i := l.indexAtPoint(data.X, data.Y); i != l.hover {
+				l.hover = i
+				l.RequestRepaint()
+			}
+		}
+		return l.forwardToRows(evt, data)
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !l.isFocused {
+			return false
+		}
+		_, _, _, h := l.viewport()
+		if i, ok := l.metrics.navigate(data.VirtualKeyCode, l.selection.current, h); ok {
+			mods := data.Modifiers
+			if mods&event.ModCtrl != 0 && mods&event.ModShift == 0 && l.SelectionMode == SelectionMulti {
+				// Ctrl moves the current item without selecting
+				l.selection.current = i
+				l.ScrollToIndex(i)
+				l.RequestRepaint()
+				return true
+			}
+			l.moveTo(i, mods&^event.ModCtrl)
+			return true
+		}
+		switch data.VirtualKeyCode {
+		case 0x20: // Space
+			if i := l.selection.current; i >= 0 {
+				if l.SelectionMode == SelectionMulti && data.Modifiers&event.ModCtrl != 0 {
+					l.SetSelected(i, !l.IsSelected(i))
+				} else {
+					l.moveTo(i, 0)
+				}
+			}
+			return true
+		case 0x0D: // Enter
+			l.activate(l.selection.current)
+			return true
+		case 0x41: // A
+			if data.Modifiers&event.ModCtrl != 0 {
+				l.SelectAll()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StringList is a ListDataSource showing one line of text per item.
+type StringList struct {
+	Items []string
+	Font  *render.Font
+}
+
+func (s *StringList) Count() int {
+	return len(s.Items)
+}
+
+func (s *StringList) CreateRow() Component {
+	r := &textRow{Font: s.Font}
+	r.Visible = true
+	return r
+}
+
+func (s *StringList) BindRow(row Component, index int, selected bool) {
+	row.(*textRow).Text = s.Items[index]
+}
+
+// textRow draws a single line of text, vertically centered.
+type textRow struct {
+	BaseComponent
+	Text    string
+	Font    *render.Font
+	FgColor uint32 // 0 for the theme's text colour
+}
+
+func (r *textRow) Render(canvas *render.Canvas) {
+	if !r.Visible {
+		return
+	}
+	canvas.SetFont(r.Font)
+	_, h := render.MeasureText(r.Text, r.Font)
+	canvas.DrawText(r.Bounds.X+6, r.Bounds.Y+(r.Bounds.Height-h)/2, r.Text, theme.Or(r.FgColor, theme.Current().Text))
+	r.RepaintRequested = false
+}
@@ -0,0 +1,362 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jacksalad/goui_v0/event"
+)
+
+// Common masks for MaskedTextBox.
+const (
+	MaskPhone = "(000) 000-0000"
+	MaskDate  = "00/00/0000"
+	MaskIPv4  = "099.099.099.099"
+)
+
+// maskSlot is one position of a mask: an input slot or a literal.
+type maskSlot struct {
+	kind    rune // '0', '9', 'L' or 'A'; 0 for literals
+	literal rune
+}
+
+func (s maskSlot) accepts(r rune) bool {
+	switch s.kind {
+	case '0', '9':
+		return unicode.IsDigit(r)
+	case 'L':
+		return unicode.IsLetter(r)
+	case 'A':
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+func parseMask(mask string) []maskSlot {
+	var slots []maskSlot
+	runes := []rune(mask)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '0', '9', 'L', 'A':
+			slots = append(slots, maskSlot{kind: r})
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				slots = append(slots, maskSlot{literal: runes[i]})
+			}
+		default:
+			slots = append(slots, maskSlot{literal: r})
+		}
+	}
+	return slots
+}
+
+// MaskedTextBox is a TextBox whose input follows a mask. In the mask, '0'
+// is a required digit, '9' an optional digit, 'L' a letter and 'A' a
+// letter or digit; '\' makes the next character literal and any other
+// character is a literal. Typing fills the slots in order, skipping the
+// literals; typing a literal jumps to it past empty optional slots.
+type MaskedTextBox struct {
+	TextBox
+	Mask       string
+	PromptChar rune // Shown in empty slots, '_' by default
+
+	// Validate checks a complete value and returns an error message, or ""
+	// if it is valid. The message is set as ErrorText.
+	Validate func(value string) string
+
+	slots []maskSlot
+	mask  string // Mask the slots were parsed from
+}
+
+func NewMaskedTextBox(mask string, width int32) *MaskedTextBox {
+	m := &MaskedTextBox{
+		TextBox:    *NewTextBox(width),
+		Mask:       mask,
+		PromptChar: '_',
+	}
+	m.layout()
+	return m
+}
+
+// layout parses the mask if it changed, resetting the text.
+func (m *MaskedTextBox) layout() []maskSlot {
+	if m.mask != m.Mask || m.slots == nil {
+		m.slots = parseMask(m.Mask)
+		m.mask = m.Mask
+		m.Text = ""
+		if m.isFocused {
+			m.Text = m.template()
+		}
+	}
+	return m.slots
+}
+
+// template returns the text of the mask with every slot empty.
+func (m *MaskedTextBox) template() string {
+	runes := make([]rune, len(m.slots))
+	for i, s := range m.slots {
+		runes[i] = s.literal
+		if s.kind != 0 {
+			runes[i] = m.PromptChar
+		}
+	}
+	return string(runes)
+}
+
+// cells returns the text as one rune per slot.
+func (m *MaskedTextBox) cells() []rune {
+	slots := m.layout()
+	runes := []rune(m.Text)
+	if len(runes) != len(slots) {
+		runes = []rune(m.template())
+	}
+	return runes
+}
+
+func (m *MaskedTextBox) filled(cells []rune, i int) bool {
+	return m.slots[i].kind != 0 && cells[i] != m.PromptChar
+}
+
+// Raw returns the typed characters without literals or prompts.
+func (m *MaskedTextBox) Raw() string {
+	if m.Text == "" {
+		return ""
+	}
+	cells := m.cells()
+	var b strings.Builder
+	for i := range m.slots {
+		if m.filled(cells, i) {
+			b.WriteRune(cells[i])
+		}
+	}
+	return b.String()
+}
+
+// Value returns the text with the empty slots removed, e.g. "10.0.0.1"
+// for "10_.0__.0__.1__".
+func (m *MaskedTextBox) Value() string {
+	if m.Text == "" {
+		return ""
+	}
+	cells := m.cells()
+	var b strings.Builder
+	for i, s := range m.slots {
+		if s.kind == 0 || m.filled(cells, i) {
+			b.WriteRune(cells[i])
+		}
+	}
+	return b.String()
+}
+
+// IsComplete reports whether every required slot is filled.
+func (m *MaskedTextBox) IsComplete() bool {
+	cells := m.cells()
+	for i, s := range m.slots {
+		if (s.kind == '0' || s.kind == 'L' || s.kind == 'A') && !m.filled(cells, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetValue fills the slots from value as if it was typed.
+func (m *MaskedTextBox) SetValue(value string) {
+	m.layout()
+	m.setCells([]rune(m.template()), 0)
+	for _, r := range value {
+		m.typeRune(r)
+	}
+	m.ErrorText = ""
+	if !m.isFocused && m.Raw() == "" {
+		m.Text = ""
+	}
+}
+
+func (m *MaskedTextBox) setCells(cells []rune, cursor int) {
+	m.Text = string(cells)
+	m.cursorPos = cursor
+	m.selStart = cursor
+	m.RequestRepaint()
+}
+
+// check sets ErrorText for the current text. Incomplete values are only
+// reported when final is set, so the user is not told off while typing.
+func (m *MaskedTextBox) check(final bool) {
+	m.ErrorText = ""
+	switch {
+	case m.Raw() == "":
+	case !m.IsComplete():
+		if final {
+			m.ErrorText = "Incomplete"
+		}
+	case m.Validate != nil:
+		m.ErrorText = m.Validate(m.Value())
+	}
+	m.RequestRepaint()
+}
+
+func (m *MaskedTextBox) OnFocus() {
+	m.TextBox.OnFocus()
+	m.layout()
+	if m.Text == "" {
+		m.Text = m.template()
+		cursor := 0
+		for cursor < len(m.slots) && m.slots[cursor].kind == 0 {
+			cursor++
+		}
+		m.cursorPos, m.selStart = cursor, cursor
+	}
+}
+
+func (m *MaskedTextBox) OnBlur() {
+	m.TextBox.OnBlur()
+	if m.Raw() == "" {
+		m.Text = "" // Show the placeholder again
+	}
+	m.check(true)
+}
+
+// selection returns the selected range, which is empty without one.
+func (m *MaskedTextBox) selection() (int, int) {
+	if m.selStart < 0 {
+		return m.cursorPos, m.cursorPos
+	}
+	return min(m.selStart, m.cursorPos), max(m.selStart, m.cursorPos)
+}
+
+// clearRange empties the input slots in [start, end).
+func (m *MaskedTextBox) clearRange(cells []rune, start, end int) {
+	for i := start; i < end && i < len(cells); i++ {
+		if m.slots[i].kind != 0 {
+			cells[i] = m.PromptChar
+		}
+	}
+}
+
+// typeRune puts a typed rune at the cursor and reports whether it fit.
+func (m *MaskedTextBox) typeRune(r rune) bool {
+	cells := m.cells()
+	start, end := m.selection()
+	m.clearRange(cells, start, end)
+
+	for pos := start; pos < len(m.slots); pos++ {
+		s := m.slots[pos]
+		if s.kind == 0 {
+			if s.literal == r {
+				m.setCells(cells, pos+1)
+				return true
+			}
+			continue
+		}
+		if s.accepts(r) {
+			cells[pos] = r
+			m.setCells(cells, pos+1)
+			return true
+		}
+		// Only empty optional slots may be skipped to reach a literal
+		if s.kind != '9' || m.filled(cells, pos) {
+			break
+		}
+	}
+	if start != end {
+		m.setCells(cells, start)
+		return true
+	}
+	return false
+}
+
+// erase clears the selection, or the slot before (backward) or at the
+// cursor.
+func (m *MaskedTextBox) erase(backward bool) {
+	cells := m.cells()
+	start, end := m.selection()
+	switch {
+	case start != end:
+	case backward:
+		for start > 0 && m.slots[start-1].kind == 0 {
+			start--
+		}
+		if start == 0 {
+			return
+		}
+		start--
+		end = start + 1
+	default:
+		for start < len(m.slots) && m.slots[start].kind == 0 {
+			start++
+		}
+		end = start + 1
+	}
+	m.clearRange(cells, start, end)
+	m.setCells(cells, start)
+}
+
+func (m *MaskedTextBox) OnEvent(evt event.Event) bool {
+	if !m.Visible {
+		return false
+	}
+	m.layout()
+
+	switch evt.Type {
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !m.isFocused || m.ReadOnly {
+			break
+		}
+		switch data.VirtualKeyCode {
+		case 0x08, 0x2E: // Backspace, Delete
+			before := m.Text
+			m.erase(data.VirtualKeyCode == 0x08)
+			if m.Text != before {
+				m.check(false)
+				m.changed()
+			}
+			return true
+		}
+
+	case event.EventChar:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !m.isFocused || m.ReadOnly || data.Rune < 32 {
+			break
+		}
+		if m.InputFilter != nil && !m.InputFilter(data.Rune) {
+			return true
+		}
+		before := m.Text
+		m.typeRune(data.Rune)
+		if m.Text != before {
+			m.check(false)
+			m.changed()
+		}
+		return true
+	}
+	return m.TextBox.OnEvent(evt)
+}
+
+// ValidateIPv4 checks a value typed in a MaskIPv4 box.
+func ValidateIPv4(value string) string {
+	parts := strings.Split(value, ".")
+	if len(parts) != 4 {
+		return "Invalid address"
+	}
+	for _, p := range parts {
+		if n, err := strconv.Atoi(p); err != nil || n > 255 {
+			return "Each part must be between 0 and 255"
+		}
+	}
+	return ""
+}
+
+// DateValidator returns a validator for dates written with the given
+// time layout, e.g. "01/02/2006" for a MaskDate box.
+func DateValidator(layout string) func(string) string {
+	return func(value string) string {
+		if _, err := time.Parse(layout, value); err != nil {
+			return "Invalid date"
+		}
+		return ""
+	}
+}
@@ -0,0 +1,530 @@
+package component
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	menuItemHeight      = 24
+	menuSeparatorHeight = 7
+	menuPadding         = 3  // Above the first and below the last item
+	menuIconColumn      = 28 // Check marks and icons
+	menuArrowColumn     = 20 // Submenu arrows
+	menuAccelGap        = 24 // Between text and accelerator
+)
+
+// MenuItem is an entry of a Menu. An "&" in Text marks the next letter as
+// the mnemonic, which is underlined and activates the item when typed
+// while the menu is open ("&&" is a literal "&").
+type MenuItem struct {
+	Text        string
+	Icon        *Image // Drawn in the check column, cropped to 16x16
+	Accelerator string // Shown right-aligned, e.g. "Ctrl+S"; see MenuBar
+	Checkable   bool   // Clicking toggles Checked
+	Checked     bool
+	RadioGroup  string // Items of a menu sharing a group are checked exclusively
+	Separator   bool
+	Disabled    bool
+	Submenu     *Menu
+
+	OnClick func(item *MenuItem)
+}
+
+func NewMenuItem(text string, onClick func(item *MenuItem)) *MenuItem {
+	return &MenuItem{Text: text, OnClick: onClick}
+}
+
+func NewMenuSeparator() *MenuItem {
+	return &MenuItem{Separator: true}
+}
+
+func NewSubmenuItem(text string, submenu *Menu) *MenuItem {
+	return &MenuItem{Text: text, Submenu: submenu}
+}
+
+// selectable reports whether the item can be highlighted.
+func (item *MenuItem) selectable() bool {
+	return !item.Separator && !item.Disabled
+}
+
+// parseMnemonic strips the "&" markers from text and returns the lower
+// case mnemonic and its rune index in the result, or -1.
+func parseMnemonic(text string) (display string, mnemonic rune, index int) {
+	var b strings.Builder
+	index = -1
+	runes := []rune(text)
+	n := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '&' && i+1 < len(runes) {
+			i++
+			if runes[i] != '&' && index < 0 {
+				mnemonic, index = unicode.ToLower(runes[i]), n
+			}
+		}
+		b.WriteRune(runes[i])
+		n++
+	}
+	return b.String(), mnemonic, index
+}
+
+// drawMnemonicText draws text with its mnemonic underlined.
+func drawMnemonicText(canvas *render.Canvas, x, y int32, text string, font *render.Font, color uint32) {
+	display, _, index := parseMnemonic(text)
+	canvas.DrawText(x, y, display, color)
+	if index < 0 {
+		return
+	}
+	runes := []rune(display)
+	before, _ := render.MeasureText(string(runes[:index]), font)
+	w, h := render.MeasureText(string(runes[index]), font)
+	canvas.FillRect(x+before, y+h-1, w, 1, color)
+}
+
+// ContextMenuOwner is implemented by components with a context menu. The
+// window shows it on right click or the context menu key. BaseComponent
+// implements it through its ContextMenu field.
+type ContextMenuOwner interface {
+	GetContextMenu() *Menu
+}
+
+// Menu is a popup list of commands, used as a context menu, a submenu or
+// a menu of a MenuBar.
+type Menu struct {
+	BaseComponent
+	Title string // Shown by a MenuBar
+	Items []*MenuItem
+	Font  *render.Font
+
+	OnClose func()
+
+	popup     *Popup
+	parent    *Menu    // For submenus
+	bar       *MenuBar // For menus of a menu bar
+	highlight int
+	tops      []int32 // Item offsets from the top of the menu
+}
+
+func NewMenu(items ...*MenuItem) *Menu {
+	m := &Menu{Items: items, highlight: -1}
+	m.popup = &Popup{Content: m, Light: true, KeepFocus: true, CaptureKeys: true, Shadow: true}
+	m.popup.OnClose = m.closed
+	m.Visible = true
+	return m
+}
+
+// Add appends an item and returns it.
+func (m *Menu) Add(item *MenuItem) *MenuItem {
+	m.Items = append(m.Items, item)
+	return item
+}
+
+// IsOpen reports whether the menu is shown.
+func (m *Menu) IsOpen() bool {
+	return m.popup.IsOpen()
+}
+
+// Show opens the menu with its top left corner at a window position, as a
+// context menu. It is moved as needed to fit in the window.
+func (m *Menu) Show(x, y int32) {
+	m.parent, m.bar = nil, nil
+	m.popup.Owner = nil
+	m.open(layout.Rect{X: x, Y: y}, false)
+}
+
+// Close closes the menu and its submenus.
+func (m *Menu) Close() {
+	m.popup.Close()
+}
+
+// root returns the menu at the top of a submenu chain.
+func (m *Menu) root() *Menu {
+	for m.parent != nil {
+		m = m.parent
+	}
+	return m
+}
+
+// open shows the menu below anchor, or beside it for submenus.
+func (m *Menu) open(anchor layout.Rect, beside bool) {
+	overlay := ActiveOverlay()
+	if overlay == nil {
+		return
+	}
+	w, h := m.GetPreferredSize()
+	if !beside {
+		overlay.ShowAt(m.popup, anchor, w, h)
+	} else {
+		b := overlay.Bounds
+		x := anchor.X + anchor.Width
+		if x+w > b.X+b.Width && anchor.X-w >= b.X {
+			x = anchor.X - w
+		}
+		x = max(b.X, min(x, b.X+b.Width-w))
+		y := max(b.Y, min(anchor.Y-menuPadding, b.Y+b.Height-h))
+		m.SetBounds(x, y, w, h)
+		overlay.Show(m.popup)
+	}
+	m.highlight = -1
+	m.RequestRepaint()
+}
+
+func (m *Menu) closed() {
+	m.highlight = -1
+	if m.bar != nil {
+		m.bar.menuClosed(m)
+	}
+	if m.OnClose != nil {
+		m.OnClose()
+	}
+}
+
+// openSub returns the submenu that is open, if any.
+func (m *Menu) openSub() *Menu {
+	for _, item := range m.Items {
+		if item.Submenu != nil && item.Submenu.parent == m && item.Submenu.IsOpen() {
+			return item.Submenu
+		}
+	}
+	return nil
+}
+
+// openSubmenu opens the submenu of item i, closing any other.
+func (m *Menu) openSubmenu(i int, keyboard bool) {
+	sub := m.Items[i].Submenu
+	if open := m.openSub(); open != nil && open != sub {
+		open.Close()
+	}
+	if !sub.IsOpen() {
+		sub.parent, sub.bar = m, nil
+		sub.Font = m.Font
+		sub.popup.Owner = m
+		sub.open(m.itemRect(i), true)
+	}
+	if keyboard {
+		sub.moveHighlight(0, 1)
+	}
+}
+
+// Layout
+
+func (m *Menu) itemHeight(item *MenuItem) int32 {
+	if item.Separator {
+		return menuSeparatorHeight
+	}
+	return menuItemHeight
+}
+
+func (m *Menu) GetPreferredSize() (int32, int32) {
+	textW, accelW := int32(0), int32(0)
+	h := int32(0)
+	m.tops = m.tops[:0]
+	for _, item := range m.Items {
+		m.tops = append(m.tops, h)
+		h += m.itemHeight(item)
+		if item.Separator {
+			continue
+		}
+		display, _, _ := parseMnemonic(item.Text)
+		w, _ := render.MeasureText(display, m.Font)
+		textW = max(textW, w)
+		if item.Accelerator != "" {
+			w, _ := render.MeasureText(item.Accelerator, m.Font)
+			accelW = max(accelW, w+menuAccelGap)
+		}
+	}
+	return max(menuIconColumn+textW+accelW+menuArrowColumn+2, 120), h + 2*menuPadding
+}
+
+// itemRect returns the window bounds of item i.
+func (m *Menu) itemRect(i int) layout.Rect {
+	if len(m.tops) != len(m.Items) {
+		m.GetPreferredSize()
+	}
+	return layout.Rect{
+		X:      m.Bounds.X + 1,
+		Y:      m.Bounds.Y + menuPadding + m.tops[i],
+		Width:  m.Bounds.Width - 2,
+		Height: m.itemHeight(m.Items[i]),
+	}
+}
+
+// itemAt returns the item under a window position, or -1.
+func (m *Menu) itemAt(x, y int32) int {
+	if !m.Bounds.Contains(x, y) {
+		return -1
+	}
+	for i := range m.Items {
+		if m.itemRect(i).Contains(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Rendering
+
+func (m *Menu) Render(canvas *render.Canvas) {
+	if !m.Visible {
+		return
+	}
+	b := m.Bounds
+	t := theme.Current()
+	drawFrame(canvas, b.X, b.Y, b.Width, b.Height, t.Background, t.Border)
+	canvas.SetFont(m.Font)
+
+	for i, item := range m.Items {
+		r := m.itemRect(i)
+		if item.Separator {
+			canvas.FillRect(r.X+menuIconColumn, r.Y+r.Height/2, r.Width-menuIconColumn-4, 1, t.Divider)
+			continue
+		}
+		if i == m.highlight {
+			canvas.FillRect(r.X+2, r.Y, r.Width-4, r.Height, t.Selection)
+		}
+
+		color := t.Text
+		if item.Disabled {
+			color = t.TextDisabled
+		}
+		cx, cy := r.X+menuIconColumn/2, r.Y+r.Height/2
+		switch {
+		case item.Checked && item.RadioGroup != "":
+			fillCircle(canvas, cx, cy, 3, color)
+		case item.Checked:
+			drawCheckMark(canvas, cx, cy, color)
+		case item.Icon != nil:
+			iw, ih := item.Icon.ImageSize()
+			iw, ih = min(iw, 16), min(ih, 16)
+			item.Icon.SetBounds(cx-iw/2, cy-ih/2, iw, ih)
+			item.Icon.Render(canvas)
+		}
+
+		_, th := render.MeasureText("M", m.Font)
+		ty := r.Y + (r.Height-th)/2
+		drawMnemonicText(canvas, r.X+menuIconColumn, ty, item.Text, m.Font, color)
+		if item.Accelerator != "" {
+			aw, _ := render.MeasureText(item.Accelerator, m.Font)
+			canvas.DrawText(r.X+r.Width-menuArrowColumn-aw, ty, item.Accelerator, color)
+		}
+		if item.Submenu != nil {
+			drawArrowGlyph(canvas, r.X+r.Width-menuArrowColumn, r.Y, menuArrowColumn, r.Height, Horizontal, true, color)
+		}
+	}
+	m.RepaintRequested = false
+}
+
+// drawCheckMark draws a small tick centered on cx, cy.
+func drawCheckMark(canvas *render.Canvas, cx, cy int32, color uint32) {
+	// Short stroke down to the right, then a long one up to the right
+	for i := int32(0); i < 3; i++ {
+		canvas.FillRect(cx-4+i, cy+i-1, 1, 3, color)
+	}
+	for i := int32(0); i < 6; i++ {
+		canvas.FillRect(cx-1+i, cy+1-i, 1, 3, color)
+	}
+}
+
+// fillCircle fills a circle of radius r centered on cx, cy.
+func fillCircle(canvas *render.Canvas, cx, cy, r int32, color uint32) {
+	for dy := -r; dy <= r; dy++ {
+		dx := int32(0)
+		for (dx+1)*(dx+1)+dy*dy <= r*r {
+			dx++
+		}
+		canvas.FillRect(cx-dx, cy+dy, 2*dx+1, 1, color)
+	}
+}
+
+// Interaction
+
+// moveHighlight highlights the next selectable item from start in
+// direction dir (+1 or -1), wrapping around.
+func (m *Menu) moveHighlight(start, dir int) {
+	n := len(m.Items)
+	for k := 0; k < n; k++ {
+		i := ((start+k*dir)%n + n) % n
+		if m.Items[i].selectable() {
+			m.highlight = i
+			m.RequestRepaint()
+			return
+		}
+	}
+}
+
+// activate runs item i, or opens its submenu.
+func (m *Menu) activate(i int, keyboard bool) {
+	item := m.Items[i]
+	if !item.selectable() {
+		return
+	}
+	if item.Submenu != nil {
+		m.openSubmenu(i, keyboard)
+		return
+	}
+	m.root().Close()
+	invokeMenuItem(m, item)
+}
+
+// invokeMenuItem updates the check state of item and calls OnClick.
+func invokeMenuItem(m *Menu, item *MenuItem) {
+	if item.RadioGroup != "" {
+		for _, other := range m.Items {
+			if other.RadioGroup == item.RadioGroup {
+				other.Checked = false
+			}
+		}
+		item.Checked = true
+	} else if item.Checkable {
+		item.Checked = !item.Checked
+	}
+	if item.OnClick != nil {
+		item.OnClick(item)
+	}
+}
+
+// mnemonic handles a typed character: a unique match is activated,
+// several matches are cycled through.
+func (m *Menu) mnemonic(r rune) {
+	r = unicode.ToLower(r)
+	var matches []int
+	for i, item := range m.Items {
+		if _, mn, _ := parseMnemonic(item.Text); mn == r && item.selectable() {
+			matches = append(matches, i)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		m.highlight = matches[0]
+		m.activate(matches[0], true)
+	case len(matches) > 1:
+		next := matches[0]
+		for _, i := range matches {
+			if i > m.highlight {
+				next = i
+				break
+			}
+		}
+		m.highlight = next
+		m.RequestRepaint()
+	}
+}
+
+func (m *Menu) OnEvent(evt event.Event) bool {
+	if !m.Visible || !m.IsOpen() {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		i := m.itemAt(data.X, data.Y)
+		if i < 0 {
+			// Keep the item of an open submenu highlighted
+			if m.openSub() == nil && m.highlight >= 0 {
+				m.highlight = -1
+				m.RequestRepaint()
+			}
+			return m.Bounds.Contains(data.X, data.Y)
+		}
+		if i != m.highlight {
+			m.highlight = -1
+			if m.Items[i].selectable() {
+				m.highlight = i
+			}
+			if sub := m.openSub(); sub != nil && sub != m.Items[i].Submenu {
+				sub.Close()
+			}
+			if m.highlight >= 0 && m.Items[i].Submenu != nil {
+				m.openSubmenu(i, false)
+			}
+			m.RequestRepaint()
+		}
+		return true
+
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !m.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if i := m.itemAt(data.X, data.Y); i >= 0 && m.Items[i].Submenu != nil {
+			m.activate(i, false)
+		}
+		return true
+
+	case event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !m.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if i := m.itemAt(data.X, data.Y); i >= 0 && m.Items[i].Submenu == nil {
+			m.activate(i, false)
+		}
+		return true
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok {
+			return false
+		}
+		switch data.VirtualKeyCode {
+		case 0x1B: // Escape: left to the overlay
+			return false
+		case 0x12: // Alt closes all menus
+			m.root().Close()
+		case 0x28: // Down
+			m.moveHighlight(m.highlight+1, 1)
+		case 0x26: // Up
+			if m.highlight < 0 {
+				m.moveHighlight(len(m.Items)-1, -1)
+			} else {
+				m.moveHighlight(m.highlight-1, -1)
+			}
+		case 0x24: // Home
+			m.moveHighlight(0, 1)
+		case 0x23: // End
+			m.moveHighlight(len(m.Items)-1, -1)
+		case 0x27: // Right
+			if m.highlight >= 0 && m.Items[m.highlight].Submenu != nil {
+				m.activate(m.highlight, true)
+			} else if r := m.root(); r.bar != nil {
+				r.bar.step(1)
+			}
+		case 0x25: // Left
+			if m.parent != nil {
+				m.Close()
+			} else if m.bar != nil {
+				m.bar.step(-1)
+			}
+		case 0x0D: // Enter
+			if m.highlight >= 0 {
+				m.activate(m.highlight, true)
+			}
+		}
+		// Keys do not reach the focused component while a menu is open
+		return true
+
+	case event.EventChar:
+		if data, ok := evt.Data.(event.KeyEvent); ok && data.Rune > 32 {
+			m.mnemonic(data.Rune)
+		}
+		return true
+
+	case event.EventKeyRelease:
+		return true
+	}
+	return false
+}
+
+// GetContextMenu returns the component's context menu, or nil.
+func (b *BaseComponent) GetContextMenu() *Menu {
+	return b.ContextMenu
+}
@@ -0,0 +1,349 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const menuBarTitlePadding = 10
+
+// MenuBar shows a row of menu titles at the top of a window (see
+// Window.SetMenuBar). Menus open on click or with Alt plus the mnemonic of
+// their title; pressing and releasing Alt, or F10, enters keyboard mode in
+// which the arrow keys move between the titles.
+//
+// The window also passes it the keys the focused component did not
+// handle, so item accelerators such as "Ctrl+S" trigger their items.
+type MenuBar struct {
+	BaseComponent
+	Menus   []*Menu
+	Font    *render.Font
+	BgColor uint32 // 0 for the theme's surface colour
+
+	highlight  int   // Title highlighted by hover or keyboard mode
+	open       *Menu // Menu that is shown
+	active     bool  // Keyboard mode
+	switching  bool  // Opening another menu, closing the current one
+	altPressed bool  // Alt is down and no other key was pressed since
+}
+
+func NewMenuBar() *MenuBar {
+	b := &MenuBar{highlight: -1}
+	b.Visible = true
+	return b
+}
+
+// Add appends a menu with the given title, which may contain a mnemonic
+// such as "&File", and returns the menu.
+func (b *MenuBar) Add(title string, menu *Menu) *Menu {
+	menu.Title = title
+	b.Menus = append(b.Menus, menu)
+	b.RequestRepaint()
+	return menu
+}
+
+func (b *MenuBar) GetPreferredSize() (int32, int32) {
+	_, h := render.MeasureText("M", b.Font)
+	return b.Bounds.Width, h + 10
+}
+
+// titleRect returns the window bounds of the title of menu i.
+func (b *MenuBar) titleRect(i int) layout.Rect {
+	x := b.Bounds.X + 2
+	for j, m := range b.Menus {
+		display, _, _ := parseMnemonic(m.Title)
+		w, _ := render.MeasureText(display, b.Font)
+		w += 2 * menuBarTitlePadding
+		if j == i {
+			return layout.Rect{X: x, Y: b.Bounds.Y, Width: w, Height: b.Bounds.Height}
+		}
+		x += w
+	}
+	return layout.Rect{}
+}
+
+// titleAt returns the menu whose title is under a window position, or -1.
+func (b *MenuBar) titleAt(x, y int32) int {
+	if !b.Bounds.Contains(x, y) {
+		return -1
+	}
+	for i := range b.Menus {
+		if b.titleRect(i).Contains(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *MenuBar) Render(canvas *render.Canvas) {
+	if !b.Visible {
+		return
+	}
+	r := b.Bounds
+	th := theme.Current()
+	canvas.FillRect(r.X, r.Y, r.Width, r.Height, theme.Or(b.BgColor, th.Surface))
+	canvas.FillRect(r.X, r.Y+r.Height-1, r.Width, 1, th.Divider)
+	canvas.SetFont(b.Font)
+
+	_, textH := render.MeasureText("M", b.Font)
+	for i, m := range b.Menus {
+		t := b.titleRect(i)
+		if m == b.open {
+			canvas.FillRect(t.X, t.Y+1, t.Width, t.Height-2, th.Selection)
+		} else if i == b.highlight {
+			canvas.FillRect(t.X, t.Y+1, t.Width, t.Height-2, th.Hover)
+		}
+		drawMnemonicText(canvas, t.X+menuBarTitlePadding, t.Y+(t.Height-textH)/2, m.Title, b.Font, th.Text)
+	}
+	b.RepaintRequested = false
+}
+
+// openMenu shows menu i, closing the one that is open.
+func (b *MenuBar) openMenu(i int, keyboard bool) {
+	m := b.Menus[i]
+	if b.open != nil && b.open != m {
+		b.switching = true
+		b.open.Close()
+		b.switching = false
+	}
+	b.highlight = i
+	if !m.IsOpen() {
+		m.parent, m.bar = nil, b
+		m.Font = b.Font
+		m.popup.Owner = b
+		b.open = m
+		m.open(b.titleRect(i), false)
+	}
+	if keyboard {
+		m.moveHighlight(0, 1)
+	}
+	b.RequestRepaint()
+}
+
+// step opens the menu dir titles away from the open one.
+func (b *MenuBar) step(dir int) {
+	if n := len(b.Menus); n > 0 {
+		b.openMenu(((b.highlight+dir)%n+n)%n, true)
+	}
+}
+
+// menuClosed is called by a menu of the bar when it closes.
+func (b *MenuBar) menuClosed(m *Menu) {
+	if b.open != m {
+		return
+	}
+	b.open = nil
+	if !b.switching {
+		b.active = false
+		b.highlight = -1
+	}
+	b.RequestRepaint()
+}
+
+// mnemonicMenu returns the menu whose title mnemonic is r, or -1.
+func (b *MenuBar) mnemonicMenu(r rune) int {
+	r = unicode.ToLower(r)
+	for i, m := range b.Menus {
+		if _, mn, _ := parseMnemonic(m.Title); mn == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *MenuBar) setActive(active bool) {
+	b.active = active
+	b.highlight = -1
+	if active && len(b.Menus) > 0 {
+		b.highlight = 0
+	}
+	b.RequestRepaint()
+}
+
+func (b *MenuBar) OnEvent(evt event.Event) bool {
+	if !b.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		i := b.titleAt(data.X, data.Y)
+		if b.open != nil {
+			// Sliding over the titles switches menus
+			if i >= 0 && b.Menus[i] != b.open {
+				b.openMenu(i, false)
+			}
+		} else if !b.active && i != b.highlight {
+			b.highlight = i
+			b.RequestRepaint()
+		}
+		return b.Bounds.Contains(data.X, data.Y)
+
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if !b.Bounds.Contains(data.X, data.Y) {
+			if b.active {
+				b.setActive(false)
+			}
+			return false
+		}
+		if i := b.titleAt(data.X, data.Y); i >= 0 {
+			if b.Menus[i] == b.open {
+				b.open.Close()
+			} else {
+				b.openMenu(i, false)
+			}
+		}
+		return true
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok {
+			return false
+		}
+		key := data.VirtualKeyCode
+		if key == 0x12 { // Alt
+			b.altPressed = true
+			return false
+		}
+		b.altPressed = false
+
+		if data.Modifiers&event.ModAlt != 0 && data.Modifiers&event.ModCtrl == 0 {
+			if i := b.mnemonicMenu(rune(key)); i >= 0 {
+				b.openMenu(i, true)
+				return true
+			}
+		}
+		if key == 0x79 && data.Modifiers&event.ModShift == 0 { // F10
+			b.setActive(!b.active)
+			return true
+		}
+		if !b.active || len(b.Menus) == 0 {
+			return false
+		}
+		switch key {
+		case 0x25: // Left
+			b.highlight = ((b.highlight-1)%len(b.Menus) + len(b.Menus)) % len(b.Menus)
+			b.RequestRepaint()
+		case 0x27: // Right
+			b.highlight = (b.highlight + 1) % len(b.Menus)
+			b.RequestRepaint()
+		case 0x28, 0x26, 0x0D: // Down, Up, Enter
+			b.openMenu(b.highlight, true)
+		case 0x1B: // Escape
+			b.setActive(false)
+		}
+		return true
+
+	case event.EventKeyRelease:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || data.VirtualKeyCode != 0x12 || !b.altPressed {
+			return false
+		}
+		// Alt pressed and released on its own
+		b.altPressed = false
+		b.setActive(!b.active)
+		return true
+
+	case event.EventChar:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !b.active {
+			return false
+		}
+		if i := b.mnemonicMenu(data.Rune); i >= 0 {
+			b.openMenu(i, true)
+		}
+		return true
+	}
+	return false
+}
+
+// HandleAccelerator triggers the enabled menu item whose Accelerator
+// matches a key press, and reports whether there was one.
+func (b *MenuBar) HandleAccelerator(data event.KeyEvent) bool {
+	mods := data.Modifiers & (event.ModShift | event.ModCtrl | event.ModAlt)
+	for _, m := range b.Menus {
+		if b.accelerate(m, mods, data.VirtualKeyCode) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *MenuBar) accelerate(m *Menu, mods, key uint32) bool {
+	for _, item := range m.Items {
+		if !item.selectable() {
+			continue
+		}
+		if item.Submenu != nil {
+			if b.accelerate(item.Submenu, mods, key) {
+				return true
+			}
+			continue
+		}
+		if am, ak, ok := parseAccelerator(item.Accelerator); ok && am == mods && ak == key {
+			invokeMenuItem(m, item)
+			b.RequestRepaint()
+			return true
+		}
+	}
+	return false
+}
+
+// Virtual key codes of named accelerator keys.
+var acceleratorKeys = map[string]uint32{
+	"backspace": 0x08, "tab": 0x09, "enter": 0x0D, "esc": 0x1B, "escape": 0x1B,
+	"space": 0x20, "pgup": 0x21, "pageup": 0x21, "pgdn": 0x22, "pagedown": 0x22,
+	"end": 0x23, "home": 0x24, "left": 0x25, "up": 0x26, "right": 0x27, "down": 0x28,
+	"ins": 0x2D, "insert": 0x2D, "del": 0x2E, "delete": 0x2E,
+	"plus": 0xBB, "minus": 0xBD,
+}
+
+// parseAccelerator parses accelerator text such as "Ctrl+Shift+S", "F5"
+// or "Alt+Enter" into modifiers and a virtual key code.
+func parseAccelerator(s string) (mods, key uint32, ok bool) {
+	if s == "" {
+		return 0, 0, false
+	}
+	parts := strings.Split(s, "+")
+	for _, p := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "ctrl", "control":
+			mods |= event.ModCtrl
+		case "shift":
+			mods |= event.ModShift
+		case "alt":
+			mods |= event.ModAlt
+		default:
+			return 0, 0, false
+		}
+	}
+
+	name := strings.TrimSpace(parts[len(parts)-1])
+	lower := strings.ToLower(name)
+	if k, found := acceleratorKeys[lower]; found {
+		return mods, k, true
+	}
+	if r := []rune(name); len(r) == 1 && r[0] < 0x80 && (unicode.IsLetter(r[0]) || unicode.IsDigit(r[0])) {
+		return mods, uint32(unicode.ToUpper(r[0])), true
+	}
+	if len(lower) > 1 && lower[0] == 'f' {
+		if n, err := strconv.Atoi(lower[1:]); err == nil && n >= 1 && n <= 24 {
+			return mods, 0x70 + uint32(n-1), true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,306 @@
+package component
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const numberBoxButtonWidth = 18
+
+// NumberFormat holds the separators numbers are written with.
+type NumberFormat struct {
+	Decimal rune // Decimal separator, e.g. '.' or ','
+	Group   rune // Thousands separator, or 0 for none
+}
+
+// InvariantNumberFormat writes numbers the way Go does, with a comma for
+// the thousands.
+var InvariantNumberFormat = NumberFormat{Decimal: '.', Group: ','}
+
+// DefaultNumberFormat is used by number boxes without a Format of their
+// own. The first window created sets it from the user's locale, unless it
+// was changed before.
+var DefaultNumberFormat = InvariantNumberFormat
+
+// FormatNumber writes v with the given number of decimals and separators.
+func (f NumberFormat) FormatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 && f.Group != 0 {
+			b.WriteRune(f.Group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteRune(f.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// ParseNumber reads a number written with the given separators. Group
+// separators are ignored wherever they are.
+func (f NumberFormat) ParseNumber(s string) (float64, error) {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r == f.Group && f.Group != 0:
+		case r == f.Decimal:
+			b.WriteByte('.')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strconv.ParseFloat(b.String(), 64)
+}
+
+// NumberBox is a TextBox for numbers, with up/down buttons. The arrow
+// keys, Page Up/Down and the mouse wheel step the value while it has the
+// focus. Typed text is
+// checked as it is entered and committed on Enter or when the box loses
+// the focus; invalid text is then replaced by the last valid value.
+type NumberBox struct {
+	TextBox
+	Value    float64
+	Min, Max float64 // Unbounded by default, see SetRange
+	Step     float64 // Arrow keys, wheel and buttons; Page Up/Down step 10x
+	Decimals int     // Digits after the decimal separator; 0 for integers
+	Format   *NumberFormat
+
+	OnValueChanged func(value float64)
+
+	pressed  int // Button held: +1 up, -1 down, 0 none
+	nextStep time.Time
+}
+
+func NewNumberBox(width int32) *NumberBox {
+	n := &NumberBox{
+		TextBox: *NewTextBox(width),
+		Min:     math.Inf(-1),
+		Max:     math.Inf(1),
+		Step:    1,
+	}
+	n.inset = numberBoxButtonWidth
+	n.InputFilter = n.accept
+	n.OnChange = func(string) { n.validate() }
+	n.SetText(n.format(0))
+	return n
+}
+
+func (n *NumberBox) numberFormat() NumberFormat {
+	if n.Format != nil {
+		return *n.Format
+	}
+	return DefaultNumberFormat
+}
+
+func (n *NumberBox) format(v float64) string {
+	return n.numberFormat().FormatNumber(v, n.Decimals)
+}
+
+// accept lets through the runes a number can contain.
+func (n *NumberBox) accept(r rune) bool {
+	f := n.numberFormat()
+	switch {
+	case r >= '0' && r <= '9':
+		return true
+	case r == '-' || r == '+':
+		return n.Min < 0
+	case r == f.Decimal:
+		return n.Decimals > 0
+	}
+	return r == f.Group && f.Group != 0
+}
+
+// parse reads the text and reports an error message if it is not a valid
+// value.
+func (n *NumberBox) parse() (float64, string) {
+	v, err := n.numberFormat().ParseNumber(n.Text)
+	switch {
+	case err != nil:
+		return 0, "Not a number"
+	case v < n.Min:
+		return v, "Must be at least " + n.format(n.Min)
+	case v > n.Max:
+		return v, "Must be at most " + n.format(n.Max)
+	}
+	return v, ""
+}
+
+// validate updates the error state after an edit.
+func (n *NumberBox) validate() {
+	_, n.ErrorText = n.parse()
+	n.RequestRepaint()
+}
+
+// SetValue sets the value, clamped to the range and rounded to Decimals,
+// and calls OnValueChanged if it changed.
+func (n *NumberBox) SetValue(v float64) {
+	v = max(n.Min, min(v, n.Max))
+	scale := math.Pow(10, float64(n.Decimals))
+	v = math.Round(v*scale) / scale
+	n.ErrorText = ""
+	n.SetText(n.format(v))
+	if v != n.Value {
+		n.Value = v
+		if n.OnValueChanged != nil {
+			n.OnValueChanged(v)
+		}
+	}
+}
+
+// SetRange sets Min and Max and clamps the value to them. Set the range
+// through SetRange rather than the fields, which leave the value as is
+// until the next commit.
+func (n *NumberBox) SetRange(min, max float64) {
+	n.Min, n.Max = min, max
+	n.SetValue(n.Value)
+}
+
+// commit applies valid text, or restores the text of the current value.
+func (n *NumberBox) commit() {
+	if v, msg := n.parse(); msg == "" {
+		n.SetValue(v)
+	} else {
+		n.SetValue(n.Value)
+	}
+}
+
+// stepBy moves the value by steps times Step, starting from the typed
+// text if it is valid.
+func (n *NumberBox) stepBy(steps float64) {
+	v := n.Value
+	if typed, msg := n.parse(); msg == "" {
+		v = typed
+	}
+	n.SetValue(v + steps*n.Step)
+	n.SelectAll()
+}
+
+// buttonAt returns +1 or -1 for the up or down button at a point, or 0.
+func (n *NumberBox) buttonAt(x, y int32) int {
+	b := n.Bounds
+	if !b.Contains(x, y) || x < b.X+b.Width-numberBoxButtonWidth {
+		return 0
+	}
+	if y < b.Y+b.Height/2 {
+		return 1
+	}
+	return -1
+}
+
+func (n *NumberBox) Tick(now time.Time) bool {
+	if n.pressed == 0 {
+		return false
+	}
+	if now.After(n.nextStep) {
+		n.stepBy(float64(n.pressed))
+		n.nextStep = now.Add(scrollRepeatInterval)
+	}
+	return true
+}
+
+func (n *NumberBox) Render(canvas *render.Canvas) {
+	if !n.Visible {
+		return
+	}
+	n.TextBox.Render(canvas)
+
+	// Draw Buttons over the right end of the box
+	b := n.Bounds
+	bx := b.X + b.Width - numberBoxButtonWidth
+	half := b.Height / 2
+	t := theme.Current()
+	fillInFrame(canvas, b, bx, b.Y+1, numberBoxButtonWidth-1, b.Height-2, t.Surface)
+	for i, dir := range []int{1, -1} {
+		y := b.Y + int32(i)*half
+		if n.pressed == dir {
+			fillInFrame(canvas, b, bx, y+1, numberBoxButtonWidth-1, half-1, t.Selection)
+		}
+		drawArrowGlyph(canvas, bx, y, numberBoxButtonWidth, half, Vertical, dir < 0, t.Icon)
+	}
+	canvas.FillRect(bx, b.Y+1, 1, b.Height-2, t.Divider)
+	n.RepaintRequested = false
+}
+
+func (n *NumberBox) OnBlur() {
+	n.TextBox.OnBlur()
+	n.commit()
+}
+
+func (n *NumberBox) OnEvent(evt event.Event) bool {
+	if !n.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if dir := n.buttonAt(data.X, data.Y); dir != 0 && !n.ReadOnly {
+			n.pressed = dir
+			n.stepBy(float64(dir))
+			n.nextStep = time.Now().Add(scrollRepeatDelay)
+			StartAnimation(n)
+			SetCapture(n)
+			return true
+		}
+
+	case event.EventMouseRelease:
+		if n.pressed != 0 {
+			n.pressed = 0
+			ReleaseCapture()
+			n.RequestRepaint()
+			return true
+		}
+
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || data.Delta == 0 || n.ReadOnly || !n.isFocused {
+			return false
+		}
+		n.stepBy(float64(data.Delta) / 120)
+		return true
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !n.isFocused || n.ReadOnly {
+			break
+		}
+		switch data.VirtualKeyCode {
+		case 0x26: // Up
+			n.stepBy(1)
+			return true
+		case 0x28: // Down
+			n.stepBy(-1)
+			return true
+		case 0x21: // PgUp
+			n.stepBy(10)
+			return true
+		case 0x22: // PgDn
+			n.stepBy(-10)
+			return true
+		case 0x0D: // Enter
+			n.commit()
+			return true
+		}
+	}
+	return n.TextBox.OnEvent(evt)
+}
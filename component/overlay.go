@@ -0,0 +1,321 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// Popup is a component shown above the window content by an Overlay,
+// e.g. a dropdown list, menu or dialog. Its Content is positioned in
+// window coordinates.
+type Popup struct {
+	Content Component
+
+	// Modal popups block mouse input to everything below them.
+	Modal bool
+	// Light popups close when clicking outside them (except on Owner) or
+	// pressing Escape.
+	Light bool
+	// Owner is the component that opened the popup. Clicks on it do not
+	// close the popup, so the owner can toggle it itself.
+	Owner Component
+	// KeepFocus leaves the keyboard focus where it is when the popup is
+	// clicked, so e.g. the owner keeps driving it with the keyboard.
+	KeepFocus bool
+	// CaptureKeys sends key events to the content before the focused
+	// component, as menus need. Keys the content does not handle go on to
+	// the focused component.
+	CaptureKeys bool
+	// Shadow draws a drop shadow behind the content.
+	Shadow bool
+	// Dim darkens everything below the popup, as for modal dialogs.
+	Dim bool
+
+	OnClose func() // Called whenever the popup is closed
+
+	overlay *Overlay
+}
+
+// NewPopup returns a light popup with a shadow that leaves the focus
+// where it is, as dropdowns do.
+func NewPopup(content Component, owner Component) *Popup {
+	return &Popup{Content: content, Owner: owner, Light: true, KeepFocus: true, Shadow: true}
+}
+
+// IsOpen reports whether the popup is shown.
+func (p *Popup) IsOpen() bool {
+	return p.overlay != nil
+}
+
+// Close hides the popup if it is open.
+func (p *Popup) Close() {
+	if p.overlay != nil {
+		p.overlay.Close(p)
+	}
+}
+
+// Overlay is the layer above a window's content that popups live in.
+// It renders after and receives events before the root panel.
+type Overlay struct {
+	Bounds layout.Rect
+	popups []*Popup // Bottom to top
+
+	// Focus and Focused give popups access to the keyboard focus of the
+	// window, e.g. for dialogs to take it and give it back. Set by the
+	// window.
+	Focus   func(c Component)
+	Focused func() Component
+}
+
+func NewOverlay() *Overlay {
+	return &Overlay{}
+}
+
+// The overlay popups open in: that of the active window.
+var activeOverlay *Overlay
+
+// SetActiveOverlay is called by the window that becomes active.
+func SetActiveOverlay(o *Overlay) {
+	activeOverlay = o
+}
+
+// ActiveOverlay returns the overlay of the active window, or nil before a
+// window exists.
+func ActiveOverlay() *Overlay {
+	return activeOverlay
+}
+
+func (o *Overlay) SetBounds(x, y, width, height int32) {
+	o.Bounds = layout.Rect{X: x, Y: y, Width: width, Height: height}
+}
+
+// Show opens p on top of all other popups.
+func (o *Overlay) Show(p *Popup) {
+	if p.overlay != nil {
+		p.overlay.remove(p)
+	}
+	p.overlay = o
+	o.popups = append(o.popups, p)
+}
+
+// ShowAt sizes p's content to w x h and opens it below anchor, or above
+// it if there is not enough room below, keeping it inside the window.
+func (o *Overlay) ShowAt(p *Popup, anchor layout.Rect, w, h int32) {
+	b := o.Bounds
+	x := max(b.X, min(anchor.X, b.X+b.Width-w))
+	y := anchor.Y + anchor.Height
+	if y+h > b.Y+b.Height && anchor.Y-h >= b.Y {
+		y = anchor.Y - h
+	}
+	y = max(b.Y, min(y, b.Y+b.Height-h))
+	p.Content.SetBounds(x, y, w, h)
+	o.Show(p)
+}
+
+// Close closes p and the popups above it (e.g. submenus).
+func (o *Overlay) Close(p *Popup) {
+	for i, q := range o.popups {
+		if q == p {
+			var closing []*Popup
+			for j := len(o.popups) - 1; j >= i; j-- {
+				closing = append(closing, o.popups[j])
+			}
+			o.closeAll(closing)
+			return
+		}
+	}
+}
+
+// closeAll removes popups, then calls their OnClose in order. OnClose may
+// open or close other popups, so it runs once o.popups is consistent.
+func (o *Overlay) closeAll(popups []*Popup) {
+	for _, p := range popups {
+		o.remove(p)
+		p.overlay = nil
+	}
+	for _, p := range popups {
+		if p.OnClose != nil {
+			p.OnClose()
+		}
+	}
+}
+
+func (o *Overlay) remove(p *Popup) {
+	for i, q := range o.popups {
+		if q == p {
+			o.popups = append(o.popups[:i], o.popups[i+1:]...)
+			return
+		}
+	}
+}
+
+// HasModal reports whether a modal popup is open.
+func (o *Overlay) HasModal() bool {
+	for _, p := range o.popups {
+		if p.Modal {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Overlay) Render(canvas *render.Canvas) {
+	for _, p := range o.popups {
+		if !p.Content.IsVisible() {
+			continue
+		}
+		t := theme.Current()
+		if p.Dim {
+			canvas.BlendRect(o.Bounds.X, o.Bounds.Y, o.Bounds.Width, o.Bounds.Height, 0x60000000)
+		}
+		if p.Shadow {
+			b := p.Content.GetBounds()
+			canvas.FillRoundRect(b.X+3, b.Y+3, b.Width, b.Height, t.CornerRadius, t.Shadow)
+		}
+		p.Content.Render(canvas)
+	}
+}
+
+// Ancestors returns the containers from a popup's content down to the
+// parent of c, or nil if c is not in a popup.
+func (o *Overlay) Ancestors(c Component) []Component {
+	for i := len(o.popups) - 1; i >= 0; i-- {
+		if path := Ancestors(o.popups[i].Content, c); path != nil {
+			return path
+		}
+	}
+	return nil
+}
+
+// Contains reports whether a point is over a popup.
+func (o *Overlay) Contains(x, y int32) bool {
+	for _, p := range o.popups {
+		if p.Content.IsVisible() && p.Content.GetBounds().Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindComponentAt hit tests the popups, topmost first. It returns nil
+// over popups that keep the focus where it is.
+func (o *Overlay) FindComponentAt(x, y int32) Component {
+	for i := len(o.popups) - 1; i >= 0; i-- {
+		p := o.popups[i]
+		c := p.Content
+		if !c.IsVisible() || !c.GetBounds().Contains(x, y) {
+			continue
+		}
+		if p.KeepFocus {
+			return nil
+		}
+		if container, ok := c.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil {
+				return found
+			}
+		}
+		return c
+	}
+	return nil
+}
+
+// OnEvent gives the popups a chance to handle an event before the window
+// content. It returns true if the content must not see the event.
+func (o *Overlay) OnEvent(evt event.Event) bool {
+	if len(o.popups) == 0 {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventKeyPress, event.EventKeyRelease, event.EventChar:
+		// The topmost popup capturing keys sees them first
+		for i := len(o.popups) - 1; i >= 0; i-- {
+			p := o.popups[i]
+			if p.CaptureKeys {
+				if p.Content.OnEvent(evt) {
+					return true
+				}
+				break
+			}
+			if p.Modal {
+				break
+			}
+		}
+		if evt.Type != event.EventKeyPress {
+			return false
+		}
+		// Escape closes the topmost light popup
+		if data, ok := evt.Data.(event.KeyEvent); ok && data.VirtualKeyCode == 0x1B {
+			for i := len(o.popups) - 1; i >= 0; i-- {
+				if o.popups[i].Light {
+					o.Close(o.popups[i])
+					return true
+				}
+				if o.popups[i].Modal {
+					break
+				}
+			}
+		}
+		return false
+
+	case event.EventMouseClick, event.EventMouseWheel, event.EventContextMenu:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		press := evt.Type != event.EventMouseWheel
+		// Topmost popup under the pointer
+		for i := len(o.popups) - 1; i >= 0; i-- {
+			p := o.popups[i]
+			if p.Content.IsVisible() && p.Content.GetBounds().Contains(data.X, data.Y) {
+				if press {
+					o.closeLightAbove(i, data)
+				}
+				p.Content.OnEvent(evt)
+				return true
+			}
+			if p.Modal {
+				if press {
+					o.closeLightAbove(i, data)
+				}
+				return true
+			}
+		}
+		if press {
+			o.closeLightAbove(-1, data)
+		}
+		return false
+
+	case event.EventMouseMove, event.EventMouseRelease:
+		// All popups see moves and releases, for hover and dragging
+		for i := len(o.popups) - 1; i >= 0; i-- {
+			p := o.popups[i]
+			if p.Content.OnEvent(evt) {
+				return true
+			}
+			if p.Modal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// closeLightAbove closes the light popups above index i, except those
+// whose owner was clicked.
+func (o *Overlay) closeLightAbove(i int, data event.MouseEvent) {
+	var closing []*Popup
+	for j := len(o.popups) - 1; j > i; j-- {
+		p := o.popups[j]
+		if !p.Light {
+			continue
+		}
+		if p.Owner != nil && p.Owner.GetBounds().Contains(data.X, data.Y) {
+			continue
+		}
+		closing = append(closing, p)
+	}
+	o.closeAll(closing)
+}
@@ -4,19 +4,19 @@ import (
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/layout"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type Panel struct {
 	BaseComponent
 	Children []Component
-	BgColor  uint32
+	BgColor  uint32 // 0 for the theme's background colour
 	Layout   layout.Layout
 }
 
 func NewPanel(x, y, w, h int32) *Panel {
 	p := &Panel{
 		Children: make([]Component, 0),
-		BgColor:  0xFFFFFFFF, // White
 	}
 	p.SetBounds(x, y, w, h)
 	p.Visible = true
@@ -46,6 +46,10 @@ func (p *Panel) GetChildren() []layout.Component {
 	return res
 }
 
+func (p *Panel) ChildComponents() []Component {
+	return p.Children
+}
+
 func (p *Panel) Add(c Component) {
 	p.Children = append(p.Children, c)
 	p.LayoutChildren()
@@ -83,7 +87,7 @@ func (p *Panel) Render(canvas *render.Canvas) {
 		return
 	}
 	// Fill background
-	canvas.FillRect(p.Bounds.X, p.Bounds.Y, p.Bounds.Width, p.Bounds.Height, p.BgColor)
+	canvas.FillRect(p.Bounds.X, p.Bounds.Y, p.Bounds.Width, p.Bounds.Height, theme.Or(p.BgColor, theme.Current().Background))
 
 	// Render children
 	for _, child := range p.Children {
@@ -100,9 +104,9 @@ func (p *Panel) FindComponentAt(x, y int32) Component {
 	for i := len(p.Children) - 1; i >= 0; i-- {
 		child := p.Children[i]
 
-		// If child is a Panel, recurse
-		if panel, ok := child.(*Panel); ok {
-			if found := panel.FindComponentAt(x, y); found != nil {
+		// If child is a container, recurse
+		if container, ok := child.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil {
 				return found
 			}
 		} else {
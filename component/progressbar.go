@@ -2,20 +2,19 @@ package component
 
 import (
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 )
 
 type ProgressBar struct {
 	BaseComponent
 	Value    float64 // 0.0 to 1.0
-	Color    uint32
-	BgColor  uint32
+	Color    uint32 // 0 for the theme's primary colour
+	BgColor  uint32 // 0 for the theme's surface colour
 }
 
 func NewProgressBar(width, height int32) *ProgressBar {
 	p := &ProgressBar{
 		Value:   0.0,
-		Color:   0xFF0078D7, // Blue
-		BgColor: 0xFFE0E0E0, // Gray
 	}
 	p.SetBounds(0, 0, width, height)
 	p.Visible = true
@@ -38,17 +37,19 @@ func (p *ProgressBar) Render(canvas *render.Canvas) {
 		return
 	}
 
+	t := theme.Current()
+
 	// Background
-	canvas.FillRect(p.Bounds.X, p.Bounds.Y, p.Bounds.Width, p.Bounds.Height, p.BgColor)
+	canvas.FillRect(p.Bounds.X, p.Bounds.Y, p.Bounds.Width, p.Bounds.Height, theme.Or(p.BgColor, t.Surface))
 
 	// Foreground
 	fillWidth := int32(float64(p.Bounds.Width) * p.Value)
 	if fillWidth > 0 {
-		canvas.FillRect(p.Bounds.X, p.Bounds.Y, fillWidth, p.Bounds.Height, p.Color)
+		canvas.FillRect(p.Bounds.X, p.Bounds.Y, fillWidth, p.Bounds.Height, theme.Or(p.Color, t.Primary))
 	}
 
 	// Border
-	borderColor := uint32(0xFFAAAAAA)
+	borderColor := t.Border
 	canvas.FillRect(p.Bounds.X, p.Bounds.Y, p.Bounds.Width, 1, borderColor)
 	canvas.FillRect(p.Bounds.X, p.Bounds.Y+p.Bounds.Height-1, p.Bounds.Width, 1, borderColor)
 	canvas.FillRect(p.Bounds.X, p.Bounds.Y, 1, p.Bounds.Height, borderColor)
@@ -0,0 +1,217 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// RadioButton is one choice of a RadioGroup. Checking it unchecks the
+// other buttons of its group; it cannot be unchecked by clicking.
+type RadioButton struct {
+	BaseComponent
+	Text     string
+	Value    string // Reported by the group, defaults to Text
+	Checked  bool
+	Disabled bool
+	OnCheck  func(checked bool)
+	Font     *render.Font
+	group    *RadioGroup
+	toggleInput
+}
+
+func NewRadioButton(text string) *RadioButton {
+	r := &RadioButton{
+		Text:  text,
+		Value: text,
+	}
+	r.Visible = true
+	return r
+}
+
+// Group returns the group the button belongs to, or nil.
+func (r *RadioButton) Group() *RadioGroup {
+	return r.group
+}
+
+// SetChecked checks or unchecks the button, unchecking the others of its
+// group. Callbacks are only called for changes.
+func (r *RadioButton) SetChecked(checked bool) {
+	if r.Checked == checked {
+		return
+	}
+	if checked && r.group != nil {
+		r.group.check(r)
+		return
+	}
+	r.setChecked(checked)
+}
+
+func (r *RadioButton) setChecked(checked bool) {
+	r.Checked = checked
+	r.RequestRepaint()
+	if r.OnCheck != nil {
+		r.OnCheck(checked)
+	}
+}
+
+func (r *RadioButton) GetPreferredSize() (int32, int32) {
+	circleSize := int32(16)
+	return toggleSize(r.Text, r.Font, circleSize, circleSize)
+}
+
+func (r *RadioButton) Render(canvas *render.Canvas) {
+	if !r.Visible {
+		return
+	}
+
+	// Draw Circle: a gray ring around the background
+	circleSize := int32(16)
+	cx := r.Bounds.X + circleSize/2
+	cy := r.Bounds.Y + r.Bounds.Height/2
+
+	t := theme.Current()
+	bgColor := t.Background
+	if r.Disabled {
+		bgColor = t.Surface
+	} else if r.isHovered {
+		bgColor = t.ControlHover
+	}
+	ringColor := t.Border
+	if r.Checked && !r.Disabled {
+		ringColor = t.Primary
+	}
+	fillCircle(canvas, cx, cy, circleSize/2, ringColor)
+	fillCircle(canvas, cx, cy, circleSize/2-1, bgColor)
+
+	// Draw Dot if checked
+	if r.Checked {
+		dotColor := t.Primary
+		if r.Disabled {
+			dotColor = t.TextDisabled
+		}
+		fillCircle(canvas, cx, cy, 4, dotColor)
+	}
+
+	drawToggleLabel(canvas, r.Bounds, r.Bounds.X+circleSize+toggleLabelGap(), r.Text, r.Font, r.Disabled, r.isFocused)
+	r.RepaintRequested = false
+}
+
+func (r *RadioButton) OnFocus() {
+	r.toggleInput.OnFocus()
+	r.RequestRepaint()
+}
+
+func (r *RadioButton) OnBlur() {
+	r.toggleInput.OnBlur()
+	r.RequestRepaint()
+}
+
+func (r *RadioButton) AcceptsFocus() bool {
+	return !r.Disabled
+}
+
+func (r *RadioButton) OnEvent(evt event.Event) bool {
+	if !r.Visible {
+		return false
+	}
+
+	handled, activated := r.handle(evt, r.Bounds, r.Disabled)
+	if activated {
+		r.SetChecked(true)
+	}
+	return handled
+}
+
+// RadioGroup makes its buttons mutually exclusive. It is not a component:
+// the buttons can be laid out anywhere.
+type RadioGroup struct {
+	Buttons  []*RadioButton
+	OnChange func(value string) // Called when a button is clicked or SetChecked
+}
+
+func NewRadioGroup(buttons ...*RadioButton) *RadioGroup {
+	g := &RadioGroup{}
+	for _, b := range buttons {
+		g.Add(b)
+	}
+	return g
+}
+
+// Add adds a button to the group, moving it from its previous group. A
+// checked button unchecks the one checked so far.
+func (g *RadioGroup) Add(b *RadioButton) {
+	if b.group != nil {
+		b.group.Remove(b)
+	}
+	b.group = g
+	g.Buttons = append(g.Buttons, b)
+	if b.Checked {
+		for _, other := range g.Buttons {
+			if other != b && other.Checked {
+				other.setChecked(false)
+			}
+		}
+	}
+}
+
+func (g *RadioGroup) Remove(b *RadioButton) {
+	for i, other := range g.Buttons {
+		if other == b {
+			g.Buttons = append(g.Buttons[:i], g.Buttons[i+1:]...)
+			b.group = nil
+			return
+		}
+	}
+}
+
+// Selected returns the checked button, or nil.
+func (g *RadioGroup) Selected() *RadioButton {
+	for _, b := range g.Buttons {
+		if b.Checked {
+			return b
+		}
+	}
+	return nil
+}
+
+// Value returns the Value of the checked button, or "".
+func (g *RadioGroup) Value() string {
+	if b := g.Selected(); b != nil {
+		return b.Value
+	}
+	return ""
+}
+
+// SetValue checks the button with the given value, or unchecks all
+// buttons if there is none. OnChange is not called.
+func (g *RadioGroup) SetValue(value string) {
+	var target *RadioButton
+	for _, b := range g.Buttons {
+		if b.Value == value {
+			target = b
+			break
+		}
+	}
+	for _, b := range g.Buttons {
+		if b != target && b.Checked {
+			b.setChecked(false)
+		}
+	}
+	if target != nil && !target.Checked {
+		target.setChecked(true)
+	}
+}
+
+// check checks b and unchecks the others, as a click on b does.
+func (g *RadioGroup) check(b *RadioButton) {
+	for _, other := range g.Buttons {
+		if other != b && other.Checked {
+			other.setChecked(false)
+		}
+	}
+	b.setChecked(true)
+	if g.OnChange != nil {
+		g.OnChange(b.Value)
+	}
+}
@@ -0,0 +1,172 @@
+package component
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/jacksalad/goui_v0/render"
+)
+
+// Resources loads images and fonts once and shares them, e.g. an icon
+// shown by many menu items, or a font used by every label. Images are kept
+// decoded by key, a path in FS; each call to Image returns a new component
+// drawing the shared pixels. Resources are safe for concurrent use, e.g.
+// preloading from a goroutine, but the Image components they return belong
+// to the UI thread like any other.
+type Resources struct {
+	FS fs.FS // Where keys are read from; the OS file system if nil
+
+	mu       sync.Mutex
+	images   map[string]*imageData
+	fonts    map[fontKey]*render.Font
+	families map[string]string // Family of each font file loaded, by path
+}
+
+type fontKey struct {
+	family string
+	size   int
+}
+
+func NewResources(fsys fs.FS) *Resources {
+	return &Resources{
+		FS:       fsys,
+		images:   map[string]*imageData{},
+		fonts:    map[fontKey]*render.Font{},
+		families: map[string]string{},
+	}
+}
+
+// DefaultResources reads from the OS file system.
+var DefaultResources = NewResources(nil)
+
+func (r *Resources) open(path string) (io.ReadCloser, error) {
+	if r.FS != nil {
+		return r.FS.Open(path)
+	}
+	return os.Open(path)
+}
+
+// store keeps data under key, unless another goroutine got there first,
+// and returns what is kept.
+func (r *Resources) store(key string, data *imageData) *imageData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.images[key]; ok {
+		return cached
+	}
+	r.images[key] = data
+	return data
+}
+
+func (r *Resources) imageData(key string) (*imageData, error) {
+	r.mu.Lock()
+	data, ok := r.images[key]
+	r.mu.Unlock()
+	if ok {
+		return data, nil
+	}
+
+	file, err := r.open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err = decodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+	return r.store(key, data), nil
+}
+
+// Image returns a new Image of the image at path key, decoded on first
+// use and shared afterwards.
+func (r *Resources) Image(key string) (*Image, error) {
+	data, err := r.imageData(key)
+	if err != nil {
+		return nil, err
+	}
+	c := newImage(data)
+	c.FilePath = key
+	return c, nil
+}
+
+// AddImage decodes an image held in memory, e.g. embedded in the
+// executable or downloaded, and keeps it under key for Image.
+func (r *Resources) AddImage(key string, data []byte) error {
+	decoded, err := decodeImage(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.images[key] = decoded
+	r.mu.Unlock()
+	return nil
+}
+
+// Preload decodes images ahead of their use, stopping at the first error.
+func (r *Resources) Preload(keys ...string) error {
+	for _, key := range keys {
+		if _, err := r.imageData(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Forget drops an image, so that it is read again on next use. Images
+// already created keep showing it.
+func (r *Resources) Forget(key string) {
+	r.mu.Lock()
+	delete(r.images, key)
+	r.mu.Unlock()
+}
+
+// LoadFont loads a font file from FS, once, and returns its family name
+// for Font.
+func (r *Resources) LoadFont(path string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if family, ok := r.families[path]; ok {
+		return family, nil
+	}
+	file, err := r.open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	family, err := render.LoadFontReader(file)
+	if err != nil {
+		return "", err
+	}
+	r.families[path] = family
+	return family, nil
+}
+
+// Font returns the font of a family and size, created on first use. The
+// font is shared: do not Close it.
+func (r *Resources) Font(family string, size int) *render.Font {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := fontKey{family, size}
+	font, ok := r.fonts[key]
+	if !ok {
+		font = render.NewFont(family, size)
+		r.fonts[key] = font
+	}
+	return font
+}
+
+// Close releases the fonts and forgets the images. Fonts still in use
+// must not be drawn with afterwards.
+func (r *Resources) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, font := range r.fonts {
+		font.Close()
+		delete(r.fonts, key)
+	}
+	clear(r.images)
+}
@@ -0,0 +1,348 @@
+package component
+
+import (
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+type Orientation int
+
+const (
+	Horizontal Orientation = iota
+	Vertical
+)
+
+const (
+	scrollBarThickness = int32(14)
+	scrollBarMinThumb  = int32(16)
+
+	// Auto-repeat of held arrows and track
+	scrollRepeatDelay    = 400 * time.Millisecond
+	scrollRepeatInterval = 50 * time.Millisecond
+)
+
+type scrollPart int
+
+const (
+	partNone scrollPart = iota
+	partDecArrow
+	partIncArrow
+	partDecTrack
+	partIncTrack
+	partThumb
+)
+
+// ScrollBar scrolls a range of Max units of which PageSize are visible.
+// Value is the first visible unit, between 0 and MaxValue().
+type ScrollBar struct {
+	BaseComponent
+	Orientation Orientation
+	Value       float64
+	Max         float64
+	PageSize    float64
+	SmallStep   float64 // Arrow buttons, 0 means 20
+	OnScroll    func(value float64)
+
+	hoverPart   scrollPart
+	pressedPart scrollPart
+	dragOffset  int32
+	pointer     int32 // Last pointer position along the bar, for track repeat
+	nextRepeat  time.Time
+}
+
+func NewScrollBar(orientation Orientation) *ScrollBar {
+	s := &ScrollBar{
+		Orientation: orientation,
+	}
+	if orientation == Vertical {
+		s.SetBounds(0, 0, scrollBarThickness, 100)
+	} else {
+		s.SetBounds(0, 0, 100, scrollBarThickness)
+	}
+	s.Visible = true
+	return s
+}
+
+func (s *ScrollBar) GetPreferredSize() (int32, int32) {
+	if s.Orientation == Vertical {
+		return scrollBarThickness, s.Bounds.Height
+	}
+	return s.Bounds.Width, scrollBarThickness
+}
+
+// MaxValue is the largest Value, reached when the end of the range is visible.
+func (s *ScrollBar) MaxValue() float64 {
+	if s.Max <= s.PageSize {
+		return 0
+	}
+	return s.Max - s.PageSize
+}
+
+// SetValue clamps v into range and calls OnScroll if it changed.
+func (s *ScrollBar) SetValue(v float64) {
+	if v > s.MaxValue() {
+		v = s.MaxValue()
+	}
+	if v < 0 {
+		v = 0
+	}
+	if v == s.Value {
+		return
+	}
+	s.Value = v
+	s.RequestRepaint()
+	if s.OnScroll != nil {
+		s.OnScroll(v)
+	}
+}
+
+func (s *ScrollBar) smallStep() float64 {
+	if s.SmallStep > 0 {
+		return s.SmallStep
+	}
+	return 20
+}
+
+// Geometry along the bar, relative to its start.
+
+func (s *ScrollBar) length() int32 {
+	if s.Orientation == Vertical {
+		return s.Bounds.Height
+	}
+	return s.Bounds.Width
+}
+
+func (s *ScrollBar) thickness() int32 {
+	if s.Orientation == Vertical {
+		return s.Bounds.Width
+	}
+	return s.Bounds.Height
+}
+
+func (s *ScrollBar) arrowSize() int32 {
+	a := s.thickness()
+	if 2*a > s.length() {
+		a = s.length() / 2
+	}
+	return a
+}
+
+// thumb returns the thumb position and length.
+func (s *ScrollBar) thumb() (int32, int32) {
+	arrow := s.arrowSize()
+	track := s.length() - 2*arrow
+	if s.Max <= 0 || s.Max <= s.PageSize {
+		return arrow, track
+	}
+	size := int32(float64(track) * s.PageSize / s.Max)
+	if size < scrollBarMinThumb {
+		size = scrollBarMinThumb
+	}
+	if size > track {
+		size = track
+	}
+	pos := arrow + int32(float64(track-size)*s.Value/s.MaxValue())
+	return pos, size
+}
+
+func (s *ScrollBar) along(x, y int32) int32 {
+	if s.Orientation == Vertical {
+		return y - s.Bounds.Y
+	}
+	return x - s.Bounds.X
+}
+
+func (s *ScrollBar) partAt(x, y int32) scrollPart {
+	if !s.Bounds.Contains(x, y) {
+		return partNone
+	}
+	p := s.along(x, y)
+	arrow := s.arrowSize()
+	pos, size := s.thumb()
+	switch {
+	case p < arrow:
+		return partDecArrow
+	case p >= s.length()-arrow:
+		return partIncArrow
+	case p < pos:
+		return partDecTrack
+	case p >= pos+size:
+		return partIncTrack
+	}
+	return partThumb
+}
+
+// step performs the action of a pressed arrow or track part.
+func (s *ScrollBar) step(part scrollPart) {
+	page := s.PageSize
+	if page <= 0 {
+		page = s.smallStep()
+	}
+	switch part {
+	case partDecArrow:
+		s.SetValue(s.Value - s.smallStep())
+	case partIncArrow:
+		s.SetValue(s.Value + s.smallStep())
+	case partDecTrack:
+		if pos, _ := s.thumb(); s.pointer < pos {
+			s.SetValue(s.Value - page)
+		}
+	case partIncTrack:
+		if pos, size := s.thumb(); s.pointer >= pos+size {
+			s.SetValue(s.Value + page)
+		}
+	}
+}
+
+// Tick auto-repeats a held arrow or track press.
+func (s *ScrollBar) Tick(now time.Time) bool {
+	if s.pressedPart == partNone || s.pressedPart == partThumb {
+		return false
+	}
+	if now.After(s.nextRepeat) {
+		s.step(s.pressedPart)
+		s.nextRepeat = now.Add(scrollRepeatInterval)
+	}
+	return true
+}
+
+func (s *ScrollBar) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	b := s.Bounds
+	t := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, t.Surface)
+
+	// Arrows and thumb are shades between the track and the text
+	shade := func(amount float64) uint32 {
+		return theme.Mix(t.Surface, t.Text, amount)
+	}
+
+	arrow := s.arrowSize()
+	length := s.length()
+	pos, size := s.thumb()
+
+	// rect maps a span along the bar to canvas coordinates
+	rect := func(start, span int32) (int32, int32, int32, int32) {
+		if s.Orientation == Vertical {
+			return b.X, b.Y + start, b.Width, span
+		}
+		return b.X + start, b.Y, span, b.Height
+	}
+
+	// Arrows
+	for _, part := range []scrollPart{partDecArrow, partIncArrow} {
+		start := int32(0)
+		if part == partIncArrow {
+			start = length - arrow
+		}
+		color := shade(0.05)
+		if s.pressedPart == part {
+			color = shade(0.17)
+		} else if s.hoverPart == part {
+			color = shade(0.09)
+		}
+		x, y, w, h := rect(start, arrow)
+		canvas.FillRect(x, y, w, h, color)
+		drawArrowGlyph(canvas, x, y, w, h, s.Orientation, part == partIncArrow, t.Icon)
+	}
+
+	// Thumb
+	if s.MaxValue() > 0 {
+		color := shade(0.2)
+		if s.pressedPart == partThumb {
+			color = shade(0.43)
+		} else if s.hoverPart == partThumb {
+			color = shade(0.3)
+		}
+		x, y, w, h := rect(pos, size)
+		if s.Orientation == Vertical {
+			canvas.FillRect(x+2, y, w-4, h, color)
+		} else {
+			canvas.FillRect(x, y+2, w, h-4, color)
+		}
+	}
+	s.RepaintRequested = false
+}
+
+// drawArrowGlyph draws a small triangle centered in the given box, pointing
+// up/left, or down/right if forward is set.
+func drawArrowGlyph(canvas *render.Canvas, x, y, w, h int32, orientation Orientation, forward bool, color uint32) {
+	half := min(w, h) / 4
+	cx, cy := x+w/2, y+h/2
+	for i := int32(0); i <= half; i++ {
+		// Row i counts from the tip
+		offset := i - half/2
+		if forward {
+			offset = half/2 - i
+		}
+		if orientation == Vertical {
+			canvas.FillRect(cx-i, cy+offset, 2*i+1, 1, color)
+		} else {
+			canvas.FillRect(cx+offset, cy-i, 1, 2*i+1, color)
+		}
+	}
+}
+
+func (s *ScrollBar) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+	data, ok := evt.Data.(event.MouseEvent)
+	if !ok {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		part := s.partAt(data.X, data.Y)
+		if part == partNone {
+			return false
+		}
+		s.pressedPart = part
+		s.pointer = s.along(data.X, data.Y)
+		if part == partThumb {
+			pos, _ := s.thumb()
+			s.dragOffset = s.pointer - pos
+		} else {
+			s.step(part)
+			s.nextRepeat = time.Now().Add(scrollRepeatDelay)
+			StartAnimation(s)
+		}
+		s.RequestRepaint()
+		return true
+
+	case event.EventMouseMove:
+		if s.pressedPart == partThumb {
+			// Keeps tracking outside the bar while dragging
+			arrow := s.arrowSize()
+			_, size := s.thumb()
+			track := s.length() - 2*arrow - size
+			if track > 0 {
+				pos := s.along(data.X, data.Y) - s.dragOffset - arrow
+				s.SetValue(float64(pos) * s.MaxValue() / float64(track))
+			}
+			return true
+		}
+		if s.pressedPart != partNone {
+			s.pointer = s.along(data.X, data.Y)
+		}
+		if part := s.partAt(data.X, data.Y); part != s.hoverPart {
+			s.hoverPart = part
+			s.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if s.pressedPart != partNone {
+			s.pressedPart = partNone
+			StopAnimation(s)
+			s.RequestRepaint()
+			return true
+		}
+	}
+	return false
+}
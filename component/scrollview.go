@@ -0,0 +1,387 @@
+package component
+
+import (
+	"math"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// ScrollPolicy decides when a ScrollView shows a scroll bar.
+type ScrollPolicy int
+
+const (
+	ScrollAuto   ScrollPolicy = iota // Only when the content overflows
+	ScrollAlways                     // Always, disabled if nothing to scroll
+	ScrollNever                      // Never; the content is fitted to the viewport on that axis
+)
+
+// ScrollView shows a part of a Content component that is larger than
+// itself. The content is sized to its preferred size (or ContentWidth /
+// ContentHeight when set) and clipped to the viewport. Since a Panel's
+// preferred size is its current size, a Panel is scrolled at the size it
+// was created with.
+type ScrollView struct {
+	BaseComponent
+	Content       Component
+	HPolicy       ScrollPolicy
+	VPolicy       ScrollPolicy
+	ContentWidth  int32  // 0 means the content's preferred width
+	ContentHeight int32  // 0 means the content's preferred height
+	WheelStep     int32  // Pixels per wheel notch
+	Smooth        bool   // Animate wheel, keyboard and ScrollTo scrolling
+	BgColor       uint32 // 0 for the theme's background colour
+	OnScroll      func(x, y int32)
+
+	hBar, vBar *ScrollBar
+	viewport   layout.Rect
+	contentW   int32
+	contentH   int32
+
+	// Scroll offset: current (rendered) and target of the animation
+	scrollX, scrollY float64
+	targetX, targetY float64
+	lastTick         time.Time
+}
+
+func NewScrollView(width, height int32, content Component) *ScrollView {
+	s := &ScrollView{
+		WheelStep: 48,
+		Smooth:    true,
+		hBar:      NewScrollBar(Horizontal),
+		vBar:      NewScrollBar(Vertical),
+	}
+	s.hBar.OnScroll = func(v float64) { s.setScroll(v, s.scrollY) }
+	s.vBar.OnScroll = func(v float64) { s.setScroll(s.scrollX, v) }
+	s.Visible = true
+	s.SetBounds(0, 0, width, height)
+	s.SetContent(content)
+	return s
+}
+
+// SetContent replaces the scrolled component and scrolls back to the origin.
+func (s *ScrollView) SetContent(c Component) {
+	s.Content = c
+	s.scrollX, s.scrollY = 0, 0
+	s.targetX, s.targetY = 0, 0
+	s.updateLayout()
+}
+
+func (s *ScrollView) ChildComponents() []Component {
+	if s.Content == nil {
+		return nil
+	}
+	return []Component{s.Content}
+}
+
+func (s *ScrollView) SetBounds(x, y, width, height int32) {
+	s.BaseComponent.SetBounds(x, y, width, height)
+	s.updateLayout()
+}
+
+// Viewport returns the visible area, i.e. the bounds without scroll bars.
+func (s *ScrollView) Viewport() layout.Rect {
+	return s.viewport
+}
+
+// ScrollOffset returns the current scroll position of the content.
+func (s *ScrollView) ScrollOffset() (int32, int32) {
+	return int32(math.Round(s.scrollX)), int32(math.Round(s.scrollY))
+}
+
+// SetScrollOffset scrolls to x, y immediately.
+func (s *ScrollView) SetScrollOffset(x, y int32) {
+	s.setScroll(float64(x), float64(y))
+}
+
+// ScrollTo scrolls just enough for r, in content coordinates, to become
+// visible. If r is larger than the viewport its top-left corner is shown.
+func (s *ScrollView) ScrollTo(r layout.Rect) {
+	x, y := s.targetX, s.targetY
+	x = ensureSpan(x, float64(r.X), float64(r.Width), float64(s.viewport.Width))
+	y = ensureSpan(y, float64(r.Y), float64(r.Height), float64(s.viewport.Height))
+	s.scrollBy(x-s.targetX, y-s.targetY)
+}
+
+// EnsureVisible scrolls to a component inside the content.
+func (s *ScrollView) EnsureVisible(c Component) {
+	if s.Content == nil {
+		return
+	}
+	r, origin := c.GetBounds(), s.Content.GetBounds()
+	r.X -= origin.X
+	r.Y -= origin.Y
+	s.ScrollTo(r)
+}
+
+func ensureSpan(offset, start, size, view float64) float64 {
+	if start < offset || size > view {
+		return start
+	}
+	if start+size > offset+view {
+		return start + size - view
+	}
+	return offset
+}
+
+func (s *ScrollView) maxScroll() (float64, float64) {
+	return float64(max(0, s.contentW-s.viewport.Width)), float64(max(0, s.contentH-s.viewport.Height))
+}
+
+func clampScroll(v, max float64) float64 {
+	return math.Max(0, math.Min(v, max))
+}
+
+// setScroll jumps to an offset without animation.
+func (s *ScrollView) setScroll(x, y float64) {
+	mx, my := s.maxScroll()
+	s.targetX, s.targetY = clampScroll(x, mx), clampScroll(y, my)
+	s.scrollX, s.scrollY = s.targetX, s.targetY
+	StopAnimation(s)
+	s.updateLayout()
+	s.notifyScroll()
+}
+
+// scrollBy moves the target offset, animating there if Smooth is set.
+// It reports whether the offset changed.
+func (s *ScrollView) scrollBy(dx, dy float64) bool {
+	mx, my := s.maxScroll()
+	x, y := clampScroll(s.targetX+dx, mx), clampScroll(s.targetY+dy, my)
+	if x == s.targetX && y == s.targetY {
+		return false
+	}
+	if !s.Smooth {
+		s.setScroll(x, y)
+		return true
+	}
+	s.targetX, s.targetY = x, y
+	s.lastTick = time.Now()
+	StartAnimation(s)
+	return true
+}
+
+// Tick eases the scroll offset towards its target.
+func (s *ScrollView) Tick(now time.Time) bool {
+	dt := now.Sub(s.lastTick)
+	s.lastTick = now
+	var doneX, doneY bool
+	s.scrollX, doneX = easeScroll(s.scrollX, s.targetX, dt)
+	s.scrollY, doneY = easeScroll(s.scrollY, s.targetY, dt)
+	done := doneX && doneY
+	s.updateLayout()
+	s.notifyScroll()
+	return !done
+}
+
+// easeScroll moves pos towards target, covering 25% of the remaining
+// distance per 16ms frame, and reports whether it arrived.
+func easeScroll(pos, target float64, dt time.Duration) (float64, bool) {
+	f := 1 - math.Pow(0.75, float64(dt)/float64(16*time.Millisecond))
+	pos += (target - pos) * f
+	if math.Abs(target-pos) < 0.5 {
+		return target, true
+	}
+	return pos, false
+}
+
+func (s *ScrollView) notifyScroll() {
+	s.RequestRepaint()
+	if s.OnScroll != nil {
+		s.OnScroll(s.ScrollOffset())
+	}
+}
+
+func (s *ScrollView) naturalContentSize() (int32, int32) {
+	w, h := s.ContentWidth, s.ContentHeight
+	if w > 0 && h > 0 {
+		return w, h
+	}
+	pw, ph := s.Content.GetPreferredSize()
+	if w <= 0 {
+		w = pw
+	}
+	if h <= 0 {
+		h = ph
+	}
+	return w, h
+}
+
+// updateLayout decides which bars are shown and positions the bars and
+// the content for the current scroll offset.
+func (s *ScrollView) updateLayout() {
+	if s.hBar == nil {
+		return // Not constructed yet
+	}
+	b := s.Bounds
+	var cw, ch int32
+	if s.Content != nil {
+		cw, ch = s.naturalContentSize()
+	}
+
+	showV := s.VPolicy == ScrollAlways || (s.VPolicy == ScrollAuto && ch > b.Height)
+	showH := s.HPolicy == ScrollAlways || (s.HPolicy == ScrollAuto && cw > b.Width-barSize(showV))
+	// The horizontal bar may make the vertical one necessary
+	if !showV && s.VPolicy == ScrollAuto && ch > b.Height-barSize(showH) {
+		showV = true
+	}
+
+	s.viewport = layout.Rect{X: b.X, Y: b.Y, Width: max(0, b.Width-barSize(showV)), Height: max(0, b.Height-barSize(showH))}
+	if s.HPolicy == ScrollNever {
+		cw = s.viewport.Width
+	}
+	if s.VPolicy == ScrollNever {
+		ch = s.viewport.Height
+	}
+	s.contentW, s.contentH = cw, ch
+
+	// Content may have shrunk
+	mx, my := s.maxScroll()
+	s.scrollX, s.targetX = clampScroll(s.scrollX, mx), clampScroll(s.targetX, mx)
+	s.scrollY, s.targetY = clampScroll(s.scrollY, my), clampScroll(s.targetY, my)
+
+	s.hBar.Visible, s.vBar.Visible = showH, showV
+	s.hBar.SetBounds(b.X, b.Y+s.viewport.Height, s.viewport.Width, barSize(showH))
+	s.vBar.SetBounds(b.X+s.viewport.Width, b.Y, barSize(showV), s.viewport.Height)
+	s.hBar.Max, s.hBar.PageSize, s.hBar.Value = float64(cw), float64(s.viewport.Width), s.scrollX
+	s.vBar.Max, s.vBar.PageSize, s.vBar.Value = float64(ch), float64(s.viewport.Height), s.scrollY
+	s.hBar.SmallStep, s.vBar.SmallStep = float64(s.WheelStep), float64(s.WheelStep)
+
+	if s.Content != nil {
+		ox, oy := s.ScrollOffset()
+		x, y := s.viewport.X-ox, s.viewport.Y-oy
+		if cb := s.Content.GetBounds(); cb.X != x || cb.Y != y || cb.Width != cw || cb.Height != ch {
+			s.Content.SetBounds(x, y, cw, ch)
+		}
+	}
+}
+
+func barSize(shown bool) int32 {
+	if shown {
+		return scrollBarThickness
+	}
+	return 0
+}
+
+func (s *ScrollView) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	// Content size may have changed since the last frame
+	s.updateLayout()
+
+	v := s.viewport
+	t := theme.Current()
+	canvas.PushClip(v.X, v.Y, v.Width, v.Height)
+	canvas.FillRect(v.X, v.Y, v.Width, v.Height, theme.Or(s.BgColor, t.Background))
+	if s.Content != nil {
+		s.Content.Render(canvas)
+	}
+	canvas.PopClip()
+
+	s.hBar.Render(canvas)
+	s.vBar.Render(canvas)
+	if s.hBar.Visible && s.vBar.Visible {
+		canvas.FillRect(v.X+v.Width, v.Y+v.Height, scrollBarThickness, scrollBarThickness, t.Surface)
+	}
+	s.RepaintRequested = false
+}
+
+func (s *ScrollView) FindComponentAt(x, y int32) Component {
+	if !s.Visible || !s.Bounds.Contains(x, y) {
+		return nil
+	}
+	if s.Content != nil && s.viewport.Contains(x, y) {
+		if container, ok := s.Content.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil && found != s.Content {
+				return found
+			}
+		} else if s.Content.GetBounds().Contains(x, y) {
+			return s.Content
+		}
+	}
+	// The view itself takes focus so the keyboard can scroll it
+	return s
+}
+
+func (s *ScrollView) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !s.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		// Nested scrollables get the wheel first
+		if s.Content != nil && s.viewport.Contains(data.X, data.Y) && s.Content.OnEvent(evt) {
+			return true
+		}
+		step := float64(s.WheelStep) / 120
+		dx, dy := float64(data.DeltaX)*step, -float64(data.Delta)*step
+		if data.Modifiers&event.ModShift != 0 && dx == 0 {
+			dx, dy = dy, 0
+		}
+		// Unhandled at the edge so an outer view can scroll instead
+		return s.scrollBy(dx, dy)
+
+	case event.EventMouseClick, event.EventMouseMove, event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if s.vBar.OnEvent(evt) || s.hBar.OnEvent(evt) {
+			return true
+		}
+		if s.Content == nil {
+			return false
+		}
+		inside := s.viewport.Contains(data.X, data.Y)
+		switch {
+		case evt.Type == event.EventMouseClick && !inside:
+			return s.Bounds.Contains(data.X, data.Y)
+		case evt.Type == event.EventMouseMove && !inside:
+			// The clipped parts of the content must not react to the
+			// pointer, so it is reported just outside the content.
+			cb := s.Content.GetBounds()
+			data.X, data.Y = cb.X-1, cb.Y-1
+			evt.Data = data
+		}
+		if s.Content.OnEvent(evt) {
+			return true
+		}
+		return evt.Type == event.EventMouseClick
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok {
+			return false
+		}
+		line := float64(s.WheelStep)
+		page := float64(s.viewport.Height)
+		switch data.VirtualKeyCode {
+		case 0x25: // Left
+			return s.scrollBy(-line, 0)
+		case 0x27: // Right
+			return s.scrollBy(line, 0)
+		case 0x26: // Up
+			return s.scrollBy(0, -line)
+		case 0x28: // Down
+			return s.scrollBy(0, line)
+		case 0x21: // Page Up
+			return s.scrollBy(0, -page)
+		case 0x22: // Page Down
+			return s.scrollBy(0, page)
+		case 0x24: // Home
+			return s.scrollBy(0, -s.targetY)
+		case 0x23: // End
+			_, my := s.maxScroll()
+			return s.scrollBy(0, my-s.targetY)
+		}
+	}
+	return false
+}
@@ -0,0 +1,555 @@
+package component
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	sliderThumbRadius = 8
+	sliderTrackWidth  = 4
+	sliderTickLength  = 4
+)
+
+// sliderBase holds the range, geometry and drawing shared by Slider and
+// RangeSlider. Vertical sliders have their minimum at the bottom.
+type sliderBase struct {
+	Orientation   Orientation
+	Min, Max      float64
+	Step          float64 // Values snap to Min + n*Step; 0 is continuous
+	LargeStep     float64 // Page Up/Down; 0 means a tenth of the range
+	TickFrequency float64 // Distance between tick marks; 0 draws none
+	ShowTooltip   bool    // Show the value next to the thumb while dragging
+	Format        func(value float64) string
+	Disabled      bool
+
+	isFocused bool
+	tip       *Popup
+}
+
+func newSliderBase(orientation Orientation, minimum, maximum float64) sliderBase {
+	return sliderBase{Orientation: orientation, Min: minimum, Max: maximum, ShowTooltip: true}
+}
+
+// clamp limits v to the range and snaps it to the step.
+func (s *sliderBase) AcceptsFocus() bool {
+	return !s.Disabled
+}
+
+func (s *sliderBase) clamp(v float64) float64 {
+	if s.Step > 0 {
+		v = s.Min + math.Round((v-s.Min)/s.Step)*s.Step
+	}
+	return max(s.Min, min(v, s.Max))
+}
+
+// keyStep returns the change a navigation key makes to value v, and
+// whether key is one. Home and End jump to the ends.
+func (s *sliderBase) keyStep(key uint32, v float64) (float64, bool) {
+	small := s.Step
+	if small <= 0 {
+		small = (s.Max - s.Min) / 100
+	}
+	large := s.LargeStep
+	if large <= 0 {
+		large = max((s.Max-s.Min)/10, small)
+	}
+	switch key {
+	case 0x27, 0x26: // Right, Up
+		return v + small, true
+	case 0x25, 0x28: // Left, Down
+		return v - small, true
+	case 0x21: // PgUp
+		return v + large, true
+	case 0x22: // PgDn
+		return v - large, true
+	case 0x24: // Home
+		return s.Min, true
+	case 0x23: // End
+		return s.Max, true
+	}
+	return v, false
+}
+
+// track returns the pixel span the thumb center moves along: from the
+// minimum position a to the maximum position b.
+func (s *sliderBase) track(bounds layout.Rect) (a, b int32) {
+	if s.Orientation == Vertical {
+		return bounds.Y + bounds.Height - 1 - sliderThumbRadius, bounds.Y + sliderThumbRadius
+	}
+	return bounds.X + sliderThumbRadius, bounds.X + bounds.Width - 1 - sliderThumbRadius
+}
+
+// posOf returns the pixel position of value v along the track.
+func (s *sliderBase) posOf(bounds layout.Rect, v float64) int32 {
+	a, b := s.track(bounds)
+	if s.Max <= s.Min {
+		return a
+	}
+	t := (v - s.Min) / (s.Max - s.Min)
+	return a + int32(math.Round(float64(b-a)*t))
+}
+
+// valueAt returns the value at a pointer position.
+func (s *sliderBase) valueAt(bounds layout.Rect, x, y int32) float64 {
+	a, b := s.track(bounds)
+	p := x
+	if s.Orientation == Vertical {
+		p = y
+	}
+	if a == b {
+		return s.Min
+	}
+	t := float64(p-a) / float64(b-a)
+	return s.clamp(s.Min + t*(s.Max-s.Min))
+}
+
+// along returns the pointer coordinate along the track.
+func (s *sliderBase) along(x, y int32) int32 {
+	if s.Orientation == Vertical {
+		return y
+	}
+	return x
+}
+
+// thumbCenter returns the center of the thumb for value v.
+func (s *sliderBase) thumbCenter(bounds layout.Rect, v float64) (int32, int32) {
+	p := s.posOf(bounds, v)
+	if s.Orientation == Vertical {
+		return bounds.X + bounds.Width/2, p
+	}
+	return p, bounds.Y + bounds.Height/2
+}
+
+func (s *sliderBase) preferredSize(bounds layout.Rect) (int32, int32) {
+	thickness := int32(2*sliderThumbRadius + 2)
+	if s.TickFrequency > 0 {
+		thickness += 2 * (sliderTickLength + 2)
+	}
+	if s.Orientation == Vertical {
+		return thickness, bounds.Height
+	}
+	return bounds.Width, thickness
+}
+
+// drawTrack draws the track with the part between the values from and to
+// highlighted, and the tick marks on both sides.
+func (s *sliderBase) drawTrack(canvas *render.Canvas, bounds layout.Rect, from, to float64) {
+	a, b := s.track(bounds)
+	p, q := s.posOf(bounds, from), s.posOf(bounds, to)
+	th := theme.Current()
+	fill := th.Primary
+	if s.Disabled {
+		fill = th.Border
+	}
+
+	cx, cy := bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2
+	if s.Orientation == Vertical {
+		canvas.FillRect(cx-sliderTrackWidth/2, b, sliderTrackWidth, a-b+1, th.Divider)
+		canvas.FillRect(cx-sliderTrackWidth/2, min(p, q), sliderTrackWidth, max(p, q)-min(p, q)+1, fill)
+	} else {
+		canvas.FillRect(a, cy-sliderTrackWidth/2, b-a+1, sliderTrackWidth, th.Divider)
+		canvas.FillRect(min(p, q), cy-sliderTrackWidth/2, max(p, q)-min(p, q)+1, sliderTrackWidth, fill)
+	}
+
+	if s.TickFrequency <= 0 || s.Max <= s.Min {
+		return
+	}
+	off := int32(sliderThumbRadius + 2)
+	for v := s.Min; v <= s.Max+s.TickFrequency/1e6; v += s.TickFrequency {
+		t := s.posOf(bounds, min(v, s.Max))
+		if s.Orientation == Vertical {
+			canvas.FillRect(cx-off-sliderTickLength, t, sliderTickLength, 1, th.TextDisabled)
+			canvas.FillRect(cx+off+1, t, sliderTickLength, 1, th.TextDisabled)
+		} else {
+			canvas.FillRect(t, cy-off-sliderTickLength, 1, sliderTickLength, th.TextDisabled)
+			canvas.FillRect(t, cy+off+1, 1, sliderTickLength, th.TextDisabled)
+		}
+	}
+}
+
+// drawThumb draws a thumb for value v; the active one gets a focus ring.
+func (s *sliderBase) drawThumb(canvas *render.Canvas, bounds layout.Rect, v float64, hovered, active bool) {
+	cx, cy := s.thumbCenter(bounds, v)
+	th := theme.Current()
+	ring := th.Primary
+	if s.Disabled {
+		ring = th.Border
+	} else if hovered {
+		ring = th.PrimaryHover
+	}
+	fillCircle(canvas, cx, cy, sliderThumbRadius, ring)
+	inner := int32(sliderThumbRadius - 2)
+	if active && s.isFocused {
+		inner = sliderThumbRadius - 4
+	}
+	fillCircle(canvas, cx, cy, inner, th.Background)
+}
+
+// format returns the text shown for value v.
+func (s *sliderBase) format(v float64) string {
+	if s.Format != nil {
+		return s.Format(v)
+	}
+	// As many decimals as the step has
+	decimals := 2
+	if s.Step > 0 {
+		decimals = 0
+		if str := strconv.FormatFloat(s.Step, 'f', -1, 64); strings.Contains(str, ".") {
+			decimals = len(str) - strings.Index(str, ".") - 1
+		}
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// showTip shows the value tooltip beside the thumb for value v.
+func (s *sliderBase) showTip(bounds layout.Rect, v float64) {
+	overlay := ActiveOverlay()
+	if !s.ShowTooltip || overlay == nil {
+		return
+	}
+	if s.tip == nil {
+		s.tip = &Popup{Content: newTooltipBox(), KeepFocus: true}
+	}
+	box := s.tip.Content.(*tooltipBox)
+	box.Text = s.format(v)
+	w, h := box.GetPreferredSize()
+	cx, cy := s.thumbCenter(bounds, v)
+	x, y := cx-w/2, cy-sliderThumbRadius-6-h // Above the thumb
+	if s.Orientation == Vertical {
+		x, y = cx-sliderThumbRadius-6-w, cy-h/2 // Left of it
+	}
+	ob := overlay.Bounds
+	x = max(ob.X, min(x, ob.X+ob.Width-w))
+	y = max(ob.Y, min(y, ob.Y+ob.Height-h))
+	box.SetBounds(x, y, w, h)
+	if !s.tip.IsOpen() {
+		overlay.Show(s.tip)
+	}
+}
+
+func (s *sliderBase) hideTip() {
+	if s.tip != nil {
+		s.tip.Close()
+	}
+}
+
+// Slider selects a value from a continuous or stepped range by dragging a
+// thumb along a track.
+type Slider struct {
+	BaseComponent
+	sliderBase
+	Value    float64
+	OnChange func(value float64)
+
+	dragging  bool
+	grab      int32 // Pointer offset from the thumb center while dragging
+	isHovered bool  // Over the thumb
+}
+
+func NewSlider(orientation Orientation, minimum, maximum float64) *Slider {
+	s := &Slider{sliderBase: newSliderBase(orientation, minimum, maximum), Value: minimum}
+	s.SetBounds(0, 0, 200, 200)
+	w, h := s.GetPreferredSize()
+	s.SetBounds(0, 0, w, h)
+	s.Visible = true
+	return s
+}
+
+// SetValue sets the value, clamped to the range and snapped to the step,
+// and calls OnChange if it changed.
+func (s *Slider) SetValue(v float64) {
+	v = s.clamp(v)
+	if v == s.Value {
+		return
+	}
+	s.Value = v
+	s.RequestRepaint()
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+func (s *Slider) GetPreferredSize() (int32, int32) {
+	return s.preferredSize(s.Bounds)
+}
+
+func (s *Slider) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	s.drawTrack(canvas, s.Bounds, s.Min, s.Value)
+	s.drawThumb(canvas, s.Bounds, s.Value, s.isHovered || s.dragging, true)
+	s.RepaintRequested = false
+}
+
+func (s *Slider) OnFocus() {
+	s.isFocused = true
+	s.RequestRepaint()
+}
+
+func (s *Slider) OnBlur() {
+	s.isFocused = false
+	s.RequestRepaint()
+}
+
+// overThumb reports whether a point is on the thumb.
+func (s *Slider) overThumb(x, y int32) bool {
+	cx, cy := s.thumbCenter(s.Bounds, s.Value)
+	dx, dy := x-cx, y-cy
+	return dx*dx+dy*dy <= sliderThumbRadius*sliderThumbRadius
+}
+
+func (s *Slider) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !s.Bounds.Contains(data.X, data.Y) || s.Disabled {
+			return ok && s.Bounds.Contains(data.X, data.Y)
+		}
+		// Grab the thumb where it was hit, or jump to the pointer
+		s.grab = 0
+		if s.overThumb(data.X, data.Y) {
+			cx, cy := s.thumbCenter(s.Bounds, s.Value)
+			s.grab = s.along(data.X, data.Y) - s.along(cx, cy)
+		} else {
+			s.SetValue(s.valueAt(s.Bounds, data.X, data.Y))
+		}
+		s.dragging = true
+		SetCapture(s)
+		s.showTip(s.Bounds, s.Value)
+		s.RequestRepaint()
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if s.dragging {
+			if s.Orientation == Vertical {
+				data.Y -= s.grab
+			} else {
+				data.X -= s.grab
+			}
+			s.SetValue(s.valueAt(s.Bounds, data.X, data.Y))
+			s.showTip(s.Bounds, s.Value)
+			return true
+		}
+		if hovered := s.overThumb(data.X, data.Y); hovered != s.isHovered {
+			s.isHovered = hovered
+			s.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if s.dragging {
+			s.dragging = false
+			ReleaseCapture()
+			s.hideTip()
+			s.RequestRepaint()
+			return true
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !s.isFocused || s.Disabled {
+			return false
+		}
+		if v, ok := s.keyStep(data.VirtualKeyCode, s.Value); ok {
+			s.SetValue(v)
+			return true
+		}
+	}
+	return false
+}
+
+// RangeSlider selects a range with two thumbs, Low and High. The thumb
+// last clicked is moved by the keyboard.
+type RangeSlider struct {
+	BaseComponent
+	sliderBase
+	Low, High float64
+	OnChange  func(low, high float64)
+
+	active   int // Thumb moved by the keyboard: 0 low, 1 high
+	dragging bool
+	grab     int32
+	hovered  int // Thumb under the pointer, or -1
+}
+
+func NewRangeSlider(orientation Orientation, minimum, maximum float64) *RangeSlider {
+	s := &RangeSlider{sliderBase: newSliderBase(orientation, minimum, maximum), Low: minimum, High: maximum, hovered: -1}
+	s.SetBounds(0, 0, 200, 200)
+	w, h := s.GetPreferredSize()
+	s.SetBounds(0, 0, w, h)
+	s.Visible = true
+	return s
+}
+
+// SetRange sets both values, clamped to the range, snapped to the step and
+// ordered, and calls OnChange if they changed.
+func (s *RangeSlider) SetRange(low, high float64) {
+	low, high = s.clamp(low), s.clamp(high)
+	if low > high {
+		low, high = high, low
+	}
+	if low == s.Low && high == s.High {
+		return
+	}
+	s.Low, s.High = low, high
+	s.RequestRepaint()
+	if s.OnChange != nil {
+		s.OnChange(low, high)
+	}
+}
+
+// value returns the value of thumb i.
+func (s *RangeSlider) value(i int) float64 {
+	if i == 0 {
+		return s.Low
+	}
+	return s.High
+}
+
+// setThumb moves thumb i to v without passing the other thumb.
+func (s *RangeSlider) setThumb(i int, v float64) {
+	if i == 0 {
+		s.SetRange(min(v, s.High), s.High)
+	} else {
+		s.SetRange(s.Low, max(v, s.Low))
+	}
+}
+
+// thumbAt returns the thumb under a point, or -1. Where they overlap, the
+// one that can move toward the pointer wins.
+func (s *RangeSlider) thumbAt(x, y int32) int {
+	hit := -1
+	for i := 0; i < 2; i++ {
+		cx, cy := s.thumbCenter(s.Bounds, s.value(i))
+		dx, dy := x-cx, y-cy
+		if dx*dx+dy*dy <= sliderThumbRadius*sliderThumbRadius {
+			if hit >= 0 && s.Low == s.High {
+				// Stacked thumbs: pick by the side of the pointer
+				if s.valueAt(s.Bounds, x, y) < s.Low {
+					return 0
+				}
+				return 1
+			}
+			hit = i
+		}
+	}
+	return hit
+}
+
+// nearest returns the thumb closest to value v.
+func (s *RangeSlider) nearest(v float64) int {
+	if math.Abs(v-s.Low) <= math.Abs(v-s.High) && v <= s.High {
+		return 0
+	}
+	return 1
+}
+
+func (s *RangeSlider) GetPreferredSize() (int32, int32) {
+	return s.preferredSize(s.Bounds)
+}
+
+func (s *RangeSlider) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	s.drawTrack(canvas, s.Bounds, s.Low, s.High)
+	// The active thumb is drawn last, on top
+	other := 1 - s.active
+	s.drawThumb(canvas, s.Bounds, s.value(other), s.hovered == other, false)
+	s.drawThumb(canvas, s.Bounds, s.value(s.active), s.hovered == s.active || s.dragging, true)
+	s.RepaintRequested = false
+}
+
+func (s *RangeSlider) OnFocus() {
+	s.isFocused = true
+	s.RequestRepaint()
+}
+
+func (s *RangeSlider) OnBlur() {
+	s.isFocused = false
+	s.RequestRepaint()
+}
+
+func (s *RangeSlider) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !s.Bounds.Contains(data.X, data.Y) || s.Disabled {
+			return ok && s.Bounds.Contains(data.X, data.Y)
+		}
+		s.grab = 0
+		if i := s.thumbAt(data.X, data.Y); i >= 0 {
+			s.active = i
+			cx, cy := s.thumbCenter(s.Bounds, s.value(i))
+			s.grab = s.along(data.X, data.Y) - s.along(cx, cy)
+		} else {
+			v := s.valueAt(s.Bounds, data.X, data.Y)
+			s.active = s.nearest(v)
+			s.setThumb(s.active, v)
+		}
+		s.dragging = true
+		SetCapture(s)
+		s.showTip(s.Bounds, s.value(s.active))
+		s.RequestRepaint()
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if s.dragging {
+			if s.Orientation == Vertical {
+				data.Y -= s.grab
+			} else {
+				data.X -= s.grab
+			}
+			s.setThumb(s.active, s.valueAt(s.Bounds, data.X, data.Y))
+			s.showTip(s.Bounds, s.value(s.active))
+			return true
+		}
+		if i := s.thumbAt(data.X, data.Y); i != s.hovered {
+			s.hovered = i
+			s.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if s.dragging {
+			s.dragging = false
+			ReleaseCapture()
+			s.hideTip()
+			s.RequestRepaint()
+			return true
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !s.isFocused || s.Disabled {
+			return false
+		}
+		if v, ok := s.keyStep(data.VirtualKeyCode, s.value(s.active)); ok {
+			s.setThumb(s.active, v)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+package component
+
+import (
+	"math"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// SplitCollapse tells which pane of a SplitPane is collapsed.
+type SplitCollapse int
+
+const (
+	CollapseNone SplitCollapse = iota
+	CollapseFirst
+	CollapseSecond
+)
+
+// SplitPane shows two components separated by a divider that can be
+// dragged to resize them. Horizontal puts First left of Second, Vertical
+// puts First above Second. The split is kept as a ratio, so resizing the
+// pane (e.g. with the window) keeps the proportions; a double click on the
+// divider restores DefaultRatio. Split panes can be nested.
+type SplitPane struct {
+	BaseComponent
+	Orientation   Orientation
+	First, Second Component
+	Ratio         float64 // Share of First in the space beside the divider
+	DefaultRatio  float64 // Restored by a double click on the divider
+	MinFirst      int32   // Minimum pane sizes in pixels
+	MinSecond     int32
+	DividerSize   int32
+	Collapsible   bool          // Dragging a pane below half its minimum collapses it
+	Collapsed     SplitCollapse // See SetCollapsed
+	BgColor       uint32        // Of the divider; the theme's surface colour if 0
+
+	OnRatioChanged func(ratio float64)
+
+	dragging   bool
+	dragOffset int32 // Pointer position in the divider
+	hovered    bool
+}
+
+func NewSplitPane(orientation Orientation, first, second Component) *SplitPane {
+	s := &SplitPane{
+		Orientation:  orientation,
+		First:        first,
+		Second:       second,
+		Ratio:        0.5,
+		DefaultRatio: 0.5,
+		MinFirst:     40,
+		MinSecond:    40,
+		DividerSize:  6,
+	}
+	s.Visible = true
+	return s
+}
+
+func (s *SplitPane) SetBounds(x, y, width, height int32) {
+	s.BaseComponent.SetBounds(x, y, width, height)
+	s.LayoutChildren()
+}
+
+// SetRatio moves the divider, expanding a collapsed pane.
+func (s *SplitPane) SetRatio(ratio float64) {
+	s.Collapsed = CollapseNone
+	s.setRatio(ratio)
+}
+
+func (s *SplitPane) setRatio(ratio float64) {
+	ratio = math.Max(0, math.Min(ratio, 1))
+	changed := ratio != s.Ratio
+	s.Ratio = ratio
+	s.LayoutChildren()
+	if changed && s.OnRatioChanged != nil {
+		s.OnRatioChanged(ratio)
+	}
+}
+
+// SetCollapsed collapses a pane, giving all the space to the other, or
+// expands it again with CollapseNone. The ratio is kept for expanding.
+func (s *SplitPane) SetCollapsed(c SplitCollapse) {
+	s.Collapsed = c
+	s.LayoutChildren()
+}
+
+// ResetRatio expands a collapsed pane and restores DefaultRatio.
+func (s *SplitPane) ResetRatio() {
+	s.SetRatio(s.DefaultRatio)
+}
+
+// length returns the size along the split axis and the origin on it.
+func (s *SplitPane) length() (origin, size int32) {
+	if s.Orientation == Horizontal {
+		return s.Bounds.X, s.Bounds.Width
+	}
+	return s.Bounds.Y, s.Bounds.Height
+}
+
+// firstSize returns the size of the first pane for the current ratio,
+// honouring the minimum sizes where there is room for them.
+func (s *SplitPane) firstSize() int32 {
+	_, size := s.length()
+	avail := max(0, size-s.DividerSize)
+	switch s.Collapsed {
+	case CollapseFirst:
+		return 0
+	case CollapseSecond:
+		return avail
+	}
+	first := int32(math.Round(s.Ratio * float64(avail)))
+	first = min(first, avail-s.MinSecond)
+	first = max(first, s.MinFirst)
+	return max(0, min(first, avail))
+}
+
+// DividerBounds returns the area of the divider.
+func (s *SplitPane) DividerBounds() layout.Rect {
+	b := s.Bounds
+	first := s.firstSize()
+	if s.Orientation == Horizontal {
+		return layout.Rect{X: b.X + first, Y: b.Y, Width: s.DividerSize, Height: b.Height}
+	}
+	return layout.Rect{X: b.X, Y: b.Y + first, Width: b.Width, Height: s.DividerSize}
+}
+
+// LayoutChildren positions the panes around the divider.
+func (s *SplitPane) LayoutChildren() {
+	b := s.Bounds
+	d := s.DividerBounds()
+	if s.First != nil {
+		if s.Orientation == Horizontal {
+			s.First.SetBounds(b.X, b.Y, d.X-b.X, b.Height)
+		} else {
+			s.First.SetBounds(b.X, b.Y, b.Width, d.Y-b.Y)
+		}
+	}
+	if s.Second != nil {
+		if s.Orientation == Horizontal {
+			x := d.X + d.Width
+			s.Second.SetBounds(x, b.Y, max(0, b.X+b.Width-x), b.Height)
+		} else {
+			y := d.Y + d.Height
+			s.Second.SetBounds(b.X, y, b.Width, max(0, b.Y+b.Height-y))
+		}
+	}
+	s.RequestRepaint()
+}
+
+// panes returns the panes that are shown.
+func (s *SplitPane) panes() []Component {
+	var panes []Component
+	if s.First != nil && s.Collapsed != CollapseFirst {
+		panes = append(panes, s.First)
+	}
+	if s.Second != nil && s.Collapsed != CollapseSecond {
+		panes = append(panes, s.Second)
+	}
+	return panes
+}
+
+func (s *SplitPane) ChildComponents() []Component {
+	return s.panes()
+}
+
+func (s *SplitPane) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	for _, pane := range s.panes() {
+		r := pane.GetBounds()
+		canvas.PushClip(r.X, r.Y, r.Width, r.Height)
+		pane.Render(canvas)
+		canvas.PopClip()
+	}
+
+	// Draw Divider with a grip of three dots
+	d := s.DividerBounds()
+	t := theme.Current()
+	color := theme.Or(s.BgColor, t.Surface)
+	if s.dragging || s.hovered {
+		color = t.Selection
+	}
+	canvas.FillRect(d.X, d.Y, d.Width, d.Height, color)
+	cx, cy := d.X+d.Width/2, d.Y+d.Height/2
+	for i := int32(-1); i <= 1; i++ {
+		if s.Orientation == Horizontal {
+			canvas.FillRect(cx-1, cy+i*5-1, 2, 2, t.TextDisabled)
+		} else {
+			canvas.FillRect(cx+i*5-1, cy-1, 2, 2, t.TextDisabled)
+		}
+	}
+	s.RepaintRequested = false
+}
+
+func (s *SplitPane) CursorAt(x, y int32) Cursor {
+	if !s.dragging && !s.DividerBounds().Contains(x, y) {
+		return CursorArrow
+	}
+	if s.Orientation == Horizontal {
+		return CursorSizeWE
+	}
+	return CursorSizeNS
+}
+
+func (s *SplitPane) FindComponentAt(x, y int32) Component {
+	if !s.Visible || !s.Bounds.Contains(x, y) {
+		return nil
+	}
+	for _, pane := range s.panes() {
+		if container, ok := pane.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil {
+				return found
+			}
+		} else if pane.GetBounds().Contains(x, y) {
+			return pane
+		}
+	}
+	return s
+}
+
+// pointerPos returns a mouse position along the split axis.
+func (s *SplitPane) pointerPos(data event.MouseEvent) int32 {
+	if s.Orientation == Horizontal {
+		return data.X
+	}
+	return data.Y
+}
+
+// drag moves the divider so that the pointer stays on it, collapsing a
+// pane dragged below half its minimum size.
+func (s *SplitPane) drag(pos int32) {
+	origin, size := s.length()
+	avail := max(1, size-s.DividerSize)
+	first := pos - s.dragOffset - origin
+	switch {
+	case s.Collapsible && first < s.MinFirst/2:
+		s.SetCollapsed(CollapseFirst)
+	case s.Collapsible && avail-first < s.MinSecond/2:
+		s.SetCollapsed(CollapseSecond)
+	default:
+		first = max(s.MinFirst, min(first, avail-s.MinSecond))
+		s.SetRatio(float64(first) / float64(avail))
+	}
+}
+
+func (s *SplitPane) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !s.DividerBounds().Contains(data.X, data.Y) {
+			break
+		}
+		if data.Clicks == 2 {
+			s.ResetRatio()
+			return true
+		}
+		d := s.DividerBounds()
+		s.dragOffset = data.X - d.X
+		if s.Orientation == Vertical {
+			s.dragOffset = data.Y - d.Y
+		}
+		s.dragging = true
+		SetCapture(s)
+		s.RequestRepaint()
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			break
+		}
+		if s.dragging {
+			s.drag(s.pointerPos(data))
+			return true
+		}
+		if hovered := s.DividerBounds().Contains(data.X, data.Y); hovered != s.hovered {
+			s.hovered = hovered
+			s.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if s.dragging {
+			s.dragging = false
+			ReleaseCapture()
+			s.RequestRepaint()
+			return true
+		}
+	}
+
+	// Dispatch to panes in reverse order (top-most first), like a Panel
+	panes := s.panes()
+	for i := len(panes) - 1; i >= 0; i-- {
+		if panes[i].OnEvent(evt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,944 @@
+package component
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// TableModel supplies the cells of a Table.
+type TableModel interface {
+	RowCount() int
+	CellText(row, col int) string
+}
+
+// TableEditor is implemented by models whose cells can be edited in place.
+// SetCellText returns false to reject the new text.
+type TableEditor interface {
+	SetCellText(row, col int, text string) bool
+}
+
+// TableSorter is implemented by models that compare cells themselves.
+// By default cells are compared as numbers if both parse as one, and as
+// case-insensitive text otherwise.
+type TableSorter interface {
+	CompareCells(col, rowA, rowB int) int
+}
+
+// CellRenderer draws a cell; text is the model's text for it.
+type CellRenderer func(canvas *render.Canvas, cell layout.Rect, text string, row, col int, selected bool)
+
+type TableColumn struct {
+	Header   string
+	Field    int // Model column shown
+	Width    int32
+	MinWidth int32
+	Align    layout.FlexAlign // AlignStart, AlignCenter or AlignEnd
+	Renderer CellRenderer     // nil draws the text
+	Editable bool             // Requires the model to implement TableEditor
+}
+
+// SortKey sorts by a model column.
+type SortKey struct {
+	Field      int
+	Descending bool
+}
+
+const tableResizeGrip = int32(4)
+
+// Table is a virtualized data grid. Clicking a header sorts by it
+// (Shift+click adds a secondary key), dragging a header edge resizes a
+// column and dragging a header reorders columns. Row indices in the API
+// are model rows.
+type Table struct {
+	BaseComponent
+	Model         TableModel
+	Columns       []*TableColumn // Display order
+	RowHeight     int32
+	HeaderHeight  int32
+	SelectionMode SelectionMode
+	Font          *render.Font
+	WheelStep     int32
+	Smooth        bool
+	GridLines     bool
+
+	// Colours are the theme's if 0
+	BgColor       uint32
+	AltRowColor   uint32
+	HeaderColor   uint32
+	GridColor     uint32
+	SelectedColor uint32
+
+	OnSelectionChanged func()
+	OnActivate         func(row int) // Double click or Enter
+	OnSort             func(keys []SortKey)
+	OnCellEdited       func(row, field int, text string)
+
+	sortKeys  []SortKey
+	order     []int // View row -> model row, nil when unsorted
+	inverse   []int // Model row -> view row
+	metrics   rowMetrics
+	selection itemSelection // In view rows
+	hover     int
+	isFocused bool
+
+	vBar, hBar     *ScrollBar
+	header, body   layout.Rect
+	offsetY        float64
+	targetY        float64
+	offsetX        int32
+	lastTick       time.Time
+	totalColsWidth int32
+
+	// Header interaction
+	resizeCol   int
+	resizeX     int32
+	resizeW     int32
+	pressedCol  int
+	pressX      int32
+	draggingCol bool
+	dropIndex   int
+
+	// In-cell editing
+	editor  *TextBox
+	editRow int // View row
+	editCol int // Display column
+}
+
+func NewTable(width, height int32, model TableModel) *Table {
+	t := &Table{
+		Model:        model,
+		RowHeight:    24,
+		HeaderHeight: 28,
+		WheelStep:    48,
+		Smooth:       true,
+		GridLines:    true,
+		selection:    newItemSelection(),
+		hover:        -1,
+		resizeCol:    -1,
+		pressedCol:   -1,
+		vBar:         NewScrollBar(Vertical),
+		hBar:         NewScrollBar(Horizontal),
+	}
+	t.vBar.OnScroll = func(v float64) {
+		t.offsetY, t.targetY = v, v
+		StopAnimation(t)
+	}
+	t.hBar.OnScroll = func(v float64) { t.offsetX = int32(v) }
+	t.SetBounds(0, 0, width, height)
+	t.Visible = true
+	t.Reload()
+	return t
+}
+
+// AddColumn appends a column showing the next model column.
+func (t *Table) AddColumn(header string, width int32) *TableColumn {
+	c := &TableColumn{Header: header, Field: len(t.Columns), Width: width, MinWidth: 20, Align: layout.AlignStart}
+	t.Columns = append(t.Columns, c)
+	t.RequestRepaint()
+	return c
+}
+
+// Reload re-reads the row count and re-applies the sort order. Call it
+// after the data changed.
+func (t *Table) Reload() {
+	t.cancelEdit()
+	count := 0
+	if t.Model != nil {
+		count = t.Model.RowCount()
+	}
+	t.metrics.reset(count, t.RowHeight, nil)
+	if t.applySort() {
+		t.selectionChanged()
+	}
+	t.RequestRepaint()
+}
+
+func (t *Table) modelRow(view int) int {
+	if t.order == nil {
+		return view
+	}
+	return t.order[view]
+}
+
+func (t *Table) viewRow(model int) int {
+	if t.order == nil || model < 0 || model >= len(t.inverse) {
+		return model
+	}
+	return t.inverse[model]
+}
+
+// Sorting
+
+func (t *Table) SortKeys() []SortKey {
+	return append([]SortKey(nil), t.sortKeys...)
+}
+
+// SortBy sorts the rows by the given keys, most significant first. The
+// sort is stable, and no keys restore the model order.
+func (t *Table) SortBy(keys ...SortKey) {
+	t.sortKeys = append([]SortKey(nil), keys...)
+	t.applySort()
+	if t.OnSort != nil {
+		t.OnSort(t.SortKeys())
+	}
+}
+
+// toggleSort handles a header click: a new primary key, or the direction
+// flipped if it already is one. With add the key is added or flipped in
+// place instead.
+func (t *Table) toggleSort(field int, add bool) {
+	keys := t.SortKeys()
+	idx := -1
+	for i, k := range keys {
+		if k.Field == field {
+			idx = i
+		}
+	}
+	switch {
+	case add && idx >= 0:
+		keys[idx].Descending = !keys[idx].Descending
+	case add:
+		keys = append(keys, SortKey{Field: field})
+	case idx == 0 && len(keys) == 1:
+		keys[0].Descending = !keys[0].Descending
+	default:
+		keys = []SortKey{{Field: field}}
+	}
+	t.SortBy(keys...)
+}
+
+// applySort sorts the rows again, e.g. after SortBy or Reload, and
+// reports whether selected rows were dropped because the row count
+// shrank.
+func (t *Table) applySort() bool {
+	// Selection and current row follow their model rows, found with the
+	// order they were selected in. Rows gone from the model are dropped;
+	// if the old order cannot map a row, the selection is cleared.
+	count := t.metrics.count
+	valid := true
+	oldModelRow := func(view int) int {
+		switch {
+		case t.order == nil:
+		case view >= 0 && view < len(t.order):
+			view = t.order[view]
+		default:
+			valid = false
+		}
+		return view
+	}
+	indices := t.selection.set.indices()
+	selected := make([]int, 0, len(indices))
+	for _, v := range indices {
+		if m := oldModelRow(v); m < count {
+			selected = append(selected, m)
+		}
+	}
+	current, anchor := -1, -1
+	if t.selection.current >= 0 {
+		current = min(oldModelRow(t.selection.current), count-1)
+	}
+	if t.selection.anchor >= 0 {
+		anchor = min(oldModelRow(t.selection.anchor), count-1)
+	}
+	if !valid {
+		selected, current, anchor = selected[:0], -1, -1
+	}
+
+	t.order, t.inverse = nil, nil
+	if len(t.sortKeys) > 0 {
+		t.order = make([]int, t.metrics.count)
+		for i := range t.order {
+			t.order[i] = i
+		}
+		sort.SliceStable(t.order, func(i, j int) bool {
+			return t.compareRows(t.order[i], t.order[j]) < 0
+		})
+		t.inverse = make([]int, len(t.order))
+		for v, m := range t.order {
+			t.inverse[m] = v
+		}
+	}
+
+	// Map back to view rows
+	for i, m := range selected {
+		selected[i] = t.viewRow(m)
+	}
+	t.selection.set.setIndices(selected)
+	t.selection.current, t.selection.anchor = t.viewRow(current), t.viewRow(anchor)
+	t.RequestRepaint()
+	return len(selected) != len(indices)
+}
+
+func (t *Table) compareRows(a, b int) int {
+	sorter, custom := t.Model.(TableSorter)
+	for _, k := range t.sortKeys {
+		var c int
+		if custom {
+			c = sorter.CompareCells(k.Field, a, b)
+		} else {
+			c = compareCellText(t.Model.CellText(a, k.Field), t.Model.CellText(b, k.Field))
+		}
+		if k.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareCellText(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// Columns
+
+// MoveColumn moves the column at display index from to index to.
+func (t *Table) MoveColumn(from, to int) {
+	if from < 0 || from >= len(t.Columns) || to < 0 || to >= len(t.Columns) || from == to {
+		return
+	}
+	t.cancelEdit()
+	c := t.Columns[from]
+	t.Columns = append(t.Columns[:from], t.Columns[from+1:]...)
+	t.Columns = append(t.Columns[:to], append([]*TableColumn{c}, t.Columns[to:]...)...)
+	t.RequestRepaint()
+}
+
+// columnX returns the left edge of display column i on screen.
+func (t *Table) columnX(i int) int32 {
+	x := t.body.X - t.offsetX
+	for _, c := range t.Columns[:i] {
+		x += c.Width
+	}
+	return x
+}
+
+// columnAt returns the display column at screen x, or -1.
+func (t *Table) columnAt(x int32) int {
+	left := t.body.X - t.offsetX
+	for i, c := range t.Columns {
+		if x >= left && x < left+c.Width {
+			return i
+		}
+		left += c.Width
+	}
+	return -1
+}
+
+// resizeGripAt returns the column whose right edge is at screen x, or -1.
+func (t *Table) resizeGripAt(x int32) int {
+	right := t.body.X - t.offsetX
+	for i, c := range t.Columns {
+		right += c.Width
+		if x >= right-tableResizeGrip && x <= right+tableResizeGrip {
+			return i
+		}
+	}
+	return -1
+}
+
+// dropIndexAt returns where a dragged column would be inserted.
+func (t *Table) dropIndexAt(x int32) int {
+	left := t.body.X - t.offsetX
+	for i, c := range t.Columns {
+		if x < left+c.Width/2 {
+			return i
+		}
+		left += c.Width
+	}
+	return len(t.Columns)
+}
+
+// Scrolling and geometry
+
+// updateLayout computes the header and body areas and the scroll bars.
+func (t *Table) updateLayout() {
+	if t.Model != nil && t.Model.RowCount() != t.metrics.count {
+		t.Reload()
+	}
+	t.totalColsWidth = 0
+	for _, c := range t.Columns {
+		t.totalColsWidth += c.Width
+	}
+
+	inner := layout.UniformInsets(1).Shrink(t.Bounds)
+	rows := t.metrics.total()
+	bodyH := inner.Height - t.HeaderHeight
+	showV := rows > int64(bodyH)
+	showH := t.totalColsWidth > inner.Width-barSize(showV)
+	if !showV && rows > int64(bodyH-barSize(showH)) {
+		showV = true
+	}
+	w := max(0, inner.Width-barSize(showV))
+	t.header = layout.Rect{X: inner.X, Y: inner.Y, Width: w, Height: t.HeaderHeight}
+	t.body = layout.Rect{X: inner.X, Y: inner.Y + t.HeaderHeight, Width: w, Height: max(0, bodyH-barSize(showH))}
+
+	maxY := float64(max(0, rows-int64(t.body.Height)))
+	t.offsetY, t.targetY = clampScroll(t.offsetY, maxY), clampScroll(t.targetY, maxY)
+	t.offsetX = max(0, min(t.offsetX, t.totalColsWidth-t.body.Width))
+
+	t.vBar.Visible, t.hBar.Visible = showV, showH
+	t.vBar.SetBounds(inner.X+w, t.body.Y, scrollBarThickness, t.body.Height)
+	t.vBar.Max, t.vBar.PageSize, t.vBar.Value = float64(rows), float64(t.body.Height), t.offsetY
+	t.vBar.SmallStep = float64(t.WheelStep)
+	t.hBar.SetBounds(inner.X, t.body.Y+t.body.Height, w, scrollBarThickness)
+	t.hBar.Max, t.hBar.PageSize, t.hBar.Value = float64(t.totalColsWidth), float64(w), float64(t.offsetX)
+}
+
+func (t *Table) scrollBy(dy float64) bool {
+	target := clampScroll(t.targetY+dy, float64(max(0, t.metrics.total()-int64(t.body.Height))))
+	if target == t.targetY {
+		return false
+	}
+	t.targetY = target
+	if !t.Smooth {
+		t.offsetY = target
+		t.RequestRepaint()
+		return true
+	}
+	t.lastTick = time.Now()
+	StartAnimation(t)
+	return true
+}
+
+func (t *Table) scrollXBy(dx int32) bool {
+	x := max(0, min(t.offsetX+dx, t.totalColsWidth-t.body.Width))
+	if x == t.offsetX {
+		return false
+	}
+	t.offsetX = x
+	t.RequestRepaint()
+	return true
+}
+
+func (t *Table) Tick(now time.Time) bool {
+	var done bool
+	t.offsetY, done = easeScroll(t.offsetY, t.targetY, now.Sub(t.lastTick))
+	t.lastTick = now
+	t.RequestRepaint()
+	return !done
+}
+
+// ScrollToRow scrolls a model row into view.
+func (t *Table) ScrollToRow(row int) {
+	t.scrollToView(t.viewRow(row))
+}
+
+func (t *Table) scrollToView(v int) {
+	if v < 0 || v >= t.metrics.count {
+		return
+	}
+	target := ensureSpan(t.targetY, float64(t.metrics.top(v)), float64(t.metrics.height(v)), float64(t.body.Height))
+	t.scrollBy(target - t.targetY)
+}
+
+// rowAt returns the view row at a window position in the body, or -1.
+func (t *Table) rowAt(x, y int32) int {
+	if !t.body.Contains(x, y) {
+		return -1
+	}
+	pos := int64(y-t.body.Y) + int64(t.offsetY)
+	if pos >= t.metrics.total() {
+		return -1
+	}
+	return t.metrics.indexAt(pos)
+}
+
+func (t *Table) cellRect(view, col int) layout.Rect {
+	return layout.Rect{
+		X:      t.columnX(col),
+		Y:      t.body.Y + int32(t.metrics.top(view)-int64(t.offsetY)),
+		Width:  t.Columns[col].Width,
+		Height: t.metrics.height(view),
+	}
+}
+
+// Selection
+
+func (t *Table) IsSelected(row int) bool {
+	return t.selection.set.contains(t.viewRow(row))
+}
+
+// SelectedRow returns the first selected model row in view order, or -1.
+func (t *Table) SelectedRow() int {
+	if v := t.selection.set.first(); v >= 0 {
+		return t.modelRow(v)
+	}
+	return -1
+}
+
+// SelectedRows returns the selected model rows in view order.
+func (t *Table) SelectedRows() []int {
+	views := t.selection.set.indices()
+	for i, v := range views {
+		views[i] = t.modelRow(v)
+	}
+	return views
+}
+
+// SelectRow selects only a model row and scrolls to it.
+func (t *Table) SelectRow(row int) {
+	v := t.viewRow(row)
+	if v < 0 || v >= t.metrics.count {
+		return
+	}
+	if t.selection.selectAt(SelectionSingle, v, 0) {
+		t.selectionChanged()
+	}
+	t.scrollToView(v)
+}
+
+func (t *Table) SelectAll() {
+	if t.SelectionMode != SelectionMulti || t.metrics.count == 0 {
+		return
+	}
+	t.selection.set.clear()
+	t.selection.set.add(0, t.metrics.count)
+	t.selectionChanged()
+}
+
+func (t *Table) ClearSelection() {
+	if t.selection.set.len() == 0 {
+		return
+	}
+	t.selection.set.clear()
+	t.selectionChanged()
+}
+
+func (t *Table) selectionChanged() {
+	t.RequestRepaint()
+	if t.OnSelectionChanged != nil {
+		t.OnSelectionChanged()
+	}
+}
+
+func (t *Table) moveTo(v int, mods uint32) {
+	if t.SelectionMode == SelectionNone {
+		t.selection.current = v
+	} else if t.selection.selectAt(t.SelectionMode, v, mods) {
+		t.selectionChanged()
+	}
+	t.scrollToView(v)
+	t.RequestRepaint()
+}
+
+func (t *Table) activate(v int) {
+	if t.OnActivate != nil && v >= 0 && v < t.metrics.count {
+		t.OnActivate(t.modelRow(v))
+	}
+}
+
+// Editing
+
+// EditCell starts editing a model row at a display column, if the column
+// is editable and the model implements TableEditor.
+func (t *Table) EditCell(row, col int) {
+	t.beginEdit(t.viewRow(row), col)
+}
+
+func (t *Table) beginEdit(v, col int) bool {
+	if _, ok := t.Model.(TableEditor); !ok || v < 0 || v >= t.metrics.count || col < 0 || col >= len(t.Columns) || !t.Columns[col].Editable {
+		return false
+	}
+	t.commitEdit()
+	t.editRow, t.editCol = v, col
+	t.editor = NewTextBox(t.Columns[col].Width)
+	t.editor.Font = t.Font
+	t.editor.Text = t.Model.CellText(t.modelRow(v), t.Columns[col].Field)
+	t.editor.SelectAll()
+	t.editor.OnFocus()
+	t.scrollToView(v)
+	t.RequestRepaint()
+	return true
+}
+
+// commitEdit stores the editor text and ends editing.
+func (t *Table) commitEdit() {
+	if t.editor == nil {
+		return
+	}
+	row, field, text := t.modelRow(t.editRow), t.Columns[t.editCol].Field, t.editor.Text
+	t.cancelEdit()
+	if t.Model.(TableEditor).SetCellText(row, field, text) && t.OnCellEdited != nil {
+		t.OnCellEdited(row, field, text)
+	}
+}
+
+func (t *Table) cancelEdit() {
+	if t.editor == nil {
+		return
+	}
+	t.editor.OnBlur()
+	t.editor = nil
+	t.RequestRepaint()
+}
+
+// Rendering
+
+func (t *Table) Render(canvas *render.Canvas) {
+	if !t.Visible {
+		return
+	}
+	t.updateLayout()
+	canvas.SetFont(t.Font)
+
+	b := t.Bounds
+	th := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(t.BgColor, th.Background))
+	t.renderBody(canvas)
+	t.renderHeader(canvas)
+
+	t.vBar.Render(canvas)
+	t.hBar.Render(canvas)
+	if t.vBar.Visible && t.hBar.Visible {
+		canvas.FillRect(t.body.X+t.body.Width, t.body.Y+t.body.Height, scrollBarThickness, scrollBarThickness, th.Surface)
+	}
+
+	borderColor := th.Border
+	if t.isFocused {
+		borderColor = th.Focus
+	}
+	drawRectOutline(canvas, b.X, b.Y, b.Width, b.Height, borderColor)
+	t.RepaintRequested = false
+}
+
+func (t *Table) renderHeader(canvas *render.Canvas) {
+	h := t.header
+	th := theme.Current()
+	headerColor := theme.Or(t.HeaderColor, th.Surface)
+	separator := theme.Mix(th.Border, headerColor, 0.4)
+	canvas.PushClip(h.X, h.Y, h.Width, h.Height)
+	canvas.FillRect(h.X, h.Y, h.Width, h.Height, headerColor)
+
+	for i, c := range t.Columns {
+		x := t.columnX(i)
+		if x >= h.X+h.Width || x+c.Width <= h.X {
+			continue
+		}
+		if i == t.pressedCol && !t.draggingCol {
+			canvas.FillRect(x, h.Y, c.Width, h.Height, theme.Mix(headerColor, th.Text, 0.1))
+		}
+		cell := layout.Rect{X: x, Y: h.Y, Width: c.Width, Height: h.Height}
+
+		// Sort indicator: arrow, plus priority with several keys
+		textCell := cell
+		for k, key := range t.sortKeys {
+			if key.Field != c.Field {
+				continue
+			}
+			textCell.Width -= 18
+			drawArrowGlyph(canvas, x+c.Width-18, h.Y, 14, h.Height, Vertical, key.Descending, th.Icon)
+			if len(t.sortKeys) > 1 {
+				label := strconv.Itoa(k + 1)
+				lw, _ := render.MeasureText(label, t.Font)
+				textCell.Width -= lw
+				canvas.DrawText(x+c.Width-18-lw, h.Y+2, label, th.TextSecondary)
+			}
+		}
+		canvas.PushClip(textCell.X, textCell.Y, textCell.Width, textCell.Height)
+		t.drawCellText(canvas, textCell, c.Header, c.Align, th.Text)
+		canvas.PopClip()
+
+		canvas.FillRect(x+c.Width-1, h.Y+4, 1, h.Height-8, separator)
+	}
+	canvas.FillRect(h.X, h.Y+h.Height-1, h.Width, 1, separator)
+
+	// Drop position while reordering
+	if t.draggingCol {
+		x := t.body.X - t.offsetX + t.totalColsWidth
+		if t.dropIndex < len(t.Columns) {
+			x = t.columnX(t.dropIndex)
+		}
+		canvas.FillRect(x-1, h.Y, 2, h.Height, th.Focus)
+	}
+	canvas.PopClip()
+}
+
+func (t *Table) renderBody(canvas *render.Canvas) {
+	bd := t.body
+	if t.metrics.count == 0 || bd.Height <= 0 {
+		return
+	}
+	th := theme.Current()
+	gridColor := theme.Or(t.GridColor, th.Divider)
+	canvas.PushClip(bd.X, bd.Y, bd.Width, bd.Height)
+	first := t.metrics.indexAt(int64(t.offsetY))
+	last := t.metrics.indexAt(int64(t.offsetY) + int64(bd.Height) - 1)
+
+	for v := first; v <= last; v++ {
+		y := bd.Y + int32(t.metrics.top(v)-int64(t.offsetY))
+		rh := t.metrics.height(v)
+		selected := t.selection.set.contains(v)
+		switch {
+		case selected:
+			canvas.FillRect(bd.X, y, bd.Width, rh, theme.Or(t.SelectedColor, th.Selection))
+		case v == t.hover:
+			canvas.FillRect(bd.X, y, bd.Width, rh, th.Hover)
+		case v%2 == 1:
+			canvas.FillRect(bd.X, y, bd.Width, rh, theme.Or(t.AltRowColor, theme.Mix(th.Background, th.Surface, 0.5)))
+		}
+
+		row := t.modelRow(v)
+		for i, c := range t.Columns {
+			cell := t.cellRect(v, i)
+			if cell.X >= bd.X+bd.Width || cell.X+cell.Width <= bd.X {
+				continue
+			}
+			text := t.Model.CellText(row, c.Field)
+			canvas.PushClip(cell.X, cell.Y, cell.Width, cell.Height)
+			if c.Renderer != nil {
+				c.Renderer(canvas, cell, text, row, c.Field, selected)
+			} else {
+				t.drawCellText(canvas, cell, text, c.Align, th.Text)
+			}
+			canvas.PopClip()
+			if t.GridLines {
+				canvas.FillRect(cell.X+cell.Width-1, y, 1, rh, gridColor)
+			}
+		}
+		if t.GridLines {
+			canvas.FillRect(bd.X, y+rh-1, min(bd.Width, t.totalColsWidth-t.offsetX), 1, gridColor)
+		}
+		if t.isFocused && v == t.selection.current && t.editor == nil {
+			drawRectOutline(canvas, bd.X, y, bd.Width, rh, th.Focus)
+		}
+	}
+
+	if t.editor != nil {
+		cell := t.cellRect(t.editRow, t.editCol)
+		t.editor.SetBounds(cell.X, cell.Y, cell.Width, cell.Height)
+		t.editor.Render(canvas)
+	}
+	canvas.PopClip()
+}
+
+// drawCellText draws text aligned horizontally and centered vertically.
+func (t *Table) drawCellText(canvas *render.Canvas, cell layout.Rect, text string, align layout.FlexAlign, color uint32) {
+	w, h := render.MeasureText(text, t.Font)
+	x := cell.X + 6
+	switch align {
+	case layout.AlignCenter:
+		x = cell.X + (cell.Width-w)/2
+	case layout.AlignEnd:
+		x = cell.X + cell.Width - w - 6
+	}
+	canvas.DrawText(x, cell.Y+(cell.Height-h)/2, text, color)
+}
+
+// Events
+
+func (t *Table) OnFocus() {
+	t.isFocused = true
+	t.RequestRepaint()
+}
+
+func (t *Table) OnBlur() {
+	t.isFocused = false
+	t.commitEdit()
+	t.RequestRepaint()
+}
+
+func (t *Table) AcceptsFocus() bool {
+	return true
+}
+
+func (t *Table) OnEvent(evt event.Event) bool {
+	if !t.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !t.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if data.DeltaX != 0 || data.Modifiers&event.ModShift != 0 {
+			d := data.DeltaX
+			if d == 0 {
+				d = -data.Delta
+			}
+			return t.scrollXBy(int32(d) * t.WheelStep / 120)
+		}
+		return t.scrollBy(-float64(data.Delta) * float64(t.WheelStep) / 120)
+
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !t.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if t.vBar.OnEvent(evt) || t.hBar.OnEvent(evt) {
+			return true
+		}
+		if t.editor != nil {
+			if t.editor.Bounds.Contains(data.X, data.Y) {
+				return t.editor.OnEvent(evt)
+			}
+			t.commitEdit()
+		}
+		if t.header.Contains(data.X, data.Y) {
+			if i := t.resizeGripAt(data.X); i >= 0 {
+				t.resizeCol, t.resizeX, t.resizeW = i, data.X, t.Columns[i].Width
+			} else if i := t.columnAt(data.X); i >= 0 {
+				t.pressedCol, t.pressX = i, data.X
+			}
+			t.RequestRepaint()
+			return true
+		}
+		if v := t.rowAt(data.X, data.Y); v >= 0 {
+			t.moveTo(v, data.Modifiers)
+			if data.Clicks == 2 && !t.beginEdit(v, t.columnAt(data.X)) {
+				t.activate(v)
+			}
+		}
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if t.vBar.OnEvent(evt) || t.hBar.OnEvent(evt) {
+			return true
+		}
+		switch {
+		case t.resizeCol >= 0:
+			c := t.Columns[t.resizeCol]
+			c.Width = max(c.MinWidth, t.resizeW+data.X-t.resizeX)
+			t.RequestRepaint()
+			return true
+		case t.pressedCol >= 0:
+			if !t.draggingCol && abs(data.X-t.pressX) > 5 {
+				t.draggingCol = true
+			}
+			if t.draggingCol {
+				t.dropIndex = t.dropIndexAt(data.X)
+				t.RequestRepaint()
+			}
+			return true
+		}
+		if t.editor != nil && t.editor.OnEvent(evt) {
+			return true
+		}
+		if v := t.rowAt(data.X, data.Y); v != t.hover {
+			t.hover = v
+			t.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if t.vBar.OnEvent(evt) || t.hBar.OnEvent(evt) {
+			return true
+		}
+		switch {
+		case t.resizeCol >= 0:
+			t.resizeCol = -1
+			return true
+		case t.pressedCol >= 0:
+			col := t.pressedCol
+			t.pressedCol = -1
+			if t.draggingCol {
+				t.draggingCol = false
+				to := t.dropIndex
+				if to > col {
+					to--
+				}
+				t.MoveColumn(col, to)
+			} else if t.header.Contains(data.X, data.Y) && t.columnAt(data.X) == col {
+				t.toggleSort(t.Columns[col].Field, data.Modifiers&event.ModShift != 0)
+			}
+			t.RequestRepaint()
+			return true
+		}
+		if t.editor != nil {
+			return t.editor.OnEvent(evt)
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !t.isFocused {
+			return false
+		}
+		if t.editor != nil {
+			switch data.VirtualKeyCode {
+			case 0x0D: // Enter
+				t.commitEdit()
+				return true
+			case 0x1B: // Escape
+				t.cancelEdit()
+				return true
+			}
+			return t.editor.OnEvent(evt)
+		}
+		if v, ok := t.metrics.navigate(data.VirtualKeyCode, t.selection.current, t.body.Height); ok {
+			mods := data.Modifiers
+			if mods&event.ModCtrl != 0 && mods&event.ModShift == 0 && t.SelectionMode == SelectionMulti {
+				t.selection.current = v
+				t.scrollToView(v)
+				t.RequestRepaint()
+				return true
+			}
+			t.moveTo(v, mods&^event.ModCtrl)
+			return true
+		}
+		switch data.VirtualKeyCode {
+		case 0x25: // Left
+			return t.scrollXBy(-t.WheelStep)
+		case 0x27: // Right
+			return t.scrollXBy(t.WheelStep)
+		case 0x71: // F2: edit the first editable cell of the current row
+			for i := range t.Columns {
+				if t.beginEdit(t.selection.current, i) {
+					return true
+				}
+			}
+		case 0x0D: // Enter
+			t.activate(t.selection.current)
+			return true
+		case 0x20: // Space
+			if v := t.selection.current; v >= 0 {
+				if t.SelectionMode == SelectionMulti && data.Modifiers&event.ModCtrl != 0 {
+					t.selection.toggle(v)
+					t.selectionChanged()
+				} else {
+					t.moveTo(v, 0)
+				}
+			}
+			return true
+		case 0x41: // A
+			if data.Modifiers&event.ModCtrl != 0 {
+				t.SelectAll()
+				return true
+			}
+		}
+
+	case event.EventChar:
+		if t.editor != nil {
+			return t.editor.OnEvent(evt)
+		}
+	}
+	return false
+}
@@ -0,0 +1,624 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	tabStripHeight = 30
+	tabPadding     = 12 // Left and right of a tab's contents
+	tabMinWidth    = 60
+	tabCloseSize   = 16
+	tabArrowWidth  = 20 // Overflow scroll buttons
+	tabDragSlop    = 4  // Pixels the pointer moves before a drag starts
+)
+
+// Tab is a page of a TabView. Its Content is created by Create the first
+// time the tab is selected if it is nil.
+type Tab struct {
+	Title    string
+	Icon     *Image // Drawn before the title, cropped to 16x16
+	Closable bool
+	Content  Component
+	Create   func() Component
+
+	width int32 // Laid out width
+}
+
+func NewTab(title string, content Component) *Tab {
+	return &Tab{Title: title, Content: content}
+}
+
+// NewLazyTab returns a tab whose content is only created when it is first
+// shown.
+func NewLazyTab(title string, create func() Component) *Tab {
+	return &Tab{Title: title, Create: create}
+}
+
+// TabView shows one of several pages, chosen with a strip of tabs. Tabs
+// scroll with arrow buttons or the wheel when they do not fit, and can be
+// dragged to reorder them. Ctrl+Tab and Ctrl+Page Down select the next
+// tab, Ctrl+Shift+Tab and Ctrl+Page Up the previous one, also while the
+// focus is in the page.
+type TabView struct {
+	BaseComponent
+	Tabs        []*Tab
+	Selected    int // -1 without tabs
+	Font        *render.Font
+	Reorderable bool   // Tabs can be dragged; true by default
+	BgColor     uint32 // Of the page; the theme's background colour if 0
+
+	OnTabChanged func(index int, tab *Tab)
+	OnTabClosing func(tab *Tab) bool // Return false to keep the tab open
+	OnTabClosed  func(tab *Tab)
+	OnTabMoved   func(from, to int)
+
+	scroll       int32 // Strip scroll offset
+	hovered      int   // Tab under the pointer, or -1
+	closeHovered bool  // The pointer is on the hovered tab's close button
+	isFocused    bool
+
+	dragIndex int // Tab pressed, or -1
+	dragX     int32
+	dragging  bool
+}
+
+func NewTabView(width, height int32) *TabView {
+	t := &TabView{
+		Selected:    -1,
+		Reorderable: true,
+		hovered:     -1,
+		dragIndex:   -1,
+	}
+	t.SetBounds(0, 0, width, height)
+	t.Visible = true
+	return t
+}
+
+// Add appends a tab with the given title and content and returns it.
+func (t *TabView) Add(title string, content Component) *Tab {
+	tab := NewTab(title, content)
+	t.InsertTab(len(t.Tabs), tab)
+	return tab
+}
+
+// AddTab appends a tab.
+func (t *TabView) AddTab(tab *Tab) {
+	t.InsertTab(len(t.Tabs), tab)
+}
+
+// InsertTab inserts a tab at index. The first tab added is selected.
+func (t *TabView) InsertTab(index int, tab *Tab) {
+	index = max(0, min(index, len(t.Tabs)))
+	t.Tabs = append(t.Tabs, nil)
+	copy(t.Tabs[index+1:], t.Tabs[index:])
+	t.Tabs[index] = tab
+	if t.Selected >= index {
+		t.Selected++
+	}
+	t.layoutTabs()
+	if t.Selected < 0 {
+		t.SetSelected(index)
+	}
+	t.RequestRepaint()
+}
+
+// RemoveTab removes the tab at index without asking OnTabClosing. If it
+// was selected, the next tab (or the last) is selected.
+func (t *TabView) RemoveTab(index int) {
+	if index < 0 || index >= len(t.Tabs) {
+		return
+	}
+	t.Tabs = append(t.Tabs[:index], t.Tabs[index+1:]...)
+	t.hovered = -1
+	t.dragIndex = -1
+	switch {
+	case index < t.Selected:
+		t.Selected--
+	case index == t.Selected:
+		t.Selected = -1
+		if len(t.Tabs) > 0 {
+			t.SetSelected(min(index, len(t.Tabs)-1))
+		} else if t.OnTabChanged != nil {
+			t.OnTabChanged(-1, nil)
+		}
+	}
+	t.layoutTabs()
+	t.RequestRepaint()
+}
+
+// CloseTab closes the tab at index as its close button does: OnTabClosing
+// may keep it open, and OnTabClosed is called once it is removed.
+func (t *TabView) CloseTab(index int) {
+	if index < 0 || index >= len(t.Tabs) {
+		return
+	}
+	tab := t.Tabs[index]
+	if t.OnTabClosing != nil && !t.OnTabClosing(tab) {
+		return
+	}
+	t.RemoveTab(index)
+	if t.OnTabClosed != nil {
+		t.OnTabClosed(tab)
+	}
+}
+
+// IndexOf returns the index of a tab, or -1.
+func (t *TabView) IndexOf(tab *Tab) int {
+	for i, other := range t.Tabs {
+		if other == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectedTab returns the selected tab, or nil.
+func (t *TabView) SelectedTab() *Tab {
+	if t.Selected < 0 || t.Selected >= len(t.Tabs) {
+		return nil
+	}
+	return t.Tabs[t.Selected]
+}
+
+// SetSelected shows the tab at index, creating its content if needed, and
+// scrolls the strip to it.
+func (t *TabView) SetSelected(index int) {
+	if index < 0 || index >= len(t.Tabs) || index == t.Selected {
+		return
+	}
+	t.Selected = index
+	tab := t.Tabs[index]
+	if tab.Content == nil && tab.Create != nil {
+		tab.Content = tab.Create()
+	}
+	t.layoutContent()
+	t.ensureTabVisible(index)
+	t.RequestRepaint()
+	if t.OnTabChanged != nil {
+		t.OnTabChanged(index, tab)
+	}
+}
+
+// MoveTab moves a tab to another position, keeping it selected if it was.
+func (t *TabView) MoveTab(from, to int) {
+	if from < 0 || from >= len(t.Tabs) || to < 0 || to >= len(t.Tabs) || from == to {
+		return
+	}
+	tab := t.Tabs[from]
+	t.Tabs = append(t.Tabs[:from], t.Tabs[from+1:]...)
+	t.Tabs = append(t.Tabs[:to], append([]*Tab{tab}, t.Tabs[to:]...)...)
+	switch {
+	case t.Selected == from:
+		t.Selected = to
+	case from < t.Selected && to >= t.Selected:
+		t.Selected--
+	case from > t.Selected && to <= t.Selected:
+		t.Selected++
+	}
+	t.RequestRepaint()
+	if t.OnTabMoved != nil {
+		t.OnTabMoved(from, to)
+	}
+}
+
+// selectBy moves the selection by delta tabs, wrapping around.
+func (t *TabView) selectBy(delta int) {
+	if n := len(t.Tabs); n > 0 {
+		t.SetSelected(((t.Selected+delta)%n + n) % n)
+	}
+}
+
+func (t *TabView) SetBounds(x, y, width, height int32) {
+	t.BaseComponent.SetBounds(x, y, width, height)
+	t.layoutTabs()
+	t.layoutContent()
+}
+
+// ContentBounds returns the area pages are shown in.
+func (t *TabView) ContentBounds() layout.Rect {
+	b := t.Bounds
+	return layout.Rect{X: b.X + 1, Y: b.Y + tabStripHeight, Width: max(0, b.Width-2), Height: max(0, b.Height-tabStripHeight-1)}
+}
+
+func (t *TabView) layoutContent() {
+	if tab := t.SelectedTab(); tab != nil && tab.Content != nil {
+		r := t.ContentBounds()
+		tab.Content.SetBounds(r.X, r.Y, r.Width, r.Height)
+	}
+}
+
+// layoutTabs measures the tabs.
+func (t *TabView) layoutTabs() {
+	for _, tab := range t.Tabs {
+		w, _ := render.MeasureText(tab.Title, t.Font)
+		w += 2 * tabPadding
+		if tab.Icon != nil {
+			w += 16 + 6
+		}
+		if tab.Closable {
+			w += tabCloseSize + 4
+		}
+		tab.width = max(w, tabMinWidth)
+	}
+	t.scroll = clampStrip(t.scroll, t.maxStripScroll())
+}
+
+func (t *TabView) tabsWidth() int32 {
+	var w int32
+	for _, tab := range t.Tabs {
+		w += tab.width
+	}
+	return w
+}
+
+// overflows reports whether the tabs need the scroll arrows.
+func (t *TabView) overflows() bool {
+	return t.tabsWidth() > t.Bounds.Width
+}
+
+// stripRect returns the part of the strip tabs are drawn in.
+func (t *TabView) stripRect() layout.Rect {
+	r := layout.Rect{X: t.Bounds.X, Y: t.Bounds.Y, Width: t.Bounds.Width, Height: tabStripHeight}
+	if t.overflows() {
+		r.Width = max(0, r.Width-2*tabArrowWidth)
+	}
+	return r
+}
+
+func (t *TabView) maxStripScroll() int32 {
+	return max(0, t.tabsWidth()-t.stripRect().Width)
+}
+
+func clampStrip(v, maximum int32) int32 {
+	return max(0, min(v, maximum))
+}
+
+// tabRect returns the bounds of a tab on screen.
+func (t *TabView) tabRect(index int) layout.Rect {
+	strip := t.stripRect()
+	x := strip.X - t.scroll
+	for i := 0; i < index; i++ {
+		x += t.Tabs[i].width
+	}
+	return layout.Rect{X: x, Y: strip.Y, Width: t.Tabs[index].width, Height: tabStripHeight}
+}
+
+func (t *TabView) closeRect(index int) layout.Rect {
+	r := t.tabRect(index)
+	return layout.Rect{X: r.X + r.Width - tabPadding/2 - tabCloseSize, Y: r.Y + (r.Height-tabCloseSize)/2 + 1, Width: tabCloseSize, Height: tabCloseSize}
+}
+
+func (t *TabView) ensureTabVisible(index int) {
+	r := t.tabRect(index)
+	strip := t.stripRect()
+	offset := t.scroll + r.X - strip.X // Tab position in the strip
+	scroll := t.scroll
+	if offset < scroll {
+		scroll = offset
+	} else if offset+r.Width > scroll+strip.Width {
+		scroll = offset + r.Width - strip.Width
+	}
+	t.scroll = clampStrip(scroll, t.maxStripScroll())
+}
+
+// tabAt returns the tab at a point of the strip, or -1.
+func (t *TabView) tabAt(x, y int32) int {
+	if !t.stripRect().Contains(x, y) {
+		return -1
+	}
+	for i := range t.Tabs {
+		if t.tabRect(i).Contains(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// arrowAt returns -1 or +1 for the scroll arrow at a point, or 0.
+func (t *TabView) arrowAt(x, y int32) int {
+	if !t.overflows() {
+		return 0
+	}
+	b := t.Bounds
+	if y < b.Y || y >= b.Y+tabStripHeight || x >= b.X+b.Width {
+		return 0
+	}
+	switch {
+	case x >= b.X+b.Width-tabArrowWidth:
+		return 1
+	case x >= b.X+b.Width-2*tabArrowWidth:
+		return -1
+	}
+	return 0
+}
+
+func (t *TabView) scrollStrip(delta int32) {
+	t.scroll = clampStrip(t.scroll+delta, t.maxStripScroll())
+	t.RequestRepaint()
+}
+
+func (t *TabView) Render(canvas *render.Canvas) {
+	if !t.Visible {
+		return
+	}
+	canvas.SetFont(t.Font)
+	t.layoutTabs() // Titles or the font may have changed
+	b := t.Bounds
+	strip := t.stripRect()
+
+	// Draw Page
+	th := theme.Current()
+	c := t.ContentBounds()
+	canvas.FillRect(b.X, b.Y, b.Width, tabStripHeight, th.Surface)
+	canvas.FillRect(c.X, c.Y, c.Width, c.Height, theme.Or(t.BgColor, th.Background))
+	drawRectOutline(canvas, b.X, b.Y+tabStripHeight-1, b.Width, b.Height-tabStripHeight+1, th.Border)
+	if tab := t.SelectedTab(); tab != nil && tab.Content != nil {
+		canvas.PushClip(c.X, c.Y, c.Width, c.Height)
+		tab.Content.Render(canvas)
+		canvas.PopClip()
+	}
+
+	// Draw Tabs
+	canvas.PushClip(strip.X, strip.Y, strip.Width, strip.Height)
+	for i, tab := range t.Tabs {
+		r := t.tabRect(i)
+		if r.X+r.Width < strip.X || r.X > strip.X+strip.Width {
+			continue
+		}
+		t.renderTab(canvas, i, tab, r)
+	}
+	canvas.PopClip()
+
+	// Draw Overflow Arrows
+	if t.overflows() {
+		ax := b.X + b.Width - 2*tabArrowWidth
+		canvas.FillRect(ax, b.Y, 2*tabArrowWidth, tabStripHeight-1, th.Surface)
+		leftColor, rightColor := th.Icon, th.Icon
+		if t.scroll == 0 {
+			leftColor = th.TextDisabled
+		}
+		if t.scroll == t.maxStripScroll() {
+			rightColor = th.TextDisabled
+		}
+		drawArrowGlyph(canvas, ax, b.Y, tabArrowWidth, tabStripHeight, Horizontal, false, leftColor)
+		drawArrowGlyph(canvas, ax+tabArrowWidth, b.Y, tabArrowWidth, tabStripHeight, Horizontal, true, rightColor)
+	}
+	t.RepaintRequested = false
+}
+
+func (t *TabView) renderTab(canvas *render.Canvas, i int, tab *Tab, r layout.Rect) {
+	selected := i == t.Selected
+	th := theme.Current()
+	switch {
+	case selected:
+		// Open towards the page: no bottom border
+		canvas.FillRect(r.X, r.Y+2, r.Width, r.Height-2, theme.Or(t.BgColor, th.Background))
+		canvas.FillRect(r.X, r.Y+2, r.Width, 2, th.Primary)
+		canvas.FillRect(r.X, r.Y+2, 1, r.Height-2, th.Border)
+		canvas.FillRect(r.X+r.Width-1, r.Y+2, 1, r.Height-2, th.Border)
+	case i == t.hovered:
+		canvas.FillRect(r.X+1, r.Y+4, r.Width-2, r.Height-5, th.Hover)
+	}
+	if !selected && i+1 != t.Selected && i+1 < len(t.Tabs) {
+		canvas.FillRect(r.X+r.Width-1, r.Y+8, 1, r.Height-14, th.Divider) // Separator
+	}
+
+	x := r.X + tabPadding
+	cy := r.Y + 2 + (r.Height-2)/2
+	if tab.Icon != nil {
+		iw, ih := tab.Icon.ImageSize()
+		iw, ih = min(iw, 16), min(ih, 16)
+		tab.Icon.SetBounds(x, cy-ih/2, iw, ih)
+		tab.Icon.Render(canvas)
+		x += 16 + 6
+	}
+
+	color := th.TextSecondary
+	if selected {
+		color = th.Text
+	}
+	tw, textH := render.MeasureText(tab.Title, t.Font)
+	ty := cy - textH/2
+	canvas.DrawText(x, ty, tab.Title, color)
+	if selected && t.isFocused {
+		drawRectOutline(canvas, x-2, ty-1, tw+4, textH+2, th.Focus)
+	}
+
+	if tab.Closable {
+		cr := t.closeRect(i)
+		if i == t.hovered && t.closeHovered {
+			canvas.FillRect(cr.X, cr.Y, cr.Width, cr.Height, th.Divider)
+		}
+		drawCloseGlyph(canvas, cr.X+cr.Width/2, cr.Y+cr.Height/2, th.TextSecondary)
+	}
+}
+
+// drawCloseGlyph draws a small cross centered on cx, cy.
+func drawCloseGlyph(canvas *render.Canvas, cx, cy int32, color uint32) {
+	const h = 3
+	canvas.DrawLine(cx-h, cy-h, cx+h, cy+h, color)
+	canvas.DrawLine(cx-h, cy+h, cx+h, cy-h, color)
+}
+
+func (t *TabView) ChildComponents() []Component {
+	if tab := t.SelectedTab(); tab != nil && tab.Content != nil {
+		return []Component{tab.Content}
+	}
+	return nil
+}
+
+func (t *TabView) FindComponentAt(x, y int32) Component {
+	if !t.Visible || !t.Bounds.Contains(x, y) {
+		return nil
+	}
+	tab := t.SelectedTab()
+	if tab != nil && tab.Content != nil && t.ContentBounds().Contains(x, y) {
+		if container, ok := tab.Content.(HitTester); ok {
+			if found := container.FindComponentAt(x, y); found != nil {
+				return found
+			}
+		} else if tab.Content.GetBounds().Contains(x, y) {
+			return tab.Content
+		}
+	}
+	return t
+}
+
+func (t *TabView) OnFocus() {
+	t.isFocused = true
+	t.RequestRepaint()
+}
+
+func (t *TabView) OnBlur() {
+	t.isFocused = false
+	t.RequestRepaint()
+}
+
+func (t *TabView) AcceptsFocus() bool {
+	return len(t.Tabs) > 0
+}
+
+// HandleShortcut switches tabs with Ctrl+Tab, Ctrl+Shift+Tab and
+// Ctrl+Page Up/Down.
+func (t *TabView) HandleShortcut(key event.KeyEvent) bool {
+	if key.Modifiers&event.ModCtrl == 0 || key.Modifiers&event.ModAlt != 0 {
+		return false
+	}
+	switch key.VirtualKeyCode {
+	case 0x09: // Tab
+		if key.Modifiers&event.ModShift != 0 {
+			t.selectBy(-1)
+		} else {
+			t.selectBy(1)
+		}
+		return true
+	case 0x21: // Page Up
+		t.selectBy(-1)
+		return true
+	case 0x22: // Page Down
+		t.selectBy(1)
+		return true
+	}
+	return false
+}
+
+func (t *TabView) OnEvent(evt event.Event) bool {
+	if !t.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !t.Bounds.Contains(data.X, data.Y) || data.Y >= t.Bounds.Y+tabStripHeight {
+			break
+		}
+		if dir := t.arrowAt(data.X, data.Y); dir != 0 {
+			t.scrollStrip(int32(dir) * 80)
+			return true
+		}
+		i := t.tabAt(data.X, data.Y)
+		if i < 0 {
+			return true
+		}
+		if t.Tabs[i].Closable && t.closeRect(i).Contains(data.X, data.Y) {
+			t.CloseTab(i)
+			return true
+		}
+		t.SetSelected(i)
+		if t.Reorderable {
+			t.dragIndex, t.dragX, t.dragging = i, data.X, false
+			SetCapture(t)
+		}
+		return true
+
+	case event.EventMouseMove:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			break
+		}
+		if t.dragIndex >= 0 {
+			t.drag(data.X)
+			return true
+		}
+		hovered := t.tabAt(data.X, data.Y)
+		closeHovered := hovered >= 0 && t.Tabs[hovered].Closable && t.closeRect(hovered).Contains(data.X, data.Y)
+		if hovered != t.hovered || closeHovered != t.closeHovered {
+			t.hovered, t.closeHovered = hovered, closeHovered
+			t.RequestRepaint()
+		}
+
+	case event.EventMouseRelease:
+		if t.dragIndex >= 0 {
+			t.dragIndex = -1
+			t.dragging = false
+			ReleaseCapture()
+			t.RequestRepaint()
+			return true
+		}
+
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if ok && t.stripRect().Contains(data.X, data.Y) && t.overflows() {
+			t.scrollStrip(int32(-data.Delta-data.DeltaX) * 40 / 120)
+			return true
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !t.isFocused {
+			break
+		}
+		if t.HandleShortcut(data) {
+			return true
+		}
+		switch data.VirtualKeyCode {
+		case 0x25: // Left
+			t.SetSelected(t.Selected - 1)
+			return true
+		case 0x27: // Right
+			t.SetSelected(t.Selected + 1)
+			return true
+		case 0x24: // Home
+			t.SetSelected(0)
+			return true
+		case 0x23: // End
+			t.SetSelected(len(t.Tabs) - 1)
+			return true
+		}
+		return false
+	}
+
+	if tab := t.SelectedTab(); tab != nil && tab.Content != nil {
+		return tab.Content.OnEvent(evt)
+	}
+	return false
+}
+
+// drag moves the dragged tab past a neighbour once the pointer crosses
+// the neighbour's middle.
+func (t *TabView) drag(x int32) {
+	if !t.dragging {
+		if abs(x-t.dragX) < tabDragSlop {
+			return
+		}
+		t.dragging = true
+	}
+	for {
+		i := t.dragIndex
+		switch {
+		case i > 0 && x < t.tabRect(i-1).X+t.Tabs[i-1].width/2:
+			t.MoveTab(i, i-1)
+			t.dragIndex--
+		case i+1 < len(t.Tabs) && x > t.tabRect(i+1).X+t.Tabs[i+1].width/2:
+			t.MoveTab(i, i+1)
+			t.dragIndex++
+		default:
+			return
+		}
+	}
+}
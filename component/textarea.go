@@ -3,6 +3,7 @@ package component
 import (
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 	"strings"
 	"time"
 )
@@ -47,9 +48,7 @@ func (t *TextArea) GetPreferredSize() (int32, int32) {
 }
 
 func (t *TextArea) getLineHeight(canvas *render.Canvas) int32 {
-	if t.Font != nil {
-		canvas.SetFont(t.Font)
-	}
+	canvas.SetFont(t.Font)
 	_, h := canvas.MeasureText("Tg")
 	t.lineHeight = h + 4
 	return t.lineHeight
@@ -178,9 +177,7 @@ func (t *TextArea) Render(canvas *render.Canvas) {
 		return
 	}
 
-	if t.Font != nil {
-		canvas.SetFont(t.Font)
-	}
+	canvas.SetFont(t.Font)
 
 	// Check for pending mouse click (needs canvas for measurement)
 	if t.pendingMouseX != 0 || t.pendingMouseY != 0 {
@@ -189,17 +186,13 @@ func (t *TextArea) Render(canvas *render.Canvas) {
 		t.pendingMouseY = 0
 	}
 
-	// Background
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, t.Bounds.Width, t.Bounds.Height, 0xFFFFFFFF)
-	// Border
-	borderColor := uint32(0xFFAAAAAA)
+	// Background and Border
+	th := theme.Current()
+	borderColor := th.Border
 	if t.isFocused {
-		borderColor = 0xFF0078D7
+		borderColor = th.Focus
 	}
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, t.Bounds.Width, 1, borderColor)
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y+t.Bounds.Height-1, t.Bounds.Width, 1, borderColor)
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, 1, t.Bounds.Height, borderColor)
-	canvas.FillRect(t.Bounds.X+t.Bounds.Width-1, t.Bounds.Y, 1, t.Bounds.Height, borderColor)
+	drawFrame(canvas, t.Bounds.X, t.Bounds.Y, t.Bounds.Width, t.Bounds.Height, th.Background, borderColor)
 
 	// Draw Text
 	lines := strings.Split(t.Text, "\n")
@@ -220,7 +213,7 @@ func (t *TextArea) Render(canvas *render.Canvas) {
 			break // below view
 		}
 
-		canvas.DrawText(startX, y, line, 0xFF000000)
+		canvas.DrawText(startX, y, line, th.Text)
 	}
 
 	// Draw Cursor
@@ -247,7 +240,7 @@ func (t *TextArea) Render(canvas *render.Canvas) {
 
 				// Ensure cursor inside bounds
 				if cursorY >= t.Bounds.Y && cursorY+lineHeight <= t.Bounds.Y+t.Bounds.Height {
-					canvas.FillRect(cursorX, cursorY, 2, lineHeight, 0xFF000000)
+					canvas.FillRect(cursorX, cursorY, 2, lineHeight, th.Text)
 				}
 			}
 		}
@@ -255,6 +248,14 @@ func (t *TextArea) Render(canvas *render.Canvas) {
 	t.RepaintRequested = false
 }
 
+// SetText replaces the text, moving the cursor to its start.
+func (t *TextArea) SetText(text string) {
+	t.Text = text
+	t.cursorPos, t.selStart = 0, 0
+	t.scrollY = 0
+	t.RequestRepaint()
+}
+
 func (t *TextArea) OnFocus() {
 	t.isFocused = true
 	t.RequestRepaint()
@@ -265,6 +266,10 @@ func (t *TextArea) OnBlur() {
 	t.RequestRepaint()
 }
 
+func (t *TextArea) AcceptsFocus() bool {
+	return true
+}
+
 func (t *TextArea) OnEvent(evt event.Event) bool {
 	if !t.Visible {
 		return false
@@ -289,6 +294,9 @@ func (t *TextArea) OnEvent(evt event.Event) bool {
 
 	case event.EventMouseWheel:
 		if data, ok := evt.Data.(event.MouseEvent); ok {
+			if data.Delta == 0 || (!t.isFocused && !t.Bounds.Contains(data.X, data.Y)) {
+				return false
+			}
 			// Scroll
 			scrollAmount := int32(data.Delta) * -1 // Windows delta is + for up, usually we scroll up (decrease Y)
 			// But wait, scrollY is offset. Increasing scrollY moves content UP.
@@ -3,6 +3,7 @@ package component
 import (
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 	"time"
 )
 
@@ -13,6 +14,14 @@ type TextBox struct {
 	Font        *render.Font
 	ReadOnly    bool
 
+	// InputFilter, if set, rejects typed runes for which it returns false.
+	InputFilter func(r rune) bool
+	// OnChange is called after the user edited the text.
+	OnChange func(text string)
+	// ErrorText marks the text as invalid: the box gets a red border and
+	// an error badge. The message itself is for the application to show.
+	ErrorText string
+
 	// State
 	isFocused     bool
 	cursorPos     int // Cursor position (index in runes)
@@ -21,6 +30,7 @@ type TextBox struct {
 	pendingMouseX int32 // For deferring calculation
 	cursorBlink   bool
 	lastBlink     int64
+	inset         int32 // Width kept free at the right end, e.g. for buttons
 }
 
 func NewTextBox(width int32) *TextBox {
@@ -75,9 +85,7 @@ func (t *TextBox) Render(canvas *render.Canvas) {
 	}
 
 	// Set Font
-	if t.Font != nil {
-		canvas.SetFont(t.Font)
-	}
+	canvas.SetFont(t.Font)
 
 	// Handle pending mouse interaction (deferred because we need canvas for measurement)
 	if t.isDragging || t.pendingMouseX != 0 {
@@ -101,43 +109,38 @@ func (t *TextBox) Render(canvas *render.Canvas) {
 		}
 	}
 
-	// Draw Background
-	bgColor := uint32(0xFFFFFFFF)
-	if t.isFocused {
-		bgColor = 0xFFFFFFFF
-	} else {
-		bgColor = 0xFFF8F8F8
+	// Draw Background, slightly dimmed when not focused, and Border
+	th := theme.Current()
+	bgColor := th.Background
+	if !t.isFocused {
+		bgColor = theme.Mix(th.Background, th.Surface, 0.5)
 	}
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, t.Bounds.Width, t.Bounds.Height, bgColor)
-
-	// Draw Border
-	borderColor := uint32(0xFFAAAAAA)
-	if t.isFocused {
-		borderColor = 0xFF0078D7 // Blue
+	borderColor := th.Border
+	if t.ErrorText != "" {
+		borderColor = th.Error
+	} else if t.isFocused {
+		borderColor = th.Focus
 	}
-	// Simple border: stroke rect
-	// Top
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, t.Bounds.Width, 1, borderColor)
-	// Bottom
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y+t.Bounds.Height-1, t.Bounds.Width, 1, borderColor)
-	// Left
-	canvas.FillRect(t.Bounds.X, t.Bounds.Y, 1, t.Bounds.Height, borderColor)
-	// Right
-	canvas.FillRect(t.Bounds.X+t.Bounds.Width-1, t.Bounds.Y, 1, t.Bounds.Height, borderColor)
-
-	// Draw Text
+	drawFrame(canvas, t.Bounds.X, t.Bounds.Y, t.Bounds.Width, t.Bounds.Height, bgColor, borderColor)
+
+	// Draw Text, clipped to the box without the inset and the error badge
 	textX := t.Bounds.X + 10 // Increased left padding
+	textRight := t.Bounds.X + t.Bounds.Width - t.inset - 1
+	if t.ErrorText != "" {
+		textRight -= 20
+	}
+	canvas.PushClip(t.Bounds.X+1, t.Bounds.Y+1, max(textRight-t.Bounds.X-1, 0), t.Bounds.Height-2)
 
 	// Measure text height to center vertically
 	_, textH := canvas.MeasureText("Tg")
 	textY := t.Bounds.Y + (t.Bounds.Height-textH)/2
 
 	displayText := t.Text
-	textColor := uint32(0xFF000000)
+	textColor := th.Text
 
 	if len(t.Text) == 0 && len(t.Placeholder) > 0 {
 		displayText = t.Placeholder
-		textColor = 0xFF888888
+		textColor = th.TextDisabled
 	}
 
 	// Draw Selection Highlight
@@ -161,7 +164,7 @@ func (t *TextBox) Render(canvas *render.Canvas) {
 
 		selRectX := textX + startX
 		selRectW := endX - startX
-		canvas.FillRect(selRectX, textY, selRectW, textH, 0xFFADD8E6) // Light Blue
+		canvas.FillRect(selRectX, textY, selRectW, textH, th.Selection)
 	}
 
 	canvas.DrawText(textX, textY, displayText, textColor)
@@ -188,12 +191,49 @@ func (t *TextBox) Render(canvas *render.Canvas) {
 
 			w, _ := canvas.MeasureText(string(runes[:pos]))
 			cursorX := textX + w
-			canvas.FillRect(cursorX, textY, 2, textH, 0xFF000000)
+			canvas.FillRect(cursorX, textY, 2, textH, th.Text)
 		}
 	}
+	canvas.PopClip()
+
+	// Draw Error Badge
+	if t.ErrorText != "" {
+		t.drawErrorBadge(canvas, t.Bounds.X+t.Bounds.Width-t.inset-12, t.Bounds.Y+t.Bounds.Height/2)
+	}
 	t.RepaintRequested = false
 }
 
+// drawErrorBadge draws a red circle with an exclamation mark centered on
+// cx, cy.
+func (t *TextBox) drawErrorBadge(canvas *render.Canvas, cx, cy int32) {
+	th := theme.Current()
+	fillCircle(canvas, cx, cy, 6, th.Error)
+	canvas.FillRect(cx, cy-3, 1, 4, th.Background)
+	canvas.FillRect(cx, cy+2, 1, 1, th.Background)
+}
+
+// changed reports an edit made by the user.
+func (t *TextBox) changed() {
+	if t.OnChange != nil {
+		t.OnChange(t.Text)
+	}
+}
+
+// SetText replaces the text, moving the cursor to its end.
+func (t *TextBox) SetText(text string) {
+	t.Text = text
+	t.cursorPos = len([]rune(text))
+	t.selStart = t.cursorPos
+	t.RequestRepaint()
+}
+
+// SelectAll selects the whole text, leaving the cursor at its end.
+func (t *TextBox) SelectAll() {
+	t.selStart = 0
+	t.cursorPos = len([]rune(t.Text))
+	t.RequestRepaint()
+}
+
 func (t *TextBox) OnFocus() {
 	t.isFocused = true
 	t.lastBlink = 0 // Reset blink timer
@@ -207,6 +247,10 @@ func (t *TextBox) OnBlur() {
 	t.RequestRepaint()
 }
 
+func (t *TextBox) AcceptsFocus() bool {
+	return true
+}
+
 func (t *TextBox) OnEvent(evt event.Event) bool {
 	if !t.Visible {
 		return false
@@ -289,6 +333,9 @@ func (t *TextBox) OnEvent(evt event.Event) bool {
 							t.cursorPos--
 							t.selStart = t.cursorPos
 						}
+						if t.Text != string(runes) {
+							t.changed()
+						}
 					}
 					t.RequestRepaint()
 					return true
@@ -303,6 +350,9 @@ func (t *TextBox) OnEvent(evt event.Event) bool {
 				if data.Rune < 32 {
 					return false
 				}
+				if t.InputFilter != nil && !t.InputFilter(data.Rune) {
+					return true
+				}
 
 				runes := []rune(t.Text)
 
@@ -325,6 +375,7 @@ func (t *TextBox) OnEvent(evt event.Event) bool {
 					t.selStart = t.cursorPos
 				}
 
+				t.changed()
 				t.RequestRepaint()
 				return true
 			}
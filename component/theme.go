@@ -0,0 +1,35 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// Colour fields of components are 0 by default, meaning that they follow
+// the current theme; a colour set explicitly is kept across theme changes.
+
+// themeFont returns font, or the theme's font for style if font is nil.
+func themeFont(font *render.Font, style theme.TextStyle) *render.Font {
+	if font != nil {
+		return font
+	}
+	return theme.Current().Font(style)
+}
+
+// drawFrame fills a box with bg inside a 1 pixel border, with the corners
+// rounded to the theme's radius.
+func drawFrame(canvas *render.Canvas, x, y, w, h int32, bg, border uint32) {
+	r := theme.Current().CornerRadius
+	canvas.FillRoundRect(x, y, w, h, r, border)
+	canvas.FillRoundRect(x+1, y+1, w-2, h-2, max(r-1, 0), bg)
+}
+
+// fillInFrame fills the part of the rectangle x, y, w, h that lies inside
+// the border drawFrame draws around b, e.g. a button at the end of a field.
+func fillInFrame(canvas *render.Canvas, b layout.Rect, x, y, w, h int32, color uint32) {
+	r := theme.Current().CornerRadius
+	canvas.PushClip(x, y, w, h)
+	canvas.FillRoundRect(b.X+1, b.Y+1, b.Width-2, b.Height-2, max(r-1, 0), color)
+	canvas.PopClip()
+}
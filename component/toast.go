@@ -0,0 +1,490 @@
+package component
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// ToastKind selects the colour and icon of a toast.
+type ToastKind int
+
+const (
+	ToastInfo ToastKind = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// ToastCorner is the corner of the window toasts stack in.
+type ToastCorner int
+
+const (
+	ToastBottomRight ToastCorner = iota
+	ToastBottomLeft
+	ToastTopRight
+	ToastTopLeft
+)
+
+// DefaultToastDuration is how long a toast stays before it is dismissed,
+// unless it sets Duration.
+var DefaultToastDuration = 4 * time.Second
+
+const (
+	toastSlideDuration = 200 * time.Millisecond
+	toastMargin        = 16 // From the window edges
+	toastIconSize      = 18
+	toastStripeWidth   = 4
+	toastCloseSize     = 20
+	toastActionHeight  = 26
+)
+
+// toastGap is the space between toasts, and toastPadding the space inside
+// them, from the theme's spacing.
+func toastGap() int32 {
+	return theme.Current().Space(2)
+}
+
+func toastPadding() int32 {
+	return theme.Current().Space(3)
+}
+
+// toastColor returns the theme's accent for a kind of toast.
+func toastColor(kind ToastKind) uint32 {
+	t := theme.Current()
+	switch kind {
+	case ToastSuccess:
+		return t.Success
+	case ToastWarning:
+		return t.Warning
+	case ToastError:
+		return t.Error
+	}
+	return t.Primary
+}
+
+// Toast is a short, non-blocking message shown by a window's Toasts.
+type Toast struct {
+	Kind     ToastKind
+	Title    string // Optional, above the message
+	Message  string
+	Action   string // Text of an optional action button
+	OnAction func() // Called when the action is clicked; the toast closes
+	// Duration is how long the toast stays, DefaultToastDuration if 0, or
+	// until closed if negative. The time does not run while the pointer
+	// is on the toast.
+	Duration  time.Duration
+	OnDismiss func() // Called when the toast has gone
+
+	// State, only touched on the UI thread
+	deadline time.Time
+	appear   float64 // 0 to 1 while sliding in
+	leaving  bool
+	leave    float64 // 0 to 1 while sliding out
+	y        float64 // Current offset from the corner
+	placed   bool
+	lines    []string
+	bounds   layout.Rect
+}
+
+// Toasts shows the toasts of a window, stacked in a corner: at most
+// MaxVisible at once, the others wait their turn. Show and the other
+// methods may be called from any goroutine; the toasts are added on the UI
+// thread, like RequestRepaint.
+type Toasts struct {
+	BaseComponent
+	Corner     ToastCorner
+	MaxVisible int
+	Width      int32
+	Font       *render.Font
+
+	overlay *Overlay
+	popup   *Popup
+	shown   []*Toast // Oldest first; the newest is nearest the corner
+	queue   []*Toast
+
+	hover       *Toast
+	hoverClose  bool
+	hoverAction bool
+	lastTick    time.Time
+}
+
+func NewToasts(overlay *Overlay) *Toasts {
+	m := &Toasts{
+		MaxVisible: 3,
+		Width:      320,
+		overlay:    overlay,
+	}
+	m.popup = &Popup{Content: m, KeepFocus: true}
+	m.Visible = true
+	return m
+}
+
+// Show queues a toast and returns it, e.g. to Dismiss it later.
+func (m *Toasts) Show(t *Toast) *Toast {
+	Post(func() { m.add(t) })
+	return t
+}
+
+func (m *Toasts) Info(message string) *Toast {
+	return m.Show(&Toast{Kind: ToastInfo, Message: message})
+}
+
+func (m *Toasts) Success(message string) *Toast {
+	return m.Show(&Toast{Kind: ToastSuccess, Message: message})
+}
+
+func (m *Toasts) Warning(message string) *Toast {
+	return m.Show(&Toast{Kind: ToastWarning, Message: message})
+}
+
+func (m *Toasts) Error(message string) *Toast {
+	return m.Show(&Toast{Kind: ToastError, Message: message})
+}
+
+// Dismiss closes a toast, or removes it from the queue.
+func (m *Toasts) Dismiss(t *Toast) {
+	Post(func() { m.dismiss(t) })
+}
+
+// DismissAll closes all toasts and empties the queue.
+func (m *Toasts) DismissAll() {
+	Post(func() {
+		m.queue = nil
+		for _, t := range m.shown {
+			m.dismiss(t)
+		}
+	})
+}
+
+func (m *Toasts) add(t *Toast) {
+	m.queue = append(m.queue, t)
+	if len(m.shown) == 0 {
+		m.lastTick = time.Now()
+	}
+	m.promote(time.Now())
+	m.layout(0)
+	StartAnimation(m)
+}
+
+// promote moves queued toasts to the stack while there is room.
+func (m *Toasts) promote(now time.Time) {
+	for len(m.queue) > 0 && len(m.shown) < max(1, m.MaxVisible) {
+		t := m.queue[0]
+		m.queue = m.queue[1:]
+		t.appear, t.leave, t.leaving, t.placed = 0, 0, false, false
+		d := t.Duration
+		if d == 0 {
+			d = DefaultToastDuration
+		}
+		t.deadline = now.Add(d)
+		if d < 0 {
+			t.deadline = time.Time{}
+		}
+		m.shown = append(m.shown, t)
+	}
+	if len(m.shown) > 0 && !m.popup.IsOpen() {
+		m.overlay.Show(m.popup)
+	}
+}
+
+func (m *Toasts) dismiss(t *Toast) {
+	for i, q := range m.queue {
+		if q == t {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return
+		}
+	}
+	if !t.leaving {
+		t.leaving = true
+		StartAnimation(m)
+	}
+}
+
+// Tick slides the toasts in and out, runs their timers and restacks them.
+func (m *Toasts) Tick(now time.Time) bool {
+	dt := now.Sub(m.lastTick)
+	m.lastTick = now
+	step := float64(dt) / float64(toastSlideDuration)
+
+	kept := m.shown[:0]
+	var gone []*Toast
+	for _, t := range m.shown {
+		if t == m.hover && !t.deadline.IsZero() {
+			t.deadline = t.deadline.Add(dt) // Paused
+		}
+		if !t.leaving && !t.deadline.IsZero() && !now.Before(t.deadline) {
+			t.leaving = true
+		}
+		if t.leaving {
+			t.leave = min(1, t.leave+step)
+		} else {
+			t.appear = min(1, t.appear+step)
+		}
+		if t.leave >= 1 {
+			gone = append(gone, t)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	m.shown = kept
+	for _, t := range gone {
+		if t == m.hover {
+			m.hover = nil
+		}
+		if t.OnDismiss != nil {
+			t.OnDismiss()
+		}
+	}
+	m.promote(now)
+	m.layout(dt)
+	m.RequestRepaint()
+
+	if len(m.shown) == 0 {
+		m.popup.Close()
+		return false
+	}
+	return true
+}
+
+// layout places the toasts, the newest nearest the corner, easing them
+// towards their places as others come and go.
+func (m *Toasts) layout(dt time.Duration) {
+	ob := m.overlay.Bounds
+	right := m.Corner == ToastBottomRight || m.Corner == ToastTopRight
+	bottom := m.Corner == ToastBottomRight || m.Corner == ToastBottomLeft
+	textW := m.Width - toastStripeWidth - 3*toastPadding() - toastIconSize - toastCloseSize
+
+	var offset float64
+	var union layout.Rect
+	for i := len(m.shown) - 1; i >= 0; i-- {
+		t := m.shown[i]
+		t.lines = wrapText(t.Message, m.Font, textW)
+		h := m.toastHeight(t)
+		if !t.placed {
+			t.y, t.placed = offset, true
+		} else {
+			t.y, _ = easeScroll(t.y, offset, dt)
+		}
+
+		// Slide in from and out to the nearest side
+		slide := 1 - easeOut(t.appear)
+		if t.leaving {
+			slide = easeOut(t.leave)
+		}
+		dx := int32(slide * float64(m.Width+toastMargin))
+		x := ob.X + toastMargin - dx
+		if right {
+			x = ob.X + ob.Width - toastMargin - m.Width + dx
+		}
+		y := ob.Y + toastMargin + int32(t.y)
+		if bottom {
+			y = ob.Y + ob.Height - toastMargin - int32(t.y) - h
+		}
+		t.bounds = layout.Rect{X: x, Y: y, Width: m.Width, Height: h}
+		union = unionRect(union, t.bounds)
+
+		if !t.leaving {
+			offset += float64(h + toastGap())
+		}
+	}
+	m.Bounds = union
+}
+
+func easeOut(p float64) float64 {
+	return 1 - (1-p)*(1-p)
+}
+
+func unionRect(a, b layout.Rect) layout.Rect {
+	if a.Width == 0 || a.Height == 0 {
+		return b
+	}
+	x, y := min(a.X, b.X), min(a.Y, b.Y)
+	return layout.Rect{
+		X: x, Y: y,
+		Width:  max(a.X+a.Width, b.X+b.Width) - x,
+		Height: max(a.Y+a.Height, b.Y+b.Height) - y,
+	}
+}
+
+func (m *Toasts) toastHeight(t *Toast) int32 {
+	_, lh := render.MeasureText("Ag", m.Font)
+	h := int32(len(t.lines)) * lh
+	if t.Title != "" {
+		h += lh + 2
+	}
+	if t.Action != "" {
+		h += toastActionHeight
+	}
+	return max(h+2*toastPadding(), toastIconSize+2*toastPadding())
+}
+
+// closeRect and actionRect return a toast's close and action buttons.
+func (m *Toasts) closeRect(t *Toast) layout.Rect {
+	b := t.bounds
+	return layout.Rect{X: b.X + b.Width - toastCloseSize - 6, Y: b.Y + 6, Width: toastCloseSize, Height: toastCloseSize}
+}
+
+func (m *Toasts) actionRect(t *Toast) layout.Rect {
+	if t.Action == "" {
+		return layout.Rect{}
+	}
+	w, _ := render.MeasureText(t.Action, m.Font)
+	b := t.bounds
+	return layout.Rect{
+		X: b.X + b.Width - toastPadding() - w - 16, Y: b.Y + b.Height - toastPadding() - toastActionHeight + 4,
+		Width: w + 16, Height: toastActionHeight - 4,
+	}
+}
+
+func (m *Toasts) Render(canvas *render.Canvas) {
+	if !m.Visible {
+		return
+	}
+	canvas.SetFont(m.Font)
+	for _, t := range m.shown {
+		m.renderToast(canvas, t)
+	}
+	m.RepaintRequested = false
+}
+
+func (m *Toasts) renderToast(canvas *render.Canvas, t *Toast) {
+	b := t.bounds
+	th := theme.Current()
+	color := toastColor(t.Kind)
+	canvas.FillRoundRect(b.X+3, b.Y+3, b.Width, b.Height, th.CornerRadius, th.Shadow)
+	drawFrame(canvas, b.X, b.Y, b.Width, b.Height, th.Background, th.Border)
+	fillInFrame(canvas, b, b.X, b.Y, toastStripeWidth, b.Height, color)
+
+	// Icon
+	cx := b.X + toastStripeWidth + toastPadding() + toastIconSize/2
+	cy := b.Y + toastPadding() + toastIconSize/2
+	fillCircle(canvas, cx, cy, toastIconSize/2, color)
+	switch t.Kind {
+	case ToastSuccess:
+		drawCheckMark(canvas, cx, cy, th.OnPrimary)
+	case ToastError:
+		drawCloseGlyph(canvas, cx, cy, th.OnPrimary)
+	case ToastWarning:
+		canvas.FillRect(cx-1, cy-5, 2, 7, th.OnPrimary)
+		canvas.FillRect(cx-1, cy+4, 2, 2, th.OnPrimary)
+	default:
+		canvas.FillRect(cx-1, cy-5, 2, 2, th.OnPrimary)
+		canvas.FillRect(cx-1, cy-1, 2, 7, th.OnPrimary)
+	}
+
+	// Text
+	x := cx + toastIconSize/2 + toastPadding()
+	y := b.Y + toastPadding()
+	_, lh := render.MeasureText("Ag", m.Font)
+	if t.Title != "" {
+		canvas.DrawText(x, y, t.Title, th.Text)
+		y += lh + 2
+	}
+	textColor := th.Text
+	if t.Title != "" {
+		textColor = th.TextSecondary
+	}
+	for _, line := range t.lines {
+		canvas.DrawText(x, y, line, textColor)
+		y += lh
+	}
+
+	// Buttons
+	c := m.closeRect(t)
+	if t == m.hover && m.hoverClose {
+		canvas.FillRect(c.X, c.Y, c.Width, c.Height, th.ControlHover)
+	}
+	drawCloseGlyph(canvas, c.X+c.Width/2, c.Y+c.Height/2, th.Icon)
+	if a := m.actionRect(t); t.Action != "" {
+		if t == m.hover && m.hoverAction {
+			canvas.FillRect(a.X, a.Y, a.Width, a.Height, th.Hover)
+		}
+		w, h := render.MeasureText(t.Action, m.Font)
+		canvas.DrawText(a.X+(a.Width-w)/2, a.Y+(a.Height-h)/2, t.Action, color)
+	}
+}
+
+// Leave forgets the toast under the pointer, whose time then runs again,
+// as when the pointer leaves the window.
+func (m *Toasts) Leave() {
+	if m.hover != nil {
+		m.hover = nil
+		m.RequestRepaint()
+	}
+}
+
+// toastAt returns the toast at a point.
+func (m *Toasts) toastAt(x, y int32) *Toast {
+	for _, t := range m.shown {
+		if !t.leaving && t.bounds.Contains(x, y) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (m *Toasts) OnEvent(evt event.Event) bool {
+	if !m.Visible {
+		return false
+	}
+	data, ok := evt.Data.(event.MouseEvent)
+	if !ok {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseMove:
+		t := m.toastAt(data.X, data.Y)
+		hoverClose := t != nil && m.closeRect(t).Contains(data.X, data.Y)
+		hoverAction := t != nil && t.Action != "" && m.actionRect(t).Contains(data.X, data.Y)
+		if t != m.hover || hoverClose != m.hoverClose || hoverAction != m.hoverAction {
+			m.hover, m.hoverClose, m.hoverAction = t, hoverClose, hoverAction
+			m.RequestRepaint()
+		}
+
+	case event.EventMouseClick:
+		t := m.toastAt(data.X, data.Y)
+		if t == nil {
+			return false
+		}
+		switch {
+		case m.closeRect(t).Contains(data.X, data.Y):
+			m.dismiss(t)
+		case t.Action != "" && m.actionRect(t).Contains(data.X, data.Y):
+			m.dismiss(t)
+			if t.OnAction != nil {
+				t.OnAction()
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// wrapText breaks text into lines no wider than width, at spaces where
+// possible, keeping its own line breaks.
+func wrapText(text string, font *render.Font, width int32) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if w, _ := render.MeasureText(candidate, font); w <= width || line == "" {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
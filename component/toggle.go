@@ -0,0 +1,86 @@
+package component
+
+import (
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// Shared by the labelled boolean inputs: CheckBox, RadioButton and
+// ToggleSwitch. Each draws an indicator with its label to the right.
+
+// toggleLabelGap is the gap between indicator and label.
+func toggleLabelGap() int32 {
+	return theme.Current().Space(2)
+}
+
+// toggleInput tracks hover and focus, and turns clicks and Space into
+// activations.
+type toggleInput struct {
+	isHovered bool
+	isFocused bool
+}
+
+func (t *toggleInput) OnFocus() {
+	t.isFocused = true
+}
+
+func (t *toggleInput) OnBlur() {
+	t.isFocused = false
+}
+
+// handle processes an event for a toggle with the given bounds. It reports
+// whether the event was consumed and whether the toggle was activated.
+// Disabled toggles track hover but cannot be activated.
+func (t *toggleInput) handle(evt event.Event, bounds layout.Rect, disabled bool) (handled, activated bool) {
+	switch evt.Type {
+	case event.EventMouseMove:
+		if data, ok := evt.Data.(event.MouseEvent); ok {
+			wasHovered := t.isHovered
+			t.isHovered = bounds.Contains(data.X, data.Y)
+			return wasHovered != t.isHovered, false
+		}
+
+	case event.EventMouseClick: // Mouse Down
+		if data, ok := evt.Data.(event.MouseEvent); ok && bounds.Contains(data.X, data.Y) {
+			return true, !disabled
+		}
+
+	case event.EventKeyPress:
+		if data, ok := evt.Data.(event.KeyEvent); ok && t.isFocused && data.VirtualKeyCode == 0x20 { // Space
+			return true, !disabled
+		}
+	}
+	return false, false
+}
+
+// toggleSize returns the preferred size of a toggle with an indicator of
+// the given size and a label.
+func toggleSize(text string, font *render.Font, indicatorW, indicatorH int32) (int32, int32) {
+	if text == "" {
+		return indicatorW, indicatorH
+	}
+	w, h := render.MeasureText(text, font)
+	return indicatorW + toggleLabelGap() + w, max(h, indicatorH)
+}
+
+// drawToggleLabel draws the label of a toggle starting at x, vertically
+// centered in bounds, with a focus outline around it.
+func drawToggleLabel(canvas *render.Canvas, bounds layout.Rect, x int32, text string, font *render.Font, disabled, focused bool) {
+	if text == "" {
+		return
+	}
+	canvas.SetFont(font)
+	t := theme.Current()
+	color := t.Text
+	if disabled {
+		color = t.TextDisabled
+	}
+	w, h := render.MeasureText(text, font)
+	y := bounds.Y + (bounds.Height-h)/2
+	canvas.DrawText(x, y, text, color)
+	if focused {
+		drawRectOutline(canvas, x-2, y-1, w+4, h+2, t.Focus)
+	}
+}
@@ -0,0 +1,147 @@
+package component
+
+import (
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+const (
+	switchWidth    = 36
+	switchHeight   = 18
+	switchDuration = 120 * time.Millisecond // Knob travel time
+)
+
+// ToggleSwitch is an on/off switch whose knob slides when toggled.
+type ToggleSwitch struct {
+	BaseComponent
+	Text     string
+	Checked  bool
+	Disabled bool
+	OnCheck  func(checked bool)
+	Font     *render.Font
+	toggleInput
+
+	knob      float64 // Knob position, 0 (off) to 1 (on)
+	animating bool
+	lastTick  time.Time
+}
+
+func NewToggleSwitch(text string) *ToggleSwitch {
+	s := &ToggleSwitch{
+		Text: text,
+	}
+	s.Visible = true
+	return s
+}
+
+// SetChecked turns the switch on or off, sliding the knob.
+func (s *ToggleSwitch) SetChecked(checked bool) {
+	if s.Checked == checked {
+		return
+	}
+	s.Checked = checked
+	s.lastTick = time.Now()
+	s.animating = true
+	StartAnimation(s)
+	s.RequestRepaint()
+	if s.OnCheck != nil {
+		s.OnCheck(checked)
+	}
+}
+
+func (s *ToggleSwitch) Tick(now time.Time) bool {
+	step := float64(now.Sub(s.lastTick)) / float64(switchDuration)
+	s.lastTick = now
+	target := 0.0
+	if s.Checked {
+		target = 1
+	}
+	if s.knob < target {
+		s.knob = min(s.knob+step, target)
+	} else {
+		s.knob = max(s.knob-step, target)
+	}
+	s.RequestRepaint()
+	s.animating = s.knob != target
+	return s.animating
+}
+
+func (s *ToggleSwitch) GetPreferredSize() (int32, int32) {
+	return toggleSize(s.Text, s.Font, switchWidth, switchHeight)
+}
+
+func (s *ToggleSwitch) Render(canvas *render.Canvas) {
+	if !s.Visible {
+		return
+	}
+	// Jump to the end if the switch was set by assigning Checked
+	if !s.animating && (s.Checked != (s.knob == 1)) {
+		s.knob = 0
+		if s.Checked {
+			s.knob = 1
+		}
+	}
+
+	// Draw Track: a pill, blending from gray to blue as the knob moves
+	x := s.Bounds.X
+	y := s.Bounds.Y + (s.Bounds.Height-switchHeight)/2
+	r := int32(switchHeight / 2)
+	t := theme.Current()
+	offColor, onColor := t.Border, t.Primary
+	if s.Disabled {
+		offColor, onColor = t.Divider, theme.Mix(t.Primary, t.Background, 0.6)
+	} else if s.isHovered {
+		offColor, onColor = theme.Mix(t.Border, t.Text, 0.1), t.PrimaryHover
+	}
+	trackColor := blendColor(offColor, onColor, s.knob)
+	fillCircle(canvas, x+r, y+r, r, trackColor)
+	fillCircle(canvas, x+switchWidth-r-1, y+r, r, trackColor)
+	canvas.FillRect(x+r, y, switchWidth-2*r, switchHeight+1, trackColor)
+
+	// Draw Knob
+	travel := float64(switchWidth - 2*r - 1)
+	kx := x + r + int32(travel*s.knob+0.5)
+	fillCircle(canvas, kx, y+r, r-3, t.OnPrimary)
+
+	drawToggleLabel(canvas, s.Bounds, x+switchWidth+toggleLabelGap(), s.Text, s.Font, s.Disabled, s.isFocused)
+	s.RepaintRequested = false
+}
+
+// blendColor mixes two colors, t = 0 giving a and t = 1 giving b.
+func blendColor(a, b uint32, t float64) uint32 {
+	var c uint32
+	for shift := 0; shift < 32; shift += 8 {
+		ca, cb := float64((a>>shift)&0xFF), float64((b>>shift)&0xFF)
+		c |= uint32(ca+(cb-ca)*t+0.5) << shift
+	}
+	return c
+}
+
+func (s *ToggleSwitch) OnFocus() {
+	s.toggleInput.OnFocus()
+	s.RequestRepaint()
+}
+
+func (s *ToggleSwitch) OnBlur() {
+	s.toggleInput.OnBlur()
+	s.RequestRepaint()
+}
+
+func (s *ToggleSwitch) AcceptsFocus() bool {
+	return !s.Disabled
+}
+
+func (s *ToggleSwitch) OnEvent(evt event.Event) bool {
+	if !s.Visible {
+		return false
+	}
+
+	handled, activated := s.handle(evt, s.Bounds, s.Disabled)
+	if activated {
+		s.SetChecked(!s.Checked)
+	}
+	return handled
+}
@@ -0,0 +1,222 @@
+package component
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// DefaultTooltipDelay is how long the pointer must rest on a component
+// before its tooltip shows, unless the component sets TooltipDelay.
+var DefaultTooltipDelay = 500 * time.Millisecond
+
+const (
+	tooltipOffset = 20 // Below the pointer, clear of the arrow
+	// After a tooltip was shown, moving on to another component shows its
+	// tooltip after this fraction of the delay, as when browsing a toolbar.
+	tooltipReshowFraction = 5
+	tooltipReshowWindow   = 500 * time.Millisecond
+)
+
+// TooltipOwner is implemented by components with a tooltip: a text, or a
+// component for richer content (which wins if both are set). A delay of
+// 0 means DefaultTooltipDelay. BaseComponent implements it through its
+// Tooltip, TooltipView and TooltipDelay fields; components may implement
+// it themselves, e.g. to vary the text with the pointer position.
+type TooltipOwner interface {
+	GetTooltip() (text string, view Component, delay time.Duration)
+}
+
+// GetTooltip returns the component's tooltip.
+func (b *BaseComponent) GetTooltip() (string, Component, time.Duration) {
+	return b.Tooltip, b.TooltipView, b.TooltipDelay
+}
+
+// hasTooltip reports whether c has a tooltip to show.
+func hasTooltip(c Component) bool {
+	owner, ok := c.(TooltipOwner)
+	if !ok {
+		return false
+	}
+	text, view, _ := owner.GetTooltip()
+	return text != "" || view != nil
+}
+
+// Tooltips shows the tooltips of a window. The window reports the pointer
+// with PointerMoved, hides the tooltip on presses, scrolling and when the
+// pointer leaves, and calls Tick on its timer; no goroutines are involved.
+type Tooltips struct {
+	Font *render.Font // For text tooltips; the theme's caption font if nil
+
+	overlay *Overlay
+	popup   *Popup
+	box     *tooltipBox
+
+	target     Component // Under the pointer, with a tooltip
+	x, y       int32     // Last pointer position
+	since      time.Time // Pointer on target since
+	suppressed bool      // Hidden by a press, until the pointer leaves target
+	lastShown  time.Time // When a tooltip was last visible
+}
+
+func NewTooltips(overlay *Overlay) *Tooltips {
+	return &Tooltips{overlay: overlay, box: newTooltipBox()}
+}
+
+// IsShown reports whether a tooltip is visible.
+func (t *Tooltips) IsShown() bool {
+	return t.popup != nil && t.popup.IsOpen()
+}
+
+// Target returns the component whose tooltip is pending or shown.
+func (t *Tooltips) Target() Component {
+	return t.target
+}
+
+// PointerMoved reports the component under the pointer, or nil, e.g. over
+// nothing or while dragging. The innermost of target and its ancestors
+// with a tooltip is used. It reports whether the display changed.
+func (t *Tooltips) PointerMoved(target Component, ancestors []Component, x, y int32, now time.Time) bool {
+	for i := len(ancestors) - 1; target != nil && !hasTooltip(target); i-- {
+		if i < 0 {
+			target = nil
+		} else {
+			target = ancestors[i]
+		}
+	}
+	t.x, t.y = x, y
+	if target == t.target {
+		return false
+	}
+	changed := t.close(now)
+	t.target = target
+	t.since = now
+	t.suppressed = false
+	if target != nil && !t.lastShown.IsZero() && now.Sub(t.lastShown) < tooltipReshowWindow {
+		// Browsing from one tooltip to the next
+		t.since = now.Add(-t.delay() + t.delay()/tooltipReshowFraction)
+	}
+	return changed
+}
+
+// Hide hides the tooltip, and keeps it hidden until the pointer moves on
+// to another component. It reports whether a tooltip was visible.
+func (t *Tooltips) Hide() bool {
+	t.suppressed = true
+	t.lastShown = time.Time{}
+	return t.close(time.Time{})
+}
+
+// Leave forgets the target, as when the pointer leaves the window.
+func (t *Tooltips) Leave() bool {
+	t.target = nil
+	t.lastShown = time.Time{}
+	return t.close(time.Time{})
+}
+
+func (t *Tooltips) close(now time.Time) bool {
+	if !t.IsShown() {
+		return false
+	}
+	t.lastShown = now
+	t.popup.Close()
+	return true
+}
+
+func (t *Tooltips) delay() time.Duration {
+	if owner, ok := t.target.(TooltipOwner); ok {
+		if _, _, d := owner.GetTooltip(); d > 0 {
+			return d
+		}
+	}
+	return DefaultTooltipDelay
+}
+
+// Tick shows the tooltip once the pointer rested long enough, and reports
+// whether the display changed.
+func (t *Tooltips) Tick(now time.Time) bool {
+	if t.target == nil || t.suppressed || t.IsShown() || now.Sub(t.since) < t.delay() {
+		return false
+	}
+	if !t.target.IsVisible() {
+		t.target = nil
+		return false
+	}
+	text, view, _ := t.target.(TooltipOwner).GetTooltip()
+	if view == nil {
+		if text == "" {
+			return false
+		}
+		t.box.Text = text
+		t.box.Font = t.Font
+		view = t.box
+	}
+	t.show(view)
+	return true
+}
+
+// show opens view below the pointer, or above it if there is no room
+// below, keeping it inside the window.
+func (t *Tooltips) show(view Component) {
+	w, h := view.GetPreferredSize()
+	b := t.overlay.Bounds
+	x := max(b.X, min(t.x, b.X+b.Width-w))
+	y := t.y + tooltipOffset
+	if y+h > b.Y+b.Height {
+		y = t.y - h - 4
+	}
+	y = max(b.Y, y)
+	view.SetBounds(x, y, w, h)
+	t.popup = &Popup{Content: view, KeepFocus: true, Shadow: true}
+	t.overlay.Show(t.popup)
+}
+
+// Contains reports whether a point is over the shown tooltip.
+func (t *Tooltips) Contains(x, y int32) bool {
+	return t.IsShown() && t.popup.Content.GetBounds().Contains(x, y)
+}
+
+// tooltipBox draws a small box of text above the window content.
+type tooltipBox struct {
+	BaseComponent
+	Text string
+	Font *render.Font
+}
+
+func newTooltipBox() *tooltipBox {
+	t := &tooltipBox{}
+	t.Visible = true
+	return t
+}
+
+func (t *tooltipBox) GetPreferredSize() (int32, int32) {
+	var w, h int32
+	font := themeFont(t.Font, theme.Caption)
+	for _, line := range strings.Split(t.Text, "\n") {
+		lw, lh := render.MeasureText(line, font)
+		w = max(w, lw)
+		h += lh
+	}
+	return w + 12, h + 6
+}
+
+func (t *tooltipBox) Render(canvas *render.Canvas) {
+	if !t.Visible {
+		return
+	}
+	b := t.Bounds
+	th := theme.Current()
+	font := themeFont(t.Font, theme.Caption)
+	canvas.FillRoundRect(b.X, b.Y, b.Width, b.Height, th.CornerRadius, th.Tooltip)
+	canvas.SetFont(font)
+	y := b.Y + 3
+	for _, line := range strings.Split(t.Text, "\n") {
+		_, h := render.MeasureText(line, font)
+		canvas.DrawText(b.X+6, y, line, th.TooltipText)
+		y += h
+	}
+	canvas.SetFont(nil)
+	t.RepaintRequested = false
+}
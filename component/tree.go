@@ -0,0 +1,600 @@
+package component
+
+import (
+	"math"
+	"time"
+
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// TreeModel supplies the nodes of a TreeView. Nodes are identified by
+// value, so they must be comparable (typically pointers); the nil node is
+// the invisible root whose children are the top-level nodes.
+type TreeModel interface {
+	Children(node interface{}) []interface{}
+	HasChildren(node interface{}) bool
+	NodeText(node interface{}) string
+}
+
+// TreeAsyncModel is implemented by models whose children take time to
+// load, e.g. from disk or the network. LoadChildren is called instead of
+// Children and may call done from any goroutine; a spinner is shown until
+// it does.
+type TreeAsyncModel interface {
+	LoadChildren(node interface{}, done func(children []interface{}, err error))
+}
+
+// TreeIcons is implemented by models that show an icon before each node.
+// Icons are drawn at their own size, cropped to the row.
+type TreeIcons interface {
+	NodeIcon(node interface{}, expanded bool) *Image
+}
+
+// treeRow is a visible row of the flattened tree.
+type treeRow struct {
+	node   interface{}
+	depth  int
+	last   bool   // Last child of its parent
+	guides []bool // guides[j]: the ancestor at depth j has later siblings
+	// Placeholder rows stand for children still loading (or failed)
+	placeholder bool
+	err         error
+}
+
+// TreeView shows a hierarchy with expandable nodes. Children are loaded
+// when a node is first expanded.
+type TreeView struct {
+	BaseComponent
+	Model      TreeModel
+	RowHeight  int32
+	Indent     int32
+	Font       *render.Font
+	WheelStep  int32
+	Smooth     bool
+	ShowGuides bool
+
+	// Colours are the theme's if 0
+	BgColor       uint32
+	SelectedColor uint32
+	GuideColor    uint32
+
+	OnSelectionChanged func(node interface{})
+	OnActivate         func(node interface{}) // Double click or Enter
+	OnExpand           func(node interface{}, expanded bool)
+
+	rows     []treeRow
+	children map[interface{}][]interface{} // Loaded children
+	expanded map[interface{}]bool
+	loading  map[interface{}]int // Token of the load in flight, by node
+	loads    int                 // Last token given
+	errors   map[interface{}]error
+	selected interface{}
+	current  int
+	hover    int
+
+	metrics        rowMetrics
+	bar            *ScrollBar
+	offset, target float64
+	lastTick       time.Time
+	isFocused      bool
+}
+
+func NewTreeView(width, height int32, model TreeModel) *TreeView {
+	t := &TreeView{
+		RowHeight:  24,
+		Indent:     18,
+		WheelStep:  48,
+		Smooth:     true,
+		ShowGuides: true,
+		current:    -1,
+		hover:      -1,
+		bar:        NewScrollBar(Vertical),
+	}
+	t.bar.OnScroll = func(v float64) {
+		t.offset, t.target = v, v
+	}
+	t.SetBounds(0, 0, width, height)
+	t.Visible = true
+	t.SetModel(model)
+	return t
+}
+
+// SetModel replaces the model, collapsing everything.
+func (t *TreeView) SetModel(model TreeModel) {
+	t.Model = model
+	t.children = make(map[interface{}][]interface{})
+	t.expanded = make(map[interface{}]bool)
+	t.loading = make(map[interface{}]int)
+	t.errors = make(map[interface{}]error)
+	t.selected = nil
+	t.offset, t.target = 0, 0
+	t.rebuild()
+}
+
+// Refresh forgets the loaded children of node (nil for the whole tree),
+// so they are requested from the model again.
+func (t *TreeView) Refresh(node interface{}) {
+	if node == nil {
+		t.children = make(map[interface{}][]interface{})
+		t.errors = make(map[interface{}]error)
+		t.loading = make(map[interface{}]int)
+	} else {
+		delete(t.children, node)
+		delete(t.errors, node)
+		delete(t.loading, node)
+	}
+	t.rebuild()
+}
+
+// loadChildren returns the children of node if they are loaded, starting
+// to load them otherwise.
+func (t *TreeView) loadChildren(node interface{}) ([]interface{}, bool) {
+	if c, ok := t.children[node]; ok {
+		return c, true
+	}
+	if _, ok := t.loading[node]; ok || t.errors[node] != nil {
+		return nil, false
+	}
+	async, ok := t.Model.(TreeAsyncModel)
+	if !ok {
+		c := t.Model.Children(node)
+		t.children[node] = c
+		return c, true
+	}
+
+	// Tokens are never reused, so a load outdated by Refresh or SetModel
+	// is ignored even if the node is loaded again meanwhile
+	t.loads++
+	token := t.loads
+	t.loading[node] = token
+	StartAnimation(t) // Spinner
+	async.LoadChildren(node, func(children []interface{}, err error) {
+		Post(func() {
+			if t.loading[node] != token {
+				return // Refreshed or model replaced meanwhile
+			}
+			delete(t.loading, node)
+			if err != nil {
+				t.errors[node] = err
+			} else {
+				t.children[node] = children
+			}
+			t.rebuild()
+		})
+	})
+	return nil, false
+}
+
+// rebuild flattens the expanded part of the tree into rows.
+func (t *TreeView) rebuild() {
+	t.rows = t.rows[:0]
+	if t.Model != nil {
+		t.appendRows(nil, 0, nil)
+	}
+	t.metrics.reset(len(t.rows), t.RowHeight, nil)
+
+	t.current = -1
+	for i, r := range t.rows {
+		if !r.placeholder && r.node == t.selected && t.selected != nil {
+			t.current = i
+		}
+	}
+	t.RequestRepaint()
+}
+
+func (t *TreeView) appendRows(parent interface{}, depth int, guides []bool) {
+	children, ok := t.loadChildren(parent)
+	if !ok {
+		t.rows = append(t.rows, treeRow{depth: depth, last: true, guides: guides, placeholder: true, err: t.errors[parent]})
+		return
+	}
+	for i, c := range children {
+		last := i == len(children)-1
+		t.rows = append(t.rows, treeRow{node: c, depth: depth, last: last, guides: guides})
+		if t.expanded[c] {
+			// Own slice per level, siblings share the parent's
+			g := append(append([]bool(nil), guides...), !last)
+			t.appendRows(c, depth+1, g)
+		}
+	}
+}
+
+// Expansion
+
+func (t *TreeView) IsExpanded(node interface{}) bool {
+	return t.expanded[node]
+}
+
+func (t *TreeView) Expand(node interface{}) {
+	t.setExpanded(node, true)
+}
+
+func (t *TreeView) Collapse(node interface{}) {
+	t.setExpanded(node, false)
+}
+
+func (t *TreeView) Toggle(node interface{}) {
+	t.setExpanded(node, !t.expanded[node])
+}
+
+func (t *TreeView) setExpanded(node interface{}, expanded bool) {
+	if node == nil || t.expanded[node] == expanded || (expanded && !t.Model.HasChildren(node)) {
+		return
+	}
+	if expanded {
+		t.expanded[node] = true
+	} else {
+		delete(t.expanded, node)
+		delete(t.errors, node) // Retry on the next expand
+	}
+	t.rebuild()
+	if t.OnExpand != nil {
+		t.OnExpand(node, expanded)
+	}
+}
+
+// Selection
+
+// Selected returns the selected node, or nil.
+func (t *TreeView) Selected() interface{} {
+	return t.selected
+}
+
+// Select selects a visible node and scrolls to it.
+func (t *TreeView) Select(node interface{}) {
+	for i, r := range t.rows {
+		if !r.placeholder && r.node == node {
+			t.selectRow(i)
+			return
+		}
+	}
+}
+
+func (t *TreeView) selectRow(i int) {
+	if i < 0 || i >= len(t.rows) {
+		return
+	}
+	t.current = i
+	t.scrollToRow(i)
+	t.RequestRepaint()
+	r := t.rows[i]
+	if r.placeholder || r.node == t.selected {
+		return
+	}
+	t.selected = r.node
+	if t.OnSelectionChanged != nil {
+		t.OnSelectionChanged(r.node)
+	}
+}
+
+// parentRow returns the row of the parent of row i, or -1.
+func (t *TreeView) parentRow(i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if t.rows[j].depth < t.rows[i].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// Scrolling
+
+func (t *TreeView) viewport() (x, y, w, h int32) {
+	x, y, w, h = t.Bounds.X+1, t.Bounds.Y+1, t.Bounds.Width-2, t.Bounds.Height-2
+	if t.metrics.total() > int64(h) {
+		w -= scrollBarThickness
+	}
+	return x, y, max(w, 0), max(h, 0)
+}
+
+func (t *TreeView) maxOffset() float64 {
+	_, _, _, h := t.viewport()
+	return float64(max(0, t.metrics.total()-int64(h)))
+}
+
+func (t *TreeView) scrollBy(d float64) bool {
+	target := clampScroll(t.target+d, t.maxOffset())
+	if target == t.target {
+		return false
+	}
+	t.target = target
+	if !t.Smooth {
+		t.offset = target
+	}
+	t.lastTick = time.Now()
+	StartAnimation(t)
+	return true
+}
+
+func (t *TreeView) scrollToRow(i int) {
+	_, _, _, h := t.viewport()
+	target := ensureSpan(t.target, float64(t.metrics.top(i)), float64(t.metrics.height(i)), float64(h))
+	t.scrollBy(target - t.target)
+}
+
+// Tick animates scrolling and the loading spinner.
+func (t *TreeView) Tick(now time.Time) bool {
+	if t.lastTick.IsZero() {
+		t.lastTick = now
+	}
+	var done bool
+	t.offset, done = easeScroll(t.offset, t.target, now.Sub(t.lastTick))
+	t.lastTick = now
+	t.RequestRepaint()
+	return !done || len(t.loading) > 0
+}
+
+// rowAt returns the row at a window position, or -1.
+func (t *TreeView) rowAt(px, py int32) int {
+	x, y, w, h := t.viewport()
+	if px < x || px >= x+w || py < y || py >= y+h {
+		return -1
+	}
+	pos := int64(py-y) + int64(t.offset)
+	if pos >= t.metrics.total() {
+		return -1
+	}
+	return t.metrics.indexAt(pos)
+}
+
+// indentX returns the left edge of the expander of a row at depth.
+func (t *TreeView) indentX(depth int) int32 {
+	x, _, _, _ := t.viewport()
+	return x + 4 + int32(depth)*t.Indent
+}
+
+// Rendering
+
+func (t *TreeView) Render(canvas *render.Canvas) {
+	if !t.Visible {
+		return
+	}
+	t.offset = clampScroll(t.offset, t.maxOffset())
+	t.target = clampScroll(t.target, t.maxOffset())
+	canvas.SetFont(t.Font)
+
+	b := t.Bounds
+	th := theme.Current()
+	canvas.FillRect(b.X, b.Y, b.Width, b.Height, theme.Or(t.BgColor, th.Background))
+
+	x, y, w, h := t.viewport()
+	if len(t.rows) > 0 && h > 0 {
+		canvas.PushClip(x, y, w, h)
+		first := t.metrics.indexAt(int64(t.offset))
+		last := t.metrics.indexAt(int64(t.offset) + int64(h) - 1)
+		for i := first; i <= last; i++ {
+			top := y + int32(t.metrics.top(i)-int64(t.offset))
+			t.renderRow(canvas, i, x, top, w, t.metrics.height(i))
+		}
+		canvas.PopClip()
+	}
+
+	t.bar.Visible = w < b.Width-2
+	t.bar.SetBounds(x+w, y, scrollBarThickness, h)
+	t.bar.Max, t.bar.PageSize, t.bar.Value = float64(t.metrics.total()), float64(h), t.offset
+	t.bar.SmallStep = float64(t.WheelStep)
+	t.bar.Render(canvas)
+
+	borderColor := th.Border
+	if t.isFocused {
+		borderColor = th.Focus
+	}
+	drawRectOutline(canvas, b.X, b.Y, b.Width, b.Height, borderColor)
+	t.RepaintRequested = false
+}
+
+func (t *TreeView) renderRow(canvas *render.Canvas, i int, x, y, w, h int32) {
+	r := t.rows[i]
+	th := theme.Current()
+	if !r.placeholder && r.node == t.selected && t.selected != nil {
+		canvas.FillRect(x, y, w, h, theme.Or(t.SelectedColor, th.Selection))
+	} else if i == t.hover {
+		canvas.FillRect(x, y, w, h, th.Hover)
+	}
+
+	// Indentation guides: ancestors with later siblings, then the
+	// connector from the parent
+	if t.ShowGuides {
+		for j := 0; j < r.depth-1; j++ {
+			if r.guides[j+1] {
+				t.dottedLine(canvas, t.indentX(j)+t.Indent/2, y, h, true)
+			}
+		}
+		if r.depth > 0 {
+			cx, cy := t.indentX(r.depth-1)+t.Indent/2, y+h/2
+			if r.last {
+				t.dottedLine(canvas, cx, y, cy-y, true)
+			} else {
+				t.dottedLine(canvas, cx, y, h, true)
+			}
+			t.dottedLine(canvas, cx, cy, t.indentX(r.depth)+4-cx, false)
+		}
+	}
+
+	ex := t.indentX(r.depth)
+	textX := ex + t.Indent
+	if r.placeholder {
+		if r.err != nil {
+			_, textH := render.MeasureText(r.err.Error(), t.Font)
+			canvas.DrawText(textX, y+(h-textH)/2, r.err.Error(), th.Error)
+			return
+		}
+		drawSpinner(canvas, ex+t.Indent/2, y+h/2, time.Now())
+		_, textH := render.MeasureText("Loading...", t.Font)
+		canvas.DrawText(textX, y+(h-textH)/2, "Loading...", th.TextDisabled)
+		return
+	}
+
+	// Expander
+	expanded := t.expanded[r.node]
+	if t.Model.HasChildren(r.node) {
+		if expanded {
+			drawArrowGlyph(canvas, ex, y, t.Indent, h, Vertical, true, th.Icon)
+		} else {
+			drawArrowGlyph(canvas, ex, y, t.Indent, h, Horizontal, true, th.Icon)
+		}
+	}
+
+	if icons, ok := t.Model.(TreeIcons); ok {
+		if icon := icons.NodeIcon(r.node, expanded); icon != nil {
+			iw, ih := icon.ImageSize()
+			iw, ih = min(iw, h), min(ih, h)
+			icon.SetBounds(textX, y+(h-ih)/2, iw, ih)
+			icon.Render(canvas)
+			textX += iw + 4
+		}
+	}
+
+	text := t.Model.NodeText(r.node)
+	_, textH := render.MeasureText(text, t.Font)
+	canvas.DrawText(textX, y+(h-textH)/2, text, th.Text)
+
+	if t.isFocused && i == t.current {
+		drawRectOutline(canvas, x, y, w, h, th.Focus)
+	}
+}
+
+// dottedLine draws every other pixel of a horizontal or vertical line.
+func (t *TreeView) dottedLine(canvas *render.Canvas, x, y, length int32, vertical bool) {
+	color := theme.Or(t.GuideColor, theme.Current().TextDisabled)
+	for i := int32(0); i < length; i += 2 {
+		if vertical {
+			canvas.SetPixel(x, y+i, color)
+		} else {
+			canvas.SetPixel(x+i, y, color)
+		}
+	}
+}
+
+// drawSpinner draws a ring of dots around (cx, cy), fading from the text
+// colour into the background, whose strongest dot rotates over time.
+func drawSpinner(canvas *render.Canvas, cx, cy int32, now time.Time) {
+	const dots = 8
+	phase := int(now.UnixMilli()/100) % dots
+	th := theme.Current()
+	for i := 0; i < dots; i++ {
+		a := 2 * math.Pi * float64(i) / dots
+		x := cx + int32(math.Round(5*math.Cos(a)))
+		y := cy + int32(math.Round(5*math.Sin(a)))
+		shade := 0x30 + 0x18*((i-phase+dots)%dots)
+		canvas.FillRect(x-1, y-1, 2, 2, theme.Mix(th.Background, th.Text, float64(min(shade, 0xE0))/0xFF))
+	}
+}
+
+// Events
+
+func (t *TreeView) OnFocus() {
+	t.isFocused = true
+	t.RequestRepaint()
+}
+
+func (t *TreeView) OnBlur() {
+	t.isFocused = false
+	t.RequestRepaint()
+}
+
+func (t *TreeView) AcceptsFocus() bool {
+	return true
+}
+
+func (t *TreeView) activate(i int) {
+	if i < 0 || i >= len(t.rows) || t.rows[i].placeholder {
+		return
+	}
+	if t.OnActivate != nil {
+		t.OnActivate(t.rows[i].node)
+	}
+}
+
+func (t *TreeView) OnEvent(evt event.Event) bool {
+	if !t.Visible {
+		return false
+	}
+
+	switch evt.Type {
+	case event.EventMouseWheel:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !t.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		return t.scrollBy(-float64(data.Delta) * float64(t.WheelStep) / 120)
+
+	case event.EventMouseClick:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok || !t.Bounds.Contains(data.X, data.Y) {
+			return false
+		}
+		if t.bar.OnEvent(evt) {
+			return true
+		}
+		i := t.rowAt(data.X, data.Y)
+		if i < 0 || t.rows[i].placeholder {
+			return true
+		}
+		node := t.rows[i].node
+		ex := t.indentX(t.rows[i].depth)
+		if data.X >= ex && data.X < ex+t.Indent {
+			t.Toggle(node) // Expander
+			return true
+		}
+		t.selectRow(i)
+		if data.Clicks == 2 {
+			t.Toggle(node)
+			t.activate(t.current)
+		}
+		return true
+
+	case event.EventMouseMove, event.EventMouseRelease:
+		data, ok := evt.Data.(event.MouseEvent)
+		if !ok {
+			return false
+		}
+		if t.bar.OnEvent(evt) {
+			return true
+		}
+		if evt.Type == event.EventMouseMove {
+			if i := t.rowAt(data.X, data.Y); i != t.hover {
+				t.hover = i
+				t.RequestRepaint()
+			}
+		}
+
+	case event.EventKeyPress:
+		data, ok := evt.Data.(event.KeyEvent)
+		if !ok || !t.isFocused || len(t.rows) == 0 {
+			return false
+		}
+		_, _, _, h := t.viewport()
+		if i, ok := t.metrics.navigate(data.VirtualKeyCode, t.current, h); ok {
+			t.selectRow(i)
+			return true
+		}
+		cur := t.current
+		if cur < 0 || t.rows[cur].placeholder {
+			return false
+		}
+		node := t.rows[cur].node
+		switch data.VirtualKeyCode {
+		case 0x27: // Right: expand, or go to the first child
+			if !t.expanded[node] {
+				t.Expand(node)
+			} else if cur+1 < len(t.rows) && t.rows[cur+1].depth > t.rows[cur].depth {
+				t.selectRow(cur + 1)
+			}
+			return true
+		case 0x25: // Left: collapse, or go to the parent
+			if t.expanded[node] {
+				t.Collapse(node)
+			} else {
+				t.selectRow(t.parentRow(cur))
+			}
+			return true
+		case 0x0D: // Enter
+			t.activate(cur)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package component
+
+import (
+	"sort"
+
+	"github.com/jacksalad/goui_v0/event"
+)
+
+// Helpers shared by the virtualized item views (ListView, Table, TreeView).
+
+// rowMetrics maps between row indices and vertical positions. Rows are
+// either all of the same height or have their offsets precomputed.
+type rowMetrics struct {
+	count   int
+	fixed   int32
+	offsets []int64 // count+1 entries, nil for fixed heights
+}
+
+// reset recomputes the metrics; height may be nil for fixed-height rows.
+func (m *rowMetrics) reset(count int, fixed int32, height func(int) int32) {
+	m.count, m.fixed = count, fixed
+	if height == nil {
+		m.offsets = nil
+		return
+	}
+	if cap(m.offsets) >= count+1 {
+		m.offsets = m.offsets[:count+1]
+	} else {
+		m.offsets = make([]int64, count+1)
+	}
+	m.offsets[0] = 0
+	for i := 0; i < count; i++ {
+		m.offsets[i+1] = m.offsets[i] + int64(height(i))
+	}
+}
+
+func (m *rowMetrics) top(i int) int64 {
+	if m.offsets == nil {
+		return int64(i) * int64(m.fixed)
+	}
+	return m.offsets[i]
+}
+
+func (m *rowMetrics) height(i int) int32 {
+	if m.offsets == nil {
+		return m.fixed
+	}
+	return int32(m.offsets[i+1] - m.offsets[i])
+}
+
+func (m *rowMetrics) total() int64 {
+	return m.top(m.count)
+}
+
+// indexAt returns the row at y, clamped to the valid rows, or -1 if there
+// are none.
+func (m *rowMetrics) indexAt(y int64) int {
+	if m.count == 0 {
+		return -1
+	}
+	var i int
+	if m.offsets == nil {
+		if m.fixed > 0 {
+			i = int(y / int64(m.fixed))
+		}
+	} else {
+		i = sort.Search(m.count, func(i int) bool { return m.offsets[i+1] > y }) // First row ending below y
+	}
+	return max(0, min(i, m.count-1))
+}
+
+// indexRange is the half-open range of indices [start, end).
+type indexRange struct {
+	start, end int
+}
+
+// indexSet is a set of indices stored as sorted, disjoint ranges, so that
+// selecting a million rows costs a single entry.
+type indexSet struct {
+	ranges []indexRange
+}
+
+func (s *indexSet) contains(i int) bool {
+	n := sort.Search(len(s.ranges), func(n int) bool { return s.ranges[n].end > i })
+	return n < len(s.ranges) && s.ranges[n].start <= i
+}
+
+func (s *indexSet) add(start, end int) {
+	if start >= end {
+		return
+	}
+	out := make([]indexRange, 0, len(s.ranges)+1)
+	i := 0
+	for ; i < len(s.ranges) && s.ranges[i].end < start; i++ {
+		out = append(out, s.ranges[i])
+	}
+	// Merge overlapping and adjacent ranges
+	for ; i < len(s.ranges) && s.ranges[i].start <= end; i++ {
+		start = min(start, s.ranges[i].start)
+		end = max(end, s.ranges[i].end)
+	}
+	out = append(out, indexRange{start, end})
+	s.ranges = append(out, s.ranges[i:]...)
+}
+
+func (s *indexSet) remove(start, end int) {
+	if start >= end {
+		return
+	}
+	out := make([]indexRange, 0, len(s.ranges)+1)
+	for _, r := range s.ranges {
+		if r.end <= start || r.start >= end {
+			out = append(out, r)
+			continue
+		}
+		if r.start < start {
+			out = append(out, indexRange{r.start, start})
+		}
+		if r.end > end {
+			out = append(out, indexRange{end, r.end})
+		}
+	}
+	s.ranges = out
+}
+
+// setIndices replaces the set with the given indices, in any order.
+func (s *indexSet) setIndices(indices []int) {
+	sort.Ints(indices)
+	s.ranges = nil
+	for _, i := range indices {
+		if n := len(s.ranges); n > 0 && s.ranges[n-1].end >= i {
+			s.ranges[n-1].end = max(s.ranges[n-1].end, i+1)
+			continue
+		}
+		s.ranges = append(s.ranges, indexRange{i, i + 1})
+	}
+}
+
+func (s *indexSet) clear() {
+	s.ranges = nil
+}
+
+// truncate drops indices >= n.
+func (s *indexSet) truncate(n int) {
+	s.remove(n, int(^uint(0)>>1))
+}
+
+func (s *indexSet) len() int {
+	n := 0
+	for _, r := range s.ranges {
+		n += r.end - r.start
+	}
+	return n
+}
+
+// first returns the smallest index, or -1 if the set is empty.
+func (s *indexSet) first() int {
+	if len(s.ranges) == 0 {
+		return -1
+	}
+	return s.ranges[0].start
+}
+
+func (s *indexSet) indices() []int {
+	res := make([]int, 0, s.len())
+	for _, r := range s.ranges {
+		for i := r.start; i < r.end; i++ {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func (s *indexSet) equal(other *indexSet) bool {
+	if len(s.ranges) != len(other.ranges) {
+		return false
+	}
+	for i, r := range s.ranges {
+		if other.ranges[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *indexSet) clone() indexSet {
+	return indexSet{ranges: append([]indexRange(nil), s.ranges...)}
+}
+
+// navigate returns the row a navigation key moves to from cur, with page
+// the viewport height for Page Up/Down. ok is false for other keys.
+func (m *rowMetrics) navigate(key uint32, cur int, page int32) (int, bool) {
+	if m.count == 0 {
+		return -1, false
+	}
+	if cur < 0 {
+		cur = 0
+		if key == 0x26 || key == 0x28 { // Up/Down start at the first row
+			return 0, true
+		}
+	}
+	switch key {
+	case 0x26: // Up
+		return max(cur-1, 0), true
+	case 0x28: // Down
+		return min(cur+1, m.count-1), true
+	case 0x21: // Page Up
+		return m.indexAt(m.top(cur) - int64(page) + int64(m.height(cur))), true
+	case 0x22: // Page Down
+		return m.indexAt(m.top(cur) + int64(page) - 1), true
+	case 0x24: // Home
+		return 0, true
+	case 0x23: // End
+		return m.count - 1, true
+	}
+	return cur, false
+}
+
+// SelectionMode decides how many items of a view can be selected.
+type SelectionMode int
+
+const (
+	SelectionSingle SelectionMode = iota
+	SelectionMulti                // Ctrl toggles, Shift selects ranges
+	SelectionNone
+)
+
+// itemSelection is the selection state shared by the item views: the
+// selected indices, the current (keyboard) item and the anchor of Shift
+// range selections.
+type itemSelection struct {
+	set     indexSet
+	current int
+	anchor  int
+}
+
+func newItemSelection() itemSelection {
+	return itemSelection{current: -1, anchor: -1}
+}
+
+// selectAt applies a click or keyboard move to item i with the given
+// modifiers and reports whether the selection changed.
+func (s *itemSelection) selectAt(mode SelectionMode, i int, mods uint32) bool {
+	before := s.set.clone()
+	s.current = i
+	switch mode {
+	case SelectionSingle:
+		s.set.clear()
+		s.set.add(i, i+1)
+		s.anchor = i
+	case SelectionMulti:
+		switch {
+		case mods&event.ModShift != 0:
+			if s.anchor < 0 {
+				s.anchor = i
+			}
+			if mods&event.ModCtrl == 0 {
+				s.set.clear()
+			}
+			s.set.add(min(s.anchor, i), max(s.anchor, i)+1)
+		case mods&event.ModCtrl != 0:
+			s.toggle(i)
+			s.anchor = i
+		default:
+			s.set.clear()
+			s.set.add(i, i+1)
+			s.anchor = i
+		}
+	}
+	return !before.equal(&s.set)
+}
+
+func (s *itemSelection) toggle(i int) {
+	if s.set.contains(i) {
+		s.set.remove(i, i+1)
+	} else {
+		s.set.add(i, i+1)
+	}
+}
+
+// truncate forgets items >= n after the data shrank.
+func (s *itemSelection) truncate(n int) bool {
+	before := s.set.clone()
+	s.set.truncate(n)
+	if s.current >= n {
+		s.current = n - 1
+	}
+	if s.anchor >= n {
+		s.anchor = n - 1
+	}
+	return !before.equal(&s.set)
+}
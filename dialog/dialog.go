@@ -0,0 +1,127 @@
+// Package dialog provides ready-made modal dialogs for asking the user
+// simple questions.
+//
+// The functions can be called from any goroutine: the dialog is opened on
+// the UI thread, in the active window, and the function returns at once.
+// The answer is delivered both to the optional callback, which runs on the
+// UI thread, and to the returned channel, which is buffered so that it
+// never blocks the UI. Do not wait on the channel on the UI thread itself;
+// use the callback there.
+package dialog
+
+import (
+	"strings"
+
+	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/layout"
+)
+
+// PromptResult is the answer to a Prompt.
+type PromptResult struct {
+	Value string
+	OK    bool // False if the dialog was cancelled
+}
+
+// Message shows text with an OK button. The channel is closed when the
+// dialog is dismissed.
+func Message(title, text string, onClose func()) <-chan struct{} {
+	done := make(chan struct{})
+	component.Post(func() {
+		d := component.NewDialog(title, textPanel(text))
+		ok := d.AddButton("OK", "ok")
+		d.DefaultButton, d.CancelButton = ok, ok
+		d.OnClose = func(string) {
+			if onClose != nil {
+				onClose()
+			}
+			close(done)
+		}
+		show(d)
+	})
+	return done
+}
+
+// Confirm asks a question with OK and Cancel buttons and reports whether
+// OK was chosen. Enter chooses OK, Escape cancels.
+func Confirm(title, text string, onResult func(ok bool)) <-chan bool {
+	result := make(chan bool, 1)
+	component.Post(func() {
+		d := component.NewDialog(title, textPanel(text))
+		d.DefaultButton = d.AddButton("OK", "ok")
+		d.CancelButton = d.AddButton("Cancel", "")
+		d.OnClose = func(r string) {
+			if onResult != nil {
+				onResult(r == "ok")
+			}
+			result <- r == "ok"
+		}
+		show(d)
+	})
+	return result
+}
+
+// Prompt asks for a line of text, starting with initial. Enter accepts
+// the text, Escape cancels.
+func Prompt(title, text, initial string, onResult func(value string, ok bool)) <-chan PromptResult {
+	result := make(chan PromptResult, 1)
+	component.Post(func() {
+		input := component.NewTextBox(260)
+		input.SetText(initial)
+		input.SelectAll()
+
+		d := component.NewDialog(title, textPanel(text, input))
+		d.DefaultButton = d.AddButton("OK", "ok")
+		d.CancelButton = d.AddButton("Cancel", "")
+		d.OnClose = func(r string) {
+			answer := PromptResult{OK: r == "ok"}
+			if answer.OK {
+				answer.Value = input.Text
+			}
+			if onResult != nil {
+				onResult(answer.Value, answer.OK)
+			}
+			result <- answer
+		}
+		show(d)
+	})
+	return result
+}
+
+// show opens d, or cancels it at once if there is no window to show it in.
+func show(d *component.Dialog) {
+	if component.ActiveOverlay() == nil {
+		d.OnClose("")
+		return
+	}
+	d.Show()
+}
+
+// textPanel stacks the lines of text and the extra components.
+func textPanel(text string, extra ...component.Component) *component.Panel {
+	const spacing = 6
+	p := component.NewPanel(0, 0, 0, 0)
+	var w, h int32
+	add := func(c component.Component) {
+		cw, ch := c.GetPreferredSize()
+		w = max(w, cw)
+		if len(p.Children) > 0 {
+			h += spacing
+		}
+		h += ch
+		p.Add(c)
+	}
+	if text != "" {
+		for _, line := range strings.Split(text, "\n") {
+			if line == "" {
+				line = " " // Keep the height of empty lines
+			}
+			add(component.NewLabel(line))
+		}
+	}
+	for _, c := range extra {
+		add(c)
+	}
+	p.SetBounds(0, 0, w, h)
+	p.SetLayout(&layout.VBoxLayout{Spacing: spacing})
+	return p
+}
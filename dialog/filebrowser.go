@@ -0,0 +1,479 @@
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileMode tells whether a file dialog opens or saves files.
+type FileMode int
+
+const (
+	ModeOpen FileMode = iota
+	ModeSave
+)
+
+// FileFilter restricts the files listed to some extensions, e.g.
+// FileFilter{"Images", []string{".png", ".jpg"}}. No extensions means all
+// files.
+type FileFilter struct {
+	Name       string
+	Extensions []string
+}
+
+// AllFiles is the filter that lists every file.
+var AllFiles = FileFilter{Name: "All files"}
+
+// Match reports whether a file name has one of the filter's extensions.
+func (f FileFilter) Match(name string) bool {
+	if len(f.Extensions) == 0 {
+		return true
+	}
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range f.Extensions {
+		if strings.ToLower(e) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the name with the patterns, e.g. "Images (*.png;*.jpg)".
+func (f FileFilter) String() string {
+	if len(f.Extensions) == 0 {
+		return f.Name + " (*.*)"
+	}
+	return f.Name + " (" + f.patterns() + ")"
+}
+
+func (f FileFilter) patterns() string {
+	if len(f.Extensions) == 0 {
+		return "*.*"
+	}
+	p := make([]string, len(f.Extensions))
+	for i, e := range f.Extensions {
+		p[i] = "*" + e
+	}
+	return strings.Join(p, ";")
+}
+
+// FileEntry is a file or folder listed by a FileBrowser.
+type FileEntry struct {
+	Name    string
+	Path    string
+	IsDir   bool
+	Hidden  bool
+	Size    int64
+	ModTime time.Time
+}
+
+// FileSystem is what a FileBrowser browses. OSFileSystem is the local
+// filesystem; FSFileSystem browses an fs.FS, e.g. a fstest.MapFS in tests.
+type FileSystem interface {
+	ReadDir(dir string) ([]FileEntry, error)
+	Stat(path string) (FileEntry, error)
+	// Join resolves a name typed by the user against dir. Absolute names
+	// are returned as they are.
+	Join(dir, name string) string
+	// Parent returns the folder containing path, or "" for a root.
+	Parent(path string) string
+	// Roots returns the top-level folders, e.g. the drives on Windows.
+	Roots() []string
+}
+
+// OSFileSystem is the local filesystem.
+var OSFileSystem FileSystem = osFileSystem{}
+
+type osFileSystem struct{}
+
+func (osFileSystem) ReadDir(dir string) ([]FileEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && len(entries) == 0 {
+		return nil, err
+	}
+	files := make([]FileEntry, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue // Deleted meanwhile
+		}
+		files = append(files, osEntry(filepath.Join(dir, e.Name()), info))
+	}
+	return files, nil
+}
+
+func (osFileSystem) Stat(p string) (FileEntry, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return FileEntry{}, err
+	}
+	return osEntry(p, info), nil
+}
+
+func osEntry(p string, info fs.FileInfo) FileEntry {
+	e := FileEntry{
+		Name:    info.Name(),
+		Path:    p,
+		IsDir:   info.IsDir(),
+		Hidden:  strings.HasPrefix(info.Name(), "."),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if attr, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		e.Hidden = e.Hidden || attr.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+	}
+	return e
+}
+
+func (osFileSystem) Join(dir, name string) string {
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(dir, name)
+}
+
+func (osFileSystem) Parent(p string) string {
+	parent := filepath.Dir(filepath.Clean(p))
+	if parent == filepath.Clean(p) {
+		return ""
+	}
+	return parent
+}
+
+func (osFileSystem) Roots() []string {
+	if drives := logicalDrives(); len(drives) > 0 {
+		return drives
+	}
+	return []string{string(filepath.Separator)}
+}
+
+// FSFileSystem browses an fs.FS. Paths are slash-separated and "." is the
+// root.
+type FSFileSystem struct {
+	FS fs.FS
+}
+
+func (f FSFileSystem) ReadDir(dir string) ([]FileEntry, error) {
+	entries, err := fs.ReadDir(f.FS, dir)
+	if err != nil && len(entries) == 0 {
+		return nil, err
+	}
+	files := make([]FileEntry, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, fsEntry(path.Join(dir, e.Name()), info))
+	}
+	return files, nil
+}
+
+func (f FSFileSystem) Stat(p string) (FileEntry, error) {
+	info, err := fs.Stat(f.FS, p)
+	if err != nil {
+		return FileEntry{}, err
+	}
+	return fsEntry(p, info), nil
+}
+
+func fsEntry(p string, info fs.FileInfo) FileEntry {
+	return FileEntry{
+		Name:    info.Name(),
+		Path:    p,
+		IsDir:   info.IsDir(),
+		Hidden:  strings.HasPrefix(info.Name(), "."),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+}
+
+func (f FSFileSystem) Join(dir, name string) string {
+	name = filepath.ToSlash(name)
+	if strings.HasPrefix(name, "/") {
+		return path.Clean(strings.TrimPrefix(name, "/"))
+	}
+	return path.Join(dir, name)
+}
+
+func (f FSFileSystem) Parent(p string) string {
+	if p = path.Clean(p); p == "." {
+		return ""
+	}
+	return path.Dir(p)
+}
+
+func (f FSFileSystem) Roots() []string {
+	return []string{"."}
+}
+
+// Recent folders are shared by all file dialogs on the local filesystem,
+// most recent first.
+var (
+	recentMu      sync.Mutex
+	recentFolders []string
+)
+
+// MaxRecentFolders is the number of folders RecentFolders remembers.
+const MaxRecentFolders = 10
+
+// RecentFolders returns the folders files were last chosen in, most recent
+// first.
+func RecentFolders() []string {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	return append([]string(nil), recentFolders...)
+}
+
+// AddRecentFolder moves dir to the front of RecentFolders, e.g. to restore
+// them from the application's settings.
+func AddRecentFolder(dir string) {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	recentFolders = addRecent(recentFolders, dir)
+}
+
+func addRecent(list []string, dir string) []string {
+	out := []string{dir}
+	for _, d := range list {
+		if d != dir && len(out) < MaxRecentFolders {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// FileBrowser is the state of a file dialog: the folder shown, its
+// listing, the filter and the selection. It has no user interface, so it
+// can be driven headless, e.g. in tests; FileDialog shows one.
+type FileBrowser struct {
+	FS         FileSystem
+	Mode       FileMode
+	Multi      bool // Several files can be opened at once
+	Filters    []FileFilter
+	Filter     int // Index into Filters
+	ShowHidden bool
+
+	Dir      string
+	Entries  []FileEntry // Folders first, then the files the filter matches
+	Selected []int       // Indices into Entries
+	// Name is the file name box: a name or path, or several quoted names
+	// when Multi. Selecting files fills it in.
+	Name   string
+	Recent []string // Folders files were chosen in, most recent first
+
+	OnChange func() // Called when Dir or Entries change
+}
+
+func NewFileBrowser(fsys FileSystem, mode FileMode) *FileBrowser {
+	return &FileBrowser{FS: fsys, Mode: mode}
+}
+
+// Navigate shows the entries of dir. The selection is cleared; the name
+// is kept, so it can be saved in another folder.
+func (b *FileBrowser) Navigate(dir string) error {
+	entries, err := b.FS.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	b.Dir = dir
+	b.Entries = b.filter(entries)
+	b.Selected = nil
+	b.changed()
+	return nil
+}
+
+// Refresh reads the folder again.
+func (b *FileBrowser) Refresh() error {
+	return b.Navigate(b.Dir)
+}
+
+// Up shows the parent folder.
+func (b *FileBrowser) Up() error {
+	parent := b.FS.Parent(b.Dir)
+	if parent == "" {
+		return nil
+	}
+	return b.Navigate(parent)
+}
+
+// SetFilter lists the files matching Filters[i].
+func (b *FileBrowser) SetFilter(i int) error {
+	if i < 0 || i >= len(b.Filters) {
+		return nil
+	}
+	b.Filter = i
+	return b.Refresh()
+}
+
+func (b *FileBrowser) currentFilter() FileFilter {
+	if b.Filter >= 0 && b.Filter < len(b.Filters) {
+		return b.Filters[b.Filter]
+	}
+	return AllFiles
+}
+
+// filter drops the hidden entries and the files the filter does not
+// match, and sorts folders before files, by name.
+func (b *FileBrowser) filter(entries []FileEntry) []FileEntry {
+	f := b.currentFilter()
+	out := entries[:0]
+	for _, e := range entries {
+		if e.Hidden && !b.ShowHidden {
+			continue
+		}
+		if !e.IsDir && !f.Match(e.Name) {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].IsDir != out[j].IsDir {
+			return out[i].IsDir
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out
+}
+
+func (b *FileBrowser) changed() {
+	if b.OnChange != nil {
+		b.OnChange()
+	}
+}
+
+// Select selects entries by index and puts the names of the selected
+// files in Name. Only one entry stays selected unless Multi.
+func (b *FileBrowser) Select(indices ...int) {
+	b.Selected = b.Selected[:0]
+	for _, i := range indices {
+		if i >= 0 && i < len(b.Entries) {
+			b.Selected = append(b.Selected, i)
+		}
+		if !b.Multi && len(b.Selected) == 1 {
+			break
+		}
+	}
+	var names []string
+	for _, i := range b.Selected {
+		if !b.Entries[i].IsDir {
+			names = append(names, b.Entries[i].Name)
+		}
+	}
+	switch {
+	case len(names) == 1:
+		b.Name = names[0]
+	case len(names) > 1:
+		b.Name = `"` + strings.Join(names, `" "`) + `"`
+	}
+}
+
+// SelectNames selects entries by name.
+func (b *FileBrowser) SelectNames(names ...string) {
+	var indices []int
+	for _, name := range names {
+		for i, e := range b.Entries {
+			if e.Name == name {
+				indices = append(indices, i)
+			}
+		}
+	}
+	b.Select(indices...)
+}
+
+// Activate opens Entries[i] as a double click does: folders are
+// navigated into, files are accepted.
+func (b *FileBrowser) Activate(i int) ([]string, error) {
+	if i < 0 || i >= len(b.Entries) {
+		return nil, nil
+	}
+	if e := b.Entries[i]; e.IsDir {
+		return nil, b.Navigate(e.Path)
+	}
+	b.Select(i)
+	return b.Accept()
+}
+
+// Accept returns the chosen paths, from Name or else the selected files.
+// It returns nil and no error when Name named a folder, which is then
+// navigated into. In ModeOpen the files must exist; in ModeSave the
+// folder must, and the first extension of the filter is added to a name
+// without one.
+func (b *FileBrowser) Accept() ([]string, error) {
+	names := splitNames(b.Name)
+	if len(names) == 0 {
+		for _, i := range b.Selected {
+			names = append(names, b.Entries[i].Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.New("Choose a file")
+	}
+	if len(names) > 1 && (b.Mode == ModeSave || !b.Multi) {
+		return nil, errors.New("Choose a single file")
+	}
+
+	if len(names) == 1 {
+		p := b.FS.Join(b.Dir, names[0])
+		if e, err := b.FS.Stat(p); err == nil && e.IsDir {
+			b.Name = ""
+			return nil, b.Navigate(p)
+		}
+	}
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		p := b.FS.Join(b.Dir, name)
+		switch b.Mode {
+		case ModeOpen:
+			if e, err := b.FS.Stat(p); err != nil || e.IsDir {
+				return nil, fmt.Errorf("%s: file not found", name)
+			}
+		case ModeSave:
+			if f := b.currentFilter(); len(f.Extensions) > 0 && path.Ext(filepath.ToSlash(name)) == "" {
+				p += f.Extensions[0]
+			}
+			dir := b.FS.Parent(p)
+			if e, err := b.FS.Stat(dir); dir != "" && (err != nil || !e.IsDir) {
+				return nil, fmt.Errorf("%s: folder not found", dir)
+			}
+		}
+		paths = append(paths, p)
+	}
+	b.Recent = addRecent(b.Recent, b.FS.Parent(paths[0]))
+	return paths, nil
+}
+
+// Exists reports whether a file exists, e.g. to confirm overwriting it.
+func (b *FileBrowser) Exists(p string) bool {
+	_, err := b.FS.Stat(p)
+	return err == nil
+}
+
+// splitNames splits a file name box into names: either one name, or
+// several in double quotes.
+func splitNames(s string) []string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, `"`) {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+	var names []string
+	for _, part := range strings.Split(s, `"`) {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
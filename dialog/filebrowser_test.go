@@ -0,0 +1,183 @@
+package dialog
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testBrowser(mode FileMode) *FileBrowser {
+	fsys := fstest.MapFS{
+		"readme.txt":      {Data: []byte("hello")},
+		"photo.PNG":       {},
+		"zebra.png":       {},
+		"Apple.jpg":       {},
+		".hidden.png":     {},
+		"docs/notes.txt":  {},
+		"docs/report.txt": {},
+		"Music/song.mp3":  {},
+	}
+	return NewFileBrowser(FSFileSystem{fsys}, mode)
+}
+
+func entryNames(b *FileBrowser) []string {
+	var names []string
+	for _, e := range b.Entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestFileBrowserNavigate(t *testing.T) {
+	b := testBrowser(ModeOpen)
+	changes := 0
+	b.OnChange = func() { changes++ }
+
+	if err := b.Navigate("."); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docs", "Music", "Apple.jpg", "photo.PNG", "readme.txt", "zebra.png"}
+	if got := entryNames(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries of .: got %v, want %v", got, want)
+	}
+
+	b.Select(0)
+	if _, err := b.Activate(0); err != nil {
+		t.Fatal(err)
+	}
+	if b.Dir != "docs" || b.Selected != nil {
+		t.Errorf("after activating docs: Dir %q, Selected %v", b.Dir, b.Selected)
+	}
+	if got := entryNames(b); !reflect.DeepEqual(got, []string{"notes.txt", "report.txt"}) {
+		t.Errorf("entries of docs: got %v", got)
+	}
+
+	if err := b.Navigate("missing"); err == nil {
+		t.Error("navigating to a missing folder succeeded")
+	}
+	if b.Dir != "docs" {
+		t.Errorf("a failed Navigate changed Dir to %q", b.Dir)
+	}
+
+	if err := b.Up(); err != nil || b.Dir != "." {
+		t.Errorf("Up: Dir %q, err %v", b.Dir, err)
+	}
+	if err := b.Up(); err != nil || b.Dir != "." {
+		t.Errorf("Up at the root: Dir %q, err %v", b.Dir, err)
+	}
+	if changes != 3 {
+		t.Errorf("OnChange called %d times, want 3", changes)
+	}
+
+	b.ShowHidden = true
+	b.Refresh()
+	if got := entryNames(b); len(got) != 7 || got[2] != ".hidden.png" {
+		t.Errorf("entries with hidden files: got %v", got)
+	}
+}
+
+func TestFileBrowserFilter(t *testing.T) {
+	b := testBrowser(ModeOpen)
+	b.Filters = []FileFilter{{"Images", []string{".png", ".JPG"}}, AllFiles}
+	b.Navigate(".")
+
+	want := []string{"docs", "Music", "Apple.jpg", "photo.PNG", "zebra.png"}
+	if got := entryNames(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("images: got %v, want %v", got, want)
+	}
+
+	b.SetFilter(1)
+	if got := entryNames(b); len(got) != 6 {
+		t.Errorf("all files: got %v", got)
+	}
+	b.SetFilter(5) // Out of range, ignored
+	if b.Filter != 1 {
+		t.Errorf("Filter is %d after an invalid SetFilter", b.Filter)
+	}
+}
+
+func TestFileBrowserMultiSelect(t *testing.T) {
+	b := testBrowser(ModeOpen)
+	b.Navigate(".")
+
+	// Only one entry is selected unless Multi
+	b.SelectNames("Apple.jpg", "zebra.png")
+	if len(b.Selected) != 1 || b.Name != "Apple.jpg" {
+		t.Errorf("single selection: Selected %v, Name %q", b.Selected, b.Name)
+	}
+	b.Name = `"Apple.jpg" "zebra.png"`
+	if _, err := b.Accept(); err == nil {
+		t.Error("accepted several files without Multi")
+	}
+
+	b.Multi = true
+	b.SelectNames("docs", "Apple.jpg", "zebra.png")
+	if len(b.Selected) != 3 {
+		t.Errorf("Selected %v, want 3 entries", b.Selected)
+	}
+	if want := `"Apple.jpg" "zebra.png"`; b.Name != want {
+		t.Errorf("Name %q, want %q", b.Name, want)
+	}
+	paths, err := b.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Apple.jpg", "zebra.png"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Accept: got %v, want %v", paths, want)
+	}
+
+	b.Name = `"Apple.jpg" "gone.png"`
+	if _, err := b.Accept(); err == nil {
+		t.Error("accepted a missing file")
+	}
+}
+
+func TestFileBrowserSave(t *testing.T) {
+	b := testBrowser(ModeSave)
+	b.Filters = []FileFilter{{"Text", []string{".txt"}}}
+	b.Navigate(".")
+
+	// A folder name is navigated into, keeping nothing in the name box
+	b.Name = "docs"
+	if paths, err := b.Accept(); paths != nil || err != nil || b.Dir != "docs" || b.Name != "" {
+		t.Errorf("accepting a folder: %v, %v, Dir %q, Name %q", paths, err, b.Dir, b.Name)
+	}
+
+	// The filter's extension is added to a name without one
+	b.Name = "draft"
+	paths, err := b.Accept()
+	if err != nil || !reflect.DeepEqual(paths, []string{"docs/draft.txt"}) {
+		t.Errorf("new file: got %v, %v", paths, err)
+	}
+	if b.Exists(paths[0]) {
+		t.Error("a new file exists")
+	}
+
+	// Existing files are accepted, the dialog asks before overwriting
+	b.SelectNames("notes.txt")
+	paths, err = b.Accept()
+	if err != nil || !reflect.DeepEqual(paths, []string{"docs/notes.txt"}) {
+		t.Errorf("existing file: got %v, %v", paths, err)
+	}
+	if !b.Exists(paths[0]) {
+		t.Error("an existing file does not exist")
+	}
+
+	b.Name = "/Music/song.mp3"
+	paths, err = b.Accept()
+	if err != nil || !reflect.DeepEqual(paths, []string{"Music/song.mp3"}) {
+		t.Errorf("absolute name with an extension: got %v, %v", paths, err)
+	}
+	if want := []string{"Music", "docs"}; !reflect.DeepEqual(b.Recent, want) {
+		t.Errorf("Recent %v, want %v", b.Recent, want)
+	}
+
+	b.Name = "nope/file.txt"
+	if _, err := b.Accept(); err == nil {
+		t.Error("saved into a missing folder")
+	}
+	b.Name = `"a.txt" "b.txt"`
+	if _, err := b.Accept(); err == nil {
+		t.Error("saved several files")
+	}
+}
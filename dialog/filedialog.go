@@ -0,0 +1,557 @@
+package dialog
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/event"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
+)
+
+// FileDialog chooses files to open or a file to save. The built-in dialog
+// shows the folders and recent folders in a tree, the files of the current
+// folder in a sortable table with their size and date, a path bar, a name
+// box and the filters. Its logic is a FileBrowser, which can be driven
+// without a window.
+type FileDialog struct {
+	Title    string // "Open" or "Save As" by default
+	Mode     FileMode
+	Multi    bool   // Several files can be opened at once
+	Dir      string // Folder shown first; the most recent one by default
+	FileName string // Name proposed when saving
+	Filters  []FileFilter
+	FS       FileSystem // OSFileSystem by default
+
+	// Native shows the common dialog of Windows instead, falling back to
+	// the built-in one if that fails. Only for OSFileSystem.
+	Native bool
+}
+
+// OpenFile asks for a file to open. The path is "" if the dialog was
+// cancelled.
+func OpenFile(title string, filters []FileFilter, onResult func(path string)) <-chan string {
+	return single(&FileDialog{Title: title, Mode: ModeOpen, Filters: filters}, onResult)
+}
+
+// OpenFiles asks for one or more files to open. The paths are nil if the
+// dialog was cancelled.
+func OpenFiles(title string, filters []FileFilter, onResult func(paths []string)) <-chan []string {
+	f := &FileDialog{Title: title, Mode: ModeOpen, Multi: true, Filters: filters}
+	return f.Show(onResult)
+}
+
+// SaveFile asks where to save a file, proposing name. Choosing an existing
+// file asks for confirmation. The path is "" if the dialog was cancelled.
+func SaveFile(title, name string, filters []FileFilter, onResult func(path string)) <-chan string {
+	return single(&FileDialog{Title: title, Mode: ModeSave, FileName: name, Filters: filters}, onResult)
+}
+
+func single(f *FileDialog, onResult func(path string)) <-chan string {
+	result := make(chan string, 1)
+	f.Show(func(paths []string) {
+		var p string
+		if len(paths) > 0 {
+			p = paths[0]
+		}
+		if onResult != nil {
+			onResult(p)
+		}
+		result <- p
+	})
+	return result
+}
+
+// Show opens the dialog. The paths are nil if it was cancelled.
+func (f *FileDialog) Show(onResult func(paths []string)) <-chan []string {
+	result := make(chan []string, 1)
+	deliver := func(paths []string) {
+		if onResult != nil {
+			onResult(paths)
+		}
+		result <- paths
+	}
+	component.Post(func() {
+		if !f.Native || f.FS != nil {
+			f.showBuiltin(deliver)
+			return
+		}
+		native := *f
+		owner := activeWindow()
+		go func() {
+			paths, ok := runNative(&native, owner)
+			component.Post(func() {
+				if !ok {
+					f.showBuiltin(deliver)
+					return
+				}
+				if len(paths) > 0 {
+					AddRecentFolder(OSFileSystem.Parent(paths[0]))
+				}
+				deliver(paths)
+			})
+		}()
+	})
+	return result
+}
+
+func (f *FileDialog) showBuiltin(deliver func(paths []string)) {
+	fsys := f.FS
+	if fsys == nil {
+		fsys = OSFileSystem
+	}
+	_, local := fsys.(osFileSystem)
+
+	b := NewFileBrowser(fsys, f.Mode)
+	b.Multi = f.Multi && f.Mode == ModeOpen
+	b.Filters = f.Filters
+	if len(b.Filters) == 0 {
+		b.Filters = []FileFilter{AllFiles}
+	}
+	b.Name = f.FileName
+	if local {
+		b.Recent = RecentFolders()
+	}
+
+	title, accept := f.Title, "Open"
+	if f.Mode == ModeSave {
+		accept = "Save"
+	}
+	if title == "" {
+		title = map[FileMode]string{ModeOpen: "Open", ModeSave: "Save As"}[f.Mode]
+	}
+
+	v := newFileView(b)
+	d := component.NewDialog(title, v)
+	d.DefaultButton = d.AddButton(accept, "ok")
+	d.DefaultButton.OnClick = v.accept
+	d.CancelButton = d.AddButton("Cancel", "")
+	d.OnClose = func(r string) {
+		if r != "ok" {
+			deliver(nil)
+			return
+		}
+		if local {
+			AddRecentFolder(fsys.Parent(v.result[0]))
+		}
+		deliver(v.result)
+	}
+	v.dialog = d
+
+	if err := b.Navigate(f.startDir(b)); err != nil {
+		b.Navigate(fsys.Roots()[0])
+	}
+	show(d)
+	if o := component.ActiveOverlay(); d.IsOpen() && o.Focus != nil {
+		if f.Mode == ModeSave {
+			v.name.SelectAll()
+			o.Focus(v.name)
+		} else {
+			o.Focus(v.table)
+		}
+	}
+}
+
+// startDir returns Dir, or the most recent folder, or the working folder.
+func (f *FileDialog) startDir(b *FileBrowser) string {
+	if f.Dir != "" {
+		return f.Dir
+	}
+	if len(b.Recent) > 0 {
+		return b.Recent[0]
+	}
+	if _, local := b.FS.(osFileSystem); local {
+		if wd, err := os.Getwd(); err == nil {
+			return wd
+		}
+	}
+	return b.FS.Roots()[0]
+}
+
+const (
+	fileViewGap   = 8
+	fileGlyphSize = 16
+)
+
+// fileView is the content of the built-in file dialog.
+type fileView struct {
+	component.Panel
+	browser *FileBrowser
+	dialog  *component.Dialog
+	result  []string
+
+	up       *component.Button
+	pathBar  *component.TextBox
+	places   *component.TreeView
+	table    *component.Table
+	split    *component.SplitPane
+	nameText *component.Label
+	name     *component.TextBox
+	filter   *component.ComboBox
+}
+
+func newFileView(b *FileBrowser) *fileView {
+	v := &fileView{browser: b}
+	v.Visible = true
+
+	v.up = component.NewButton("↑")
+	v.up.OnClick = func() { v.navigateErr(b.Up()) }
+	v.pathBar = component.NewTextBox(400)
+	v.pathBar.OnChange = func(string) { v.setError(v.pathBar, nil) }
+
+	v.places = component.NewTreeView(160, 200, newPlacesModel(b))
+	v.places.OnSelectionChanged = func(node interface{}) {
+		if p := node.(*place); p.path != "" && p.path != b.Dir {
+			v.navigateErr(b.Navigate(p.path))
+		}
+	}
+
+	v.table = component.NewTable(400, 200, fileTable{b})
+	v.table.SelectionMode = component.SelectionSingle
+	if b.Multi {
+		v.table.SelectionMode = component.SelectionMulti
+	}
+	v.table.GridLines = false
+	v.table.AddColumn("Name", 240).Renderer = v.drawName
+	v.table.AddColumn("Size", 80).Align = layout.AlignEnd
+	v.table.AddColumn("Modified", 130)
+	v.table.OnSelectionChanged = func() {
+		b.Select(v.table.SelectedRows()...)
+		if b.Name != v.name.Text {
+			v.name.SetText(b.Name)
+		}
+	}
+	v.table.OnActivate = func(row int) {
+		if b.Entries[row].IsDir {
+			v.navigateErr(b.Navigate(b.Entries[row].Path))
+			return
+		}
+		b.Select(row)
+		v.name.SetText(b.Name)
+		v.accept()
+	}
+
+	v.split = component.NewSplitPane(component.Horizontal, v.places, v.table)
+	v.split.Ratio, v.split.DefaultRatio = 0.28, 0.28
+	v.split.Collapsible = true
+
+	v.nameText = component.NewLabel("File name:")
+	v.name = component.NewTextBox(300)
+	v.name.SetText(b.Name)
+	v.name.OnChange = func(text string) {
+		b.Name = text
+		v.setError(v.name, nil)
+	}
+
+	names := make([]string, len(b.Filters))
+	for i, f := range b.Filters {
+		names[i] = f.String()
+	}
+	v.filter = component.NewComboBox(200, names)
+	v.filter.SetSelected(b.Filter)
+	v.filter.OnChange = func(i int, _ string) { v.navigateErr(b.SetFilter(i)) }
+
+	// In tab order
+	for _, c := range []component.Component{v.up, v.pathBar, v.split, v.name, v.filter} {
+		v.Add(c)
+	}
+	b.OnChange = v.reload
+	return v
+}
+
+func (v *fileView) GetPreferredSize() (int32, int32) {
+	return 680, 400
+}
+
+func (v *fileView) SetBounds(x, y, width, height int32) {
+	v.Panel.SetBounds(x, y, width, height)
+
+	_, rowH := v.pathBar.GetPreferredSize()
+	v.up.SetBounds(x, y, rowH+8, rowH)
+	v.pathBar.SetBounds(x+rowH+8+fileViewGap, y, max(0, width-rowH-8-fileViewGap), rowH)
+
+	bottom := y + height - rowH
+	lw, lh := v.nameText.GetPreferredSize()
+	fw, _ := v.filter.GetPreferredSize()
+	v.nameText.SetBounds(x, bottom+(rowH-lh)/2, lw, lh)
+	v.filter.SetBounds(x+width-fw, bottom, fw, rowH)
+	nx := x + lw + fileViewGap
+	v.name.SetBounds(nx, bottom, max(0, x+width-fw-fileViewGap-nx), rowH)
+
+	top := y + rowH + fileViewGap
+	v.split.SetBounds(x, top, width, max(0, bottom-fileViewGap-top))
+}
+
+// reload shows the browser's folder after it changed.
+func (v *fileView) reload() {
+	v.pathBar.SetText(v.browser.Dir)
+	v.setError(v.pathBar, nil)
+	v.table.ClearSelection()
+	v.table.Reload()
+	v.table.ScrollToRow(0)
+	v.RequestRepaint()
+}
+
+func (v *fileView) navigateErr(err error) {
+	v.setError(v.pathBar, err)
+}
+
+func (v *fileView) setError(box *component.TextBox, err error) {
+	text := ""
+	if err != nil {
+		text = err.Error()
+	}
+	if box.ErrorText != text {
+		box.ErrorText = text
+		box.RequestRepaint()
+	}
+}
+
+// accept closes the dialog with the chosen paths, after asking before
+// overwriting a file.
+func (v *fileView) accept() {
+	paths, err := v.browser.Accept()
+	if err != nil {
+		v.setError(v.name, err)
+		return
+	}
+	if paths == nil {
+		v.name.SetText("") // Navigated into the folder typed
+		return
+	}
+	if v.browser.Mode == ModeSave && v.browser.Exists(paths[0]) {
+		name := paths[0][len(v.browser.FS.Parent(paths[0])):]
+		name = strings.TrimLeft(name, `/\`)
+		Confirm("Confirm Save As", fmt.Sprintf("%s already exists.\nDo you want to replace it?", name), func(ok bool) {
+			if ok {
+				v.finish(paths)
+			}
+		})
+		return
+	}
+	v.finish(paths)
+}
+
+func (v *fileView) finish(paths []string) {
+	v.result = paths
+	v.dialog.Close("ok")
+}
+
+// HandleShortcut navigates to the path typed in the path bar on Enter,
+// goes up with Backspace in the file list or Alt+Up, and refreshes on F5.
+func (v *fileView) HandleShortcut(key event.KeyEvent) bool {
+	var focused component.Component
+	if o := component.ActiveOverlay(); o != nil && o.Focused != nil {
+		focused = o.Focused()
+	}
+	switch key.VirtualKeyCode {
+	case 0x0D: // Enter
+		if focused == v.pathBar && key.Modifiers == 0 {
+			dir := v.browser.FS.Join(v.browser.Dir, strings.TrimSpace(v.pathBar.Text))
+			v.navigateErr(v.browser.Navigate(dir))
+			return true
+		}
+	case 0x08: // Backspace
+		if focused == v.table && key.Modifiers == 0 {
+			v.navigateErr(v.browser.Up())
+			return true
+		}
+	case 0x26: // Up
+		if key.Modifiers == event.ModAlt {
+			v.navigateErr(v.browser.Up())
+			return true
+		}
+	case 0x74: // F5
+		v.navigateErr(v.browser.Refresh())
+		return true
+	}
+	return false
+}
+
+// fileTable shows the browser's entries as a TableModel.
+type fileTable struct {
+	b *FileBrowser
+}
+
+func (t fileTable) RowCount() int {
+	return len(t.b.Entries)
+}
+
+func (t fileTable) CellText(row, col int) string {
+	e := t.b.Entries[row]
+	switch col {
+	case 0:
+		return e.Name
+	case 1:
+		if e.IsDir {
+			return ""
+		}
+		return formatSize(e.Size)
+	default:
+		if e.ModTime.IsZero() {
+			return ""
+		}
+		return e.ModTime.Format("2006-01-02 15:04")
+	}
+}
+
+// CompareCells keeps folders before files whatever the sort key.
+func (t fileTable) CompareCells(col, a, b int) int {
+	ea, eb := t.b.Entries[a], t.b.Entries[b]
+	if ea.IsDir != eb.IsDir {
+		if ea.IsDir {
+			return -1
+		}
+		return 1
+	}
+	switch col {
+	case 0:
+		return strings.Compare(strings.ToLower(ea.Name), strings.ToLower(eb.Name))
+	case 1:
+		return compareInt64(ea.Size, eb.Size)
+	default:
+		return ea.ModTime.Compare(eb.ModTime)
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// formatSize returns a size in bytes in the largest fitting unit.
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n) / 1024
+	for _, unit := range []string{"KB", "MB", "GB"} {
+		if size < 1024 {
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+		size /= 1024
+	}
+	return fmt.Sprintf("%.1f TB", size)
+}
+
+// drawName draws the name column with a folder or file glyph.
+func (v *fileView) drawName(canvas *render.Canvas, cell layout.Rect, text string, row, col int, selected bool) {
+	t := theme.Current()
+	x := cell.X + 6
+	y := cell.Y + (cell.Height-fileGlyphSize)/2
+	if v.browser.Entries[row].IsDir {
+		canvas.FillRect(x, y+2, 7, 2, t.Warning) // Tab
+		canvas.FillRect(x, y+4, fileGlyphSize, fileGlyphSize-6, theme.Mix(t.Warning, t.Background, 0.3))
+	} else {
+		canvas.FillRect(x+2, y, fileGlyphSize-4, fileGlyphSize, t.Background)
+		drawOutline(canvas, x+2, y, fileGlyphSize-4, fileGlyphSize, t.Icon)
+		for i := int32(0); i < 3; i++ {
+			canvas.FillRect(x+5, y+4+i*3, fileGlyphSize-10, 1, theme.Mix(t.Icon, t.Background, 0.5))
+		}
+	}
+	_, h := render.MeasureText(text, nil)
+	canvas.DrawText(x+fileGlyphSize+6, cell.Y+(cell.Height-h)/2, text, t.Text)
+}
+
+func drawOutline(canvas *render.Canvas, x, y, w, h int32, color uint32) {
+	canvas.FillRect(x, y, w, 1, color)
+	canvas.FillRect(x, y+h-1, w, 1, color)
+	canvas.FillRect(x, y, 1, h, color)
+	canvas.FillRect(x+w-1, y, 1, h, color)
+}
+
+// place is a node of the places tree: a group of recent folders, or a
+// folder.
+type place struct {
+	path  string // "" for the group
+	label string
+	leaf  bool // Recent folders are not expanded
+}
+
+// placesModel lists the recent folders, then the roots of the filesystem
+// with their subfolders, loaded in the background.
+type placesModel struct {
+	b      *FileBrowser
+	recent *place
+	top    []interface{}
+}
+
+func newPlacesModel(b *FileBrowser) *placesModel {
+	m := &placesModel{b: b, recent: &place{label: "Recent folders"}}
+	if _, local := b.FS.(osFileSystem); local {
+		if home, err := os.UserHomeDir(); err == nil {
+			m.top = append(m.top, &place{path: home, label: "Home"})
+		}
+	}
+	for _, root := range b.FS.Roots() {
+		label := root
+		if root == "." {
+			label = "/"
+		}
+		m.top = append(m.top, &place{path: root, label: label})
+	}
+	return m
+}
+
+func (m *placesModel) Children(node interface{}) []interface{} {
+	if node == nil {
+		if len(m.b.Recent) == 0 {
+			return m.top
+		}
+		return append([]interface{}{m.recent}, m.top...)
+	}
+	if node == m.recent {
+		children := make([]interface{}, len(m.b.Recent))
+		for i, dir := range m.b.Recent {
+			children[i] = &place{path: dir, label: dir, leaf: true}
+		}
+		return children
+	}
+	children, _ := m.folders(node.(*place).path)
+	return children
+}
+
+func (m *placesModel) LoadChildren(node interface{}, done func(children []interface{}, err error)) {
+	if node == nil || node == m.recent {
+		done(m.Children(node), nil)
+		return
+	}
+	go func() {
+		done(m.folders(node.(*place).path))
+	}()
+}
+
+// folders returns the subfolders of dir, hidden ones as the browser does.
+func (m *placesModel) folders(dir string) ([]interface{}, error) {
+	entries, err := m.b.FS.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+	var children []interface{}
+	for _, e := range entries {
+		if e.IsDir && (!e.Hidden || m.b.ShowHidden) {
+			children = append(children, &place{path: e.Path, label: e.Name})
+		}
+	}
+	return children, nil
+}
+
+func (m *placesModel) HasChildren(node interface{}) bool {
+	return node == nil || !node.(*place).leaf
+}
+
+func (m *placesModel) NodeText(node interface{}) string {
+	return node.(*place).label
+}
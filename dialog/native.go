@@ -0,0 +1,172 @@
+package dialog
+
+import (
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+	moduser32   = syscall.NewLazyDLL("user32.dll")
+	modcomdlg32 = syscall.NewLazyDLL("comdlg32.dll")
+	modole32    = syscall.NewLazyDLL("ole32.dll")
+
+	procGetLogicalDrives     = modkernel32.NewProc("GetLogicalDrives")
+	procGetActiveWindow      = moduser32.NewProc("GetActiveWindow")
+	procGetOpenFileNameW     = modcomdlg32.NewProc("GetOpenFileNameW")
+	procGetSaveFileNameW     = modcomdlg32.NewProc("GetSaveFileNameW")
+	procCommDlgExtendedError = modcomdlg32.NewProc("CommDlgExtendedError")
+	procCoInitializeEx       = modole32.NewProc("CoInitializeEx")
+	procCoUninitialize       = modole32.NewProc("CoUninitialize")
+)
+
+const (
+	OFN_OVERWRITEPROMPT      = 0x00000002
+	OFN_HIDEREADONLY         = 0x00000004
+	OFN_NOCHANGEDIR          = 0x00000008
+	OFN_ALLOWMULTISELECT     = 0x00000200
+	OFN_PATHMUSTEXIST        = 0x00000800
+	OFN_FILEMUSTEXIST        = 0x00001000
+	OFN_EXPLORER             = 0x00080000
+	COINIT_APARTMENTTHREADED = 0x2
+
+	nativeBufferSize = 32 * 1024 // Characters, enough for many selected files
+)
+
+// OPENFILENAMEW
+type openFileName struct {
+	StructSize    uint32
+	Owner         uintptr
+	Instance      uintptr
+	Filter        *uint16
+	CustomFilter  *uint16
+	MaxCustFilter uint32
+	FilterIndex   uint32
+	File          *uint16
+	MaxFile       uint32
+	FileTitle     *uint16
+	MaxFileTitle  uint32
+	InitialDir    *uint16
+	Title         *uint16
+	Flags         uint32
+	FileOffset    uint16
+	FileExtension uint16
+	DefExt        *uint16
+	CustData      uintptr
+	Hook          uintptr
+	TemplateName  *uint16
+	Reserved      uintptr
+	Reserved2     uint32
+	FlagsEx       uint32
+}
+
+// logicalDrives returns the drive roots, e.g. `C:\`.
+func logicalDrives() []string {
+	mask, _, _ := procGetLogicalDrives.Call()
+	var drives []string
+	for i := 0; i < 26; i++ {
+		if mask&(1<<i) != 0 {
+			drives = append(drives, string(rune('A'+i))+`:\`)
+		}
+	}
+	return drives
+}
+
+// activeWindow returns the active window of the calling (UI) thread, to
+// own the native dialog.
+func activeWindow() uintptr {
+	hwnd, _, _ := procGetActiveWindow.Call()
+	return hwnd
+}
+
+// runNative shows the common file dialog of Windows. It blocks until the
+// dialog closes, so it runs on its own thread, not the UI one. ok is false
+// if the dialog could not be shown at all; paths is nil if it was
+// cancelled.
+func runNative(f *FileDialog, owner uintptr) (paths []string, ok bool) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if hr, _, _ := procCoInitializeEx.Call(0, COINIT_APARTMENTTHREADED); int32(hr) >= 0 {
+		defer procCoUninitialize.Call()
+	}
+
+	buf := make([]uint16, nativeBufferSize)
+	if f.FileName != "" {
+		copy(buf[:len(buf)-1], syscall.StringToUTF16(f.FileName))
+	}
+	ofn := openFileName{
+		Owner:       owner,
+		File:        &buf[0],
+		MaxFile:     uint32(len(buf)),
+		Flags:       OFN_EXPLORER | OFN_NOCHANGEDIR | OFN_HIDEREADONLY | OFN_PATHMUSTEXIST,
+		FilterIndex: 1,
+	}
+	ofn.StructSize = uint32(unsafe.Sizeof(ofn))
+
+	filters := f.Filters
+	if len(filters) == 0 {
+		filters = []FileFilter{AllFiles}
+	}
+	var spec []uint16
+	for _, filter := range filters {
+		spec = append(spec, syscall.StringToUTF16(filter.String())...)
+		spec = append(spec, syscall.StringToUTF16(filter.patterns())...)
+	}
+	spec = append(spec, 0)
+	ofn.Filter = &spec[0]
+	if f.Title != "" {
+		ofn.Title, _ = syscall.UTF16PtrFromString(f.Title)
+	}
+	if f.Dir != "" {
+		ofn.InitialDir, _ = syscall.UTF16PtrFromString(f.Dir)
+	}
+
+	proc := procGetOpenFileNameW
+	if f.Mode == ModeSave {
+		proc = procGetSaveFileNameW
+		ofn.Flags |= OFN_OVERWRITEPROMPT
+		if len(filters[0].Extensions) > 0 {
+			ofn.DefExt, _ = syscall.UTF16PtrFromString(strings.TrimPrefix(filters[0].Extensions[0], "."))
+		}
+	} else {
+		ofn.Flags |= OFN_FILEMUSTEXIST
+		if f.Multi {
+			ofn.Flags |= OFN_ALLOWMULTISELECT
+		}
+	}
+
+	if r, _, _ := proc.Call(uintptr(unsafe.Pointer(&ofn))); r == 0 {
+		code, _, _ := procCommDlgExtendedError.Call()
+		return nil, code == 0 // 0: cancelled
+	}
+	return splitNativeResult(buf), true
+}
+
+// splitNativeResult decodes the buffer filled by the dialog: a full path,
+// or with several files the folder followed by the names, each null
+// terminated, with an empty string at the end.
+func splitNativeResult(buf []uint16) []string {
+	var parts []string
+	start := 0
+	for i, c := range buf {
+		if c != 0 {
+			continue
+		}
+		if i == start {
+			break
+		}
+		parts = append(parts, syscall.UTF16ToString(buf[start:i]))
+		start = i + 1
+	}
+	if len(parts) <= 1 {
+		return parts
+	}
+	dir := parts[0]
+	paths := make([]string, len(parts)-1)
+	for i, name := range parts[1:] {
+		paths[i] = OSFileSystem.Join(dir, name)
+	}
+	return paths
+}
@@ -18,12 +18,16 @@ const (
 	EventPaint
 	EventResize
 	EventClose
+	EventContextMenu // Right click, or the context menu key; MouseEvent data
 )
 
 type MouseEvent struct {
-	X, Y   int32
-	Button int // 1: Left, 2: Right, 3: Middle
-	Delta  int // For mouse wheel
+	X, Y      int32
+	Button    int    // 1: Left, 2: Right, 3: Middle
+	Clicks    int    // For clicks: 1, or 2 for the second click of a double click
+	Delta     int    // For mouse wheel, 120 per notch (+ is away from the user)
+	DeltaX    int    // For horizontal wheel / trackpad, + is to the right
+	Modifiers uint32 // Same bitmask as KeyEvent.Modifiers
 }
 
 type KeyEvent struct {
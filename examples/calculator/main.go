@@ -40,11 +40,11 @@ func NewCalculator() (*Calculator, error) {
 }
 
 func (c *Calculator) setupUI() {
-	// Main Layout: VBox
-	rootLayout := &layout.VBoxLayout{
-		Padding: 10,
-		Spacing: 10,
-	}
+	// Main Layout: display on top, button grid takes the rest
+	rootLayout := layout.NewFlexLayout(layout.FlexColumn)
+	rootLayout.AlignItems = layout.AlignStretch
+	rootLayout.Spacing = 10
+	c.window.Root.Padding = layout.UniformInsets(10)
 	c.window.Root.SetLayout(rootLayout)
 	c.window.Root.BgColor = 0xFFF0F0F0
 
@@ -53,19 +53,20 @@ func (c *Calculator) setupUI() {
 	btnFont := render.NewFont("SimHei", 16)
 
 	// Display Area
-	c.display = component.NewTextBox(300)
+	c.display = component.NewTextBox(0)
 	c.display.Text = "0"
 	c.display.ReadOnly = true
 	c.display.Font = displayFont
 	c.window.Root.Add(c.display)
 
 	// Button Grid
-	gridPanel := component.NewPanel(0, 0, 300, 350)
+	gridPanel := component.NewPanel(0, 0, 0, 0)
 	gridLayout := layout.NewGridLayout(5, 4) // 5 rows, 4 cols
 	gridLayout.Spacing = 5
 	gridPanel.SetLayout(gridLayout)
 
 	c.window.Root.Add(gridPanel)
+	rootLayout.SetFlex(gridPanel, 1, 1, layout.FlexBasisZero)
 
 	// Buttons definition
 	// Row 0: C, /, *, -
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/jacksalad/goui_v0/chart"
+	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/layout"
+	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/window"
+)
+
+func main() {
+	win, err := window.NewWindow(window.WindowConfig{
+		Title:     "Charts",
+		Width:     1000,
+		Height:    700,
+		Resizable: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	font := render.NewFont("Segoe UI", 13)
+
+	win.Root.BgColor = 0xFFF5F5F5
+	grid := layout.NewTrackGridLayout(
+		[]layout.Track{layout.Fr(1), layout.Fr(1)},
+		[]layout.Track{layout.Fr(1), layout.Fr(1)},
+	)
+	grid.Padding = 12
+	grid.Spacing = 12
+	win.Root.SetLayout(grid)
+
+	// Live time series: a stacked area of two loads and a line on top
+	live := chart.New(480, 320)
+	live.Title = "Load (live)"
+	live.Font = font
+	live.XAxis.Time = true
+	live.YAxis.Title = "%"
+	user := chart.NewSeries("User", chart.Area)
+	user.Stack = "cpu"
+	system := chart.NewSeries("System", chart.Area)
+	system.Stack = "cpu"
+	avg := chart.NewSeries("Average", chart.Line)
+	avg.LineWidth = 1.5
+	for _, s := range []*chart.Series{user, system, avg} {
+		s.MaxPoints = 600
+	}
+	live.AddSeries(user, system, avg)
+	win.Root.Add(live)
+
+	// Grouped and stacked bars by quarter
+	bars := chart.New(480, 320)
+	bars.Title = "Revenue by quarter"
+	bars.Font = font
+	bars.Categories = []string{"Q1", "Q2", "Q3", "Q4"}
+	bars.YAxis.Format = func(v float64) string { return "$" + strconv.FormatFloat(v, 'f', -1, 64) + "k" }
+	for i, name := range []string{"Hardware", "Software", "Services"} {
+		s := chart.NewSeries(name, chart.Bar)
+		if i > 0 {
+			s.Stack = "digital" // Software and services share a bar
+		}
+		for q := 0; q < 4; q++ {
+			s.Add(float64(q), float64(20+rand.Intn(40)))
+		}
+		bars.AddSeries(s)
+	}
+	win.Root.Add(bars)
+
+	// Scatter of two clusters with a fitted line
+	scatter := chart.New(480, 320)
+	scatter.Title = "Height and weight"
+	scatter.Font = font
+	scatter.XAxis.Title = "Height (cm)"
+	scatter.YAxis.Title = "Weight (kg)"
+	scatter.XAxis.Grid = true
+	for i, name := range []string{"Group A", "Group B"} {
+		s := chart.NewSeries(name, chart.Scatter)
+		for j := 0; j < 60; j++ {
+			h := 160 + float64(i)*12 + rand.NormFloat64()*6
+			s.Add(math.Round(h), math.Round(h*0.9-90+rand.NormFloat64()*5))
+		}
+		scatter.AddSeries(s)
+	}
+	fit := chart.NewSeries("Trend", chart.Line)
+	fit.Add(145, 0.9*145-90)
+	fit.Add(190, 0.9*190-90)
+	scatter.AddSeries(fit)
+	win.Root.Add(scatter)
+
+	// Donut
+	pie := chart.NewPie(480, 320)
+	pie.Title = "Disk usage"
+	pie.Font = font
+	pie.HoleRatio = 0.55
+	pie.Format = func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) + " GB" }
+	var total float64
+	for _, s := range []struct {
+		label string
+		gb    float64
+	}{{"System", 42}, {"Apps", 118}, {"Documents", 64}, {"Media", 151}, {"Free", 137}} {
+		pie.AddSlice(s.label, s.gb)
+		total += s.gb
+	}
+	pie.CenterText = strconv.FormatFloat(total, 'f', 0, 64) + " GB"
+	win.Root.Add(pie)
+
+	go func() {
+		u, s := 30.0, 10.0
+		for now := range time.Tick(250 * time.Millisecond) {
+			u = math.Max(0, math.Min(80, u+rand.NormFloat64()*4))
+			s = math.Max(0, math.Min(20, s+rand.NormFloat64()*1.5))
+			component.Post(func() {
+				user.AddTime(now, u)
+				system.AddTime(now, s)
+				avg.AddTime(now, (u+s)*0.8)
+				live.RequestRepaint()
+			})
+		}
+	}()
+
+	win.Show()
+	win.Run()
+}
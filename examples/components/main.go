@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/theme"
 	"github.com/jacksalad/goui_v0/window"
 	"log"
 )
@@ -21,12 +22,10 @@ func main() {
 
 	// Create a main panel
 	mainPanel := component.NewPanel(0, 0, 800, 600)
-	mainPanel.BgColor = 0xFFFFFFFF // White
 	w.Add(mainPanel)
 
-	// Create a sub-panel
+	// Create a sub-panel; its colour follows the theme
 	subPanel := component.NewPanel(50, 50, 700, 500)
-	subPanel.BgColor = 0xFFF0F0F0 // Light gray
 	mainPanel.Add(subPanel)
 
 	// Create a label
@@ -44,6 +43,39 @@ func main() {
 	}
 	subPanel.Add(btn)
 
+	// Create a radio group
+	group := component.NewRadioGroup()
+	for i, size := range []string{"Small", "Medium", "Large"} {
+		radio := component.NewRadioButton(size)
+		radio.SetBounds(100, 220+int32(i)*28, 150, 24)
+		group.Add(radio)
+		subPanel.Add(radio)
+	}
+	group.SetValue("Medium")
+	group.OnChange = func(value string) {
+		label.Text = "Size: " + value
+	}
+
+	// Toggle switches changing the theme of the whole window
+	dark := component.NewToggleSwitch("Dark mode")
+	dark.SetBounds(100, 320, 150, 24)
+	contrast := component.NewToggleSwitch("High contrast")
+	contrast.SetBounds(100, 352, 150, 24)
+	setTheme := func(bool) {
+		switch {
+		case contrast.Checked:
+			theme.Set(theme.HighContrast)
+		case dark.Checked:
+			theme.Set(theme.Dark)
+		default:
+			theme.Set(theme.Light)
+		}
+	}
+	dark.OnCheck = setTheme
+	contrast.OnCheck = setTheme
+	subPanel.Add(dark)
+	subPanel.Add(contrast)
+
 	w.Show()
 	w.Run()
 }
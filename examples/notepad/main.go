@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+
 	"github.com/jacksalad/goui_v0/component"
+	"github.com/jacksalad/goui_v0/dialog"
 	"github.com/jacksalad/goui_v0/layout"
 	"github.com/jacksalad/goui_v0/render"
 	"github.com/jacksalad/goui_v0/window"
@@ -43,17 +46,19 @@ func main() {
 	// Toolbar Buttons
 	btnNew := component.NewButton("Clear")
 	btnNew.Font = uiFont
+	btnNew.Tooltip = "Discard the current text (Ctrl+N)"
 	toolbar.Add(btnNew)
 
 	btnInfo := component.NewButton("About")
 	btnInfo.Font = uiFont
+	btnInfo.Tooltip = "About Simple Notepad"
 	toolbar.Add(btnInfo)
 
 	// Editor Area
 	// We want it to fill the rest of the window.
 	// Since VBoxLayout stacks, we just need to size it correctly.
-	// Window Height (450) - Toolbar Height (40) = 410.
-	editor := component.NewTextArea(600, 410)
+	// Window Height (450) - Menu Bar (~26) - Toolbar Height (40) = ~384.
+	editor := component.NewTextArea(600, 384)
 	editor.Font = editorFont
 	editor.Text = "Welcome to Simple Notepad!\nStart typing here..."
 
@@ -61,15 +66,87 @@ func main() {
 
 	// Wire up events
 	btnNew.OnClick = func() {
-		editor.Text = ""
-		editor.RequestRepaint()
-		win.SetFocus(editor)
+		dialog.Confirm("Clear", "Discard the current text?", func(ok bool) {
+			if ok {
+				editor.Text = ""
+				editor.RequestRepaint()
+				win.SetFocus(editor)
+			}
+		})
+	}
+
+	textFiles := []dialog.FileFilter{{Name: "Text files", Extensions: []string{".txt"}}, dialog.AllFiles}
+	openFile := func(*component.MenuItem) {
+		dialog.OpenFile("Open", textFiles, func(path string) {
+			if path == "" {
+				return
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				dialog.Message("Open", err.Error(), nil)
+				return
+			}
+			editor.SetText(string(data))
+			win.SetFocus(editor)
+		})
+	}
+	saveFile := func(*component.MenuItem) {
+		dialog.SaveFile("Save As", "Untitled.txt", textFiles, func(path string) {
+			if path == "" {
+				return
+			}
+			if err := os.WriteFile(path, []byte(editor.Text), 0644); err != nil {
+				win.Toasts.Show(&component.Toast{Kind: component.ToastError, Title: "Could not save", Message: err.Error()})
+				return
+			}
+			win.Toasts.Success("Saved " + path)
+		})
 	}
 
 	btnInfo.OnClick = func() {
-		editor.Text += "\n\n[About]\nSimple Notepad built with GoUI.\nSupports basic editing."
+		dialog.Message("About", "Simple Notepad built with GoUI.\nSupports basic editing.", nil)
+	}
+
+	// Menu Bar
+	setFontSize := func(item *component.MenuItem) {
+		size := map[string]int{"&Small": 13, "&Medium": 16, "&Large": 20}[item.Text]
+		editor.Font = render.NewFont("Consolas", size)
 		editor.RequestRepaint()
 	}
+	fontSizes := component.NewMenu(
+		&component.MenuItem{Text: "&Small", RadioGroup: "size", OnClick: setFontSize},
+		&component.MenuItem{Text: "&Medium", RadioGroup: "size", Checked: true, OnClick: setFontSize},
+		&component.MenuItem{Text: "&Large", RadioGroup: "size", OnClick: setFontSize},
+	)
+
+	menuBar := component.NewMenuBar()
+	menuBar.Font = uiFont
+	menuBar.Add("&File", component.NewMenu(
+		&component.MenuItem{Text: "&New", Accelerator: "Ctrl+N", OnClick: func(*component.MenuItem) { btnNew.OnClick() }},
+		&component.MenuItem{Text: "&Open...", Accelerator: "Ctrl+O", OnClick: openFile},
+		&component.MenuItem{Text: "Save &As...", Accelerator: "Ctrl+S", OnClick: saveFile},
+		component.NewMenuSeparator(),
+		&component.MenuItem{Text: "E&xit", Accelerator: "Alt+F4", OnClick: func(*component.MenuItem) { win.Close() }},
+	))
+	menuBar.Add("&View", component.NewMenu(
+		component.NewSubmenuItem("&Font Size", fontSizes),
+		&component.MenuItem{Text: "&Toolbar", Checkable: true, Checked: true, OnClick: func(item *component.MenuItem) {
+			toolbar.SetVisible(item.Checked)
+			win.Root.LayoutChildren()
+		}},
+	))
+	menuBar.Add("&Help", component.NewMenu(
+		component.NewMenuItem("&About", func(*component.MenuItem) { btnInfo.OnClick() }),
+	))
+	win.SetMenuBar(menuBar)
+
+	// Context menu of the editor
+	editor.ContextMenu = component.NewMenu(
+		component.NewMenuItem("&Clear", func(*component.MenuItem) { btnNew.OnClick() }),
+		component.NewMenuSeparator(),
+		component.NewSubmenuItem("&Font Size", fontSizes),
+	)
+	editor.ContextMenu.Font = uiFont
 
 	win.Show()
 	win.Run()
@@ -56,28 +56,26 @@ func main() {
 	font := render.NewFont("Segoe UI", 14)
 	// titleFont := render.NewFont("Segoe UI", 20)
 
-	// Root Layout
+	// Root Layout: two equal columns, cards sized by the grid
 	win.Root.BgColor = 0xFFF5F5F5 // Light gray background
-	win.Root.SetLayout(&layout.HBoxLayout{
-		Padding: 20,
-		Spacing: 20,
-	})
-
-	// Left Column (Stats)
-	leftCol := component.NewPanel(0, 0, 370, 460)
-	leftCol.BgColor = 0xFFF5F5F5
-	leftCol.SetLayout(&layout.VBoxLayout{Spacing: 20})
-	win.Root.Add(leftCol)
-
-	// Right Column (More Stats or Logs)
-	rightCol := component.NewPanel(0, 0, 370, 460)
-	rightCol.BgColor = 0xFFF5F5F5
-	rightCol.SetLayout(&layout.VBoxLayout{Spacing: 20})
-	win.Root.Add(rightCol)
+	grid := layout.NewTrackGridLayout(
+		[]layout.Track{layout.Fr(1), layout.Fr(1)},
+		[]layout.Track{layout.Fr(1), layout.Fr(1)},
+	)
+	grid.Padding = 20
+	grid.Spacing = 20
+	if err := grid.SetAreas(
+		"cpu net",
+		"mem go",
+	); err != nil {
+		panic(err)
+	}
+	win.Root.SetLayout(grid)
 
 	// CPU Card
 	cpuCard := component.NewCard(370, 200, "CPU Usage")
-	leftCol.Add(cpuCard)
+	grid.SetArea(cpuCard, "cpu")
+	win.Root.Add(cpuCard)
 
 	cpuText := component.NewButton("0%")
 	cpuText.Font = font
@@ -94,7 +92,8 @@ func main() {
 
 	// Memory Card
 	memCard := component.NewCard(370, 200, "Memory Usage")
-	leftCol.Add(memCard)
+	grid.SetArea(memCard, "mem")
+	win.Root.Add(memCard)
 
 	memText := component.NewButton("0 MB")
 	memText.Font = font
@@ -111,7 +110,8 @@ func main() {
 
 	// Network Card (Simulated)
 	netCard := component.NewCard(370, 200, "Network Speed")
-	rightCol.Add(netCard)
+	grid.SetArea(netCard, "net")
+	win.Root.Add(netCard)
 
 	netText := component.NewButton("Dl: 0 KB/s  Ul: 0 KB/s")
 	netText.Font = font
@@ -124,7 +124,9 @@ func main() {
 
 	// Goroutine Card
 	goCard := component.NewCard(370, 100, "Goroutines")
-	rightCol.Add(goCard)
+	grid.SetArea(goCard, "go")
+	grid.SetAlign(goCard, layout.AlignStretch, layout.AlignStart)
+	win.Root.Add(goCard)
 
 	goText := component.NewButton("0")
 	goText.Font = font
@@ -8,11 +8,25 @@ import (
 	"github.com/jacksalad/goui_v0/window"
 )
 
+type todoItem struct {
+	text string
+	done bool
+}
+
+// todoRow is the recycled row component of the list
+type todoRow struct {
+	*component.Panel
+	chk    *component.CheckBox
+	delBtn *component.Button
+	index  int
+}
+
 type TodoApp struct {
-	window    *window.Window
-	input     *component.TextBox
-	listPanel *component.Panel
-	
+	window *window.Window
+	input  *component.TextBox
+	list   *component.ListView
+	items  []todoItem
+
 	// Fonts
 	titleFont *render.Font
 	itemFont  *render.Font
@@ -78,64 +92,71 @@ func (app *TodoApp) setupUI() {
 
 	app.window.Root.Add(inputPanel)
 
-	// Todo List Container
-	app.listPanel = component.NewPanel(0, 0, 360, 400)
-	listLayout := &layout.VBoxLayout{
-		Padding: 10,
-		Spacing: 5,
-	}
-	app.listPanel.SetLayout(listLayout)
-	app.listPanel.BgColor = 0xFFFFFFFF // White background for list
-	
-	app.window.Root.Add(app.listPanel)
+	// Todo List: only the visible rows exist, however many tasks there are
+	app.list = component.NewListView(360, 400, app)
+	app.list.RowHeight = 45
+	app.list.SelectionMode = component.SelectionNone
+
+	app.window.Root.Add(app.list)
 }
 
-func (app *TodoApp) addTodo() {
-	text := app.input.Text
-	if text == "" {
-		return
-	}
+// ListDataSource implementation
+
+func (app *TodoApp) Count() int {
+	return len(app.items)
+}
 
-	// Create Todo Item Panel (HBox)
+func (app *TodoApp) CreateRow() component.Component {
 	// Using a panel to group checkbox and delete button
-	itemPanel := component.NewPanel(0, 0, 340, 40)
-	itemLayout := &layout.HBoxLayout{
+	row := &todoRow{Panel: component.NewPanel(0, 0, 340, 40)}
+	row.SetLayout(&layout.HBoxLayout{
 		Padding: 5,
 		Spacing: 10,
-	}
-	itemPanel.SetLayout(itemLayout)
-	itemPanel.BgColor = 0xFFF0F8FF // Light Alice Blue
+	})
+	row.BgColor = 0xFFF0F8FF // Light Alice Blue
 
 	// Checkbox
-	chk := component.NewCheckBox(text)
-	chk.Font = app.itemFont
-	chk.OnCheck = func(checked bool) {
-		fmt.Printf("Task '%s' checked: %v\n", text, checked)
+	row.chk = component.NewCheckBox("")
+	row.chk.Font = app.itemFont
+	row.chk.OnCheck = func(checked bool) {
+		app.items[row.index].done = checked
+		fmt.Printf("Task '%s' checked: %v\n", app.items[row.index].text, checked)
 	}
-	
+
 	// Delete Button
-	delBtn := component.NewButton("X")
-	delBtn.Font = app.itemFont
-	// Small red button? Button doesn't support color yet, but we can imagine
-	delBtn.OnClick = func() {
-		app.listPanel.Remove(itemPanel)
-		// Force repaint
-		app.window.Root.RequestRepaint()
-		// Ideally layout should update automatically
+	row.delBtn = component.NewButton("X")
+	row.delBtn.Font = app.itemFont
+	row.delBtn.OnClick = func() {
+		app.items = append(app.items[:row.index], app.items[row.index+1:]...)
+		app.list.Reload()
+	}
+
+	row.Add(row.chk)
+	row.Add(row.delBtn)
+	return row
+}
+
+func (app *TodoApp) BindRow(c component.Component, index int, selected bool) {
+	row := c.(*todoRow)
+	row.index = index
+	row.chk.Text = app.items[index].text
+	row.chk.Checked = app.items[index].done
+	row.LayoutChildren() // Text width changed
+}
+
+func (app *TodoApp) addTodo() {
+	text := app.input.Text
+	if text == "" {
+		return
 	}
 
-	itemPanel.Add(chk)
-	itemPanel.Add(delBtn)
+	app.items = append(app.items, todoItem{text: text})
+	app.list.Reload()
+	app.list.ScrollToIndex(len(app.items) - 1)
 
-	app.listPanel.Add(itemPanel)
-	
 	// Clear input
 	app.input.Text = ""
 	app.input.RequestRepaint()
-	
-	// Force repaint of list
-	app.listPanel.RequestRepaint()
-	app.window.Root.RequestRepaint()
 }
 
 func main() {
@@ -2,4 +2,7 @@ module github.com/jacksalad/goui_v0
 
 go 1.25.0
 
-require golang.org/x/sys v0.41.0 // indirect
+require (
+	golang.org/x/image v0.25.0
+	golang.org/x/sys v0.41.0 // indirect
+)
@@ -0,0 +1,134 @@
+package layout
+
+// Anchor is a bitmask of container edges a child is attached to.
+type Anchor int
+
+const (
+	AnchorLeft Anchor = 1 << iota
+	AnchorTop
+	AnchorRight
+	AnchorBottom
+
+	AnchorNone Anchor = 0
+	AnchorAll         = AnchorLeft | AnchorTop | AnchorRight | AnchorBottom
+)
+
+// AnchorProps describe how a child is attached to its container, like
+// WinForms Anchor. Each edge distance is Pixels + Percent * container
+// size, so percentage positioning is AnchorLeft with LeftPct = 0.25.
+//
+// On an axis anchored to both edges the child stretches; anchored to one
+// edge it keeps its size (Width/Height, or the preferred size when 0) at
+// the given distance; anchored to neither it is centered, offset by
+// Left/Top. The child's margins are kept inside the anchored box.
+type AnchorProps struct {
+	Anchors Anchor
+
+	Left, Top, Right, Bottom             int32
+	LeftPct, TopPct, RightPct, BottomPct float64
+
+	Width, Height       int32
+	WidthPct, HeightPct float64
+}
+
+// AnchorLayout positions children relative to the edges of the container.
+// Children without properties keep their current bounds, which makes the
+// layout usable for absolute positioning as well.
+type AnchorLayout struct {
+	Padding int32
+
+	props map[Component]AnchorProps
+}
+
+func NewAnchorLayout() *AnchorLayout {
+	return &AnchorLayout{
+		props: make(map[Component]AnchorProps),
+	}
+}
+
+func (l *AnchorLayout) SetProps(c Component, p AnchorProps) {
+	if l.props == nil {
+		l.props = make(map[Component]AnchorProps)
+	}
+	l.props[c] = p
+}
+
+// SetAnchors attaches c to the given edges at fixed pixel distances.
+func (l *AnchorLayout) SetAnchors(c Component, anchors Anchor, left, top, right, bottom int32) {
+	p := l.props[c]
+	p.Anchors = anchors
+	p.Left, p.Top, p.Right, p.Bottom = left, top, right, bottom
+	l.SetProps(c, p)
+}
+
+// SetPosition places c at (x, y) relative to the container's top-left
+// corner with its preferred size.
+func (l *AnchorLayout) SetPosition(c Component, x, y int32) {
+	l.SetAnchors(c, AnchorLeft|AnchorTop, x, y, 0, 0)
+}
+
+// SetRelative places c at fractions of the container size, e.g.
+// SetRelative(c, 0.25, 0.25, 0.5, 0.5) centers it at half size.
+func (l *AnchorLayout) SetRelative(c Component, x, y, w, h float64) {
+	l.SetProps(c, AnchorProps{
+		Anchors:   AnchorLeft | AnchorTop,
+		LeftPct:   x,
+		TopPct:    y,
+		WidthPct:  w,
+		HeightPct: h,
+	})
+}
+
+func (l *AnchorLayout) Arrange(container Container) {
+	content := contentRect(container, l.Padding)
+	width, height := content.Width, content.Height
+
+	for _, child := range container.GetChildren() {
+		if !child.IsVisible() {
+			continue
+		}
+		p, ok := l.props[child]
+		if !ok {
+			continue
+		}
+
+		hints := HintsOf(child)
+		prefW, prefH := outerSize(child, hints)
+
+		x, w := anchorAxis(width, prefW,
+			p.Anchors&AnchorLeft != 0, p.Anchors&AnchorRight != 0,
+			edge(p.Left, p.LeftPct, width), edge(p.Right, p.RightPct, width),
+			edge(p.Width, p.WidthPct, width))
+		y, h := anchorAxis(height, prefH,
+			p.Anchors&AnchorTop != 0, p.Anchors&AnchorBottom != 0,
+			edge(p.Top, p.TopPct, height), edge(p.Bottom, p.BottomPct, height),
+			edge(p.Height, p.HeightPct, height))
+
+		slot := Rect{X: content.X + x, Y: content.Y + y, Width: w, Height: h}
+		placeInSlot(child, hints, slot, pickAlign(hints.HAlign, AlignStretch), pickAlign(hints.VAlign, AlignStretch))
+	}
+}
+
+func edge(px int32, pct float64, size int32) int32 {
+	return px + int32(pct*float64(size))
+}
+
+// anchorAxis resolves position and length on one axis.
+func anchorAxis(avail, pref int32, near, far bool, nearDist, farDist, size int32) (int32, int32) {
+	if size <= 0 {
+		size = pref
+	}
+	switch {
+	case near && far:
+		size = avail - nearDist - farDist
+		if size < 0 {
+			size = 0
+		}
+		return nearDist, size
+	case near:
+		return nearDist, size
+	case far:
+		return avail - farDist - size, size
+	}
+	return (avail-size)/2 + nearDist, size
+}
@@ -0,0 +1,811 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// This file implements an incremental Cassowary linear constraint solver,
+// following the design of the Kiwi solver. It is used by ConstraintLayout
+// but can be driven directly for custom layouts.
+
+var (
+	ErrUnsatisfiableConstraint = errors.New("layout: unsatisfiable constraint")
+	ErrDuplicateConstraint     = errors.New("layout: duplicate constraint")
+	ErrUnknownConstraint       = errors.New("layout: unknown constraint")
+	ErrDuplicateEditVariable   = errors.New("layout: duplicate edit variable")
+	ErrUnknownEditVariable     = errors.New("layout: unknown edit variable")
+	ErrBadRequiredStrength     = errors.New("layout: edit variable cannot be required")
+	ErrInternalSolver          = errors.New("layout: internal solver error")
+)
+
+// Strength is the priority of a constraint. Required constraints must be
+// satisfied; the others are satisfied as well as possible, a stronger
+// constraint always winning over any number of weaker ones.
+type Strength float64
+
+// NewStrength combines strong, medium and weak components (each 0-1000)
+// scaled by weight.
+func NewStrength(strong, medium, weak, weight float64) Strength {
+	clip := func(v float64) float64 { return math.Max(0, math.Min(1000, v)) }
+	return Strength(clip(strong*weight)*1e6 + clip(medium*weight)*1e3 + clip(weak*weight))
+}
+
+var (
+	Required = NewStrength(1000, 1000, 1000, 1)
+	Strong   = NewStrength(1, 0, 0, 1)
+	Medium   = NewStrength(0, 1, 0, 1)
+	Weak     = NewStrength(0, 0, 1, 1)
+)
+
+func (s Strength) clip() Strength {
+	return Strength(math.Max(0, math.Min(float64(Required), float64(s))))
+}
+
+// Variable is an unknown solved for by the Solver.
+type Variable struct {
+	Name  string
+	value float64
+}
+
+func NewVariable(name string) *Variable {
+	return &Variable{Name: name}
+}
+
+// Value returns the value computed by the last Solver.UpdateVariables.
+func (v *Variable) Value() float64 {
+	return v.value
+}
+
+func (v *Variable) Expr() Expression {
+	return Expression{Terms: []Term{{Var: v, Coefficient: 1}}}
+}
+
+func (v *Variable) Plus(o Linear) Expression  { return v.Expr().Plus(o) }
+func (v *Variable) Minus(o Linear) Expression { return v.Expr().Minus(o) }
+func (v *Variable) Scale(f float64) Expression {
+	return v.Expr().Scale(f)
+}
+
+// Linear is anything usable as a linear expression:
+// *Variable, Expression or Const.
+type Linear interface {
+	Expr() Expression
+}
+
+// Const is a constant linear expression.
+type Const float64
+
+func (c Const) Expr() Expression {
+	return Expression{Constant: float64(c)}
+}
+
+type Term struct {
+	Var         *Variable
+	Coefficient float64
+}
+
+// Expression is sum(Terms) + Constant.
+type Expression struct {
+	Terms    []Term
+	Constant float64
+}
+
+func (e Expression) Expr() Expression {
+	return e
+}
+
+func (e Expression) Plus(o Linear) Expression {
+	oe := o.Expr()
+	terms := make([]Term, 0, len(e.Terms)+len(oe.Terms))
+	terms = append(terms, e.Terms...)
+	terms = append(terms, oe.Terms...)
+	return Expression{Terms: terms, Constant: e.Constant + oe.Constant}
+}
+
+func (e Expression) Minus(o Linear) Expression {
+	return e.Plus(o.Expr().Scale(-1))
+}
+
+func (e Expression) Scale(f float64) Expression {
+	terms := make([]Term, len(e.Terms))
+	for i, t := range e.Terms {
+		terms[i] = Term{Var: t.Var, Coefficient: t.Coefficient * f}
+	}
+	return Expression{Terms: terms, Constant: e.Constant * f}
+}
+
+func (e Expression) String() string {
+	var sb strings.Builder
+	for i, t := range e.Terms {
+		name := t.Var.Name
+		if name == "" {
+			name = "?"
+		}
+		coef := t.Coefficient
+		if i > 0 {
+			if coef < 0 {
+				sb.WriteString(" - ")
+				coef = -coef
+			} else {
+				sb.WriteString(" + ")
+			}
+		}
+		if coef != 1 {
+			sb.WriteString(strconv.FormatFloat(coef, 'g', -1, 64) + "*")
+		}
+		sb.WriteString(name)
+	}
+	switch {
+	case len(e.Terms) == 0:
+		sb.WriteString(strconv.FormatFloat(e.Constant, 'g', -1, 64))
+	case e.Constant < 0:
+		sb.WriteString(" - " + strconv.FormatFloat(-e.Constant, 'g', -1, 64))
+	case e.Constant > 0:
+		sb.WriteString(" + " + strconv.FormatFloat(e.Constant, 'g', -1, 64))
+	}
+	return sb.String()
+}
+
+type RelationalOperator int
+
+const (
+	OpEq RelationalOperator = iota
+	OpLe
+	OpGe
+)
+
+func (op RelationalOperator) String() string {
+	switch op {
+	case OpLe:
+		return "<="
+	case OpGe:
+		return ">="
+	}
+	return "=="
+}
+
+// Constraint is the relation Expression Op 0 at a given strength.
+// Constraints are compared by identity.
+type Constraint struct {
+	Expression Expression
+	Op         RelationalOperator
+	Strength   Strength
+}
+
+// NewConstraint creates lhs op rhs.
+func NewConstraint(lhs Linear, op RelationalOperator, rhs Linear, strength Strength) *Constraint {
+	return &Constraint{
+		Expression: lhs.Expr().Minus(rhs),
+		Op:         op,
+		Strength:   strength.clip(),
+	}
+}
+
+// Eq returns the required constraint lhs == rhs.
+func Eq(lhs, rhs Linear) *Constraint { return NewConstraint(lhs, OpEq, rhs, Required) }
+
+// Le returns the required constraint lhs <= rhs.
+func Le(lhs, rhs Linear) *Constraint { return NewConstraint(lhs, OpLe, rhs, Required) }
+
+// Ge returns the required constraint lhs >= rhs.
+func Ge(lhs, rhs Linear) *Constraint { return NewConstraint(lhs, OpGe, rhs, Required) }
+
+// WithStrength changes the strength of c before it is added to a solver.
+func (c *Constraint) WithStrength(s Strength) *Constraint {
+	c.Strength = s.clip()
+	return c
+}
+
+func (c *Constraint) String() string {
+	return fmt.Sprintf("%s %s 0", c.Expression, c.Op)
+}
+
+type symbolKind int
+
+const (
+	symInvalid symbolKind = iota
+	symExternal
+	symSlack
+	symError
+	symDummy
+)
+
+type symbol struct {
+	id   uint64
+	kind symbolKind
+}
+
+func (s symbol) valid() bool { return s.kind != symInvalid }
+
+// less orders symbols by creation, which keeps pivoting deterministic
+// (Bland's rule) despite map iteration order.
+func (s symbol) less(o symbol) bool { return s.id < o.id }
+
+const epsilon = 1e-8
+
+func nearZero(v float64) bool {
+	return math.Abs(v) < epsilon
+}
+
+type tableauRow struct {
+	constant float64
+	cells    map[symbol]float64
+}
+
+func newRow(constant float64) *tableauRow {
+	return &tableauRow{constant: constant, cells: make(map[symbol]float64)}
+}
+
+func (r *tableauRow) copy() *tableauRow {
+	n := newRow(r.constant)
+	for s, c := range r.cells {
+		n.cells[s] = c
+	}
+	return n
+}
+
+func (r *tableauRow) add(v float64) float64 {
+	r.constant += v
+	return r.constant
+}
+
+func (r *tableauRow) insertSymbol(s symbol, coef float64) {
+	v := r.cells[s] + coef
+	if nearZero(v) {
+		delete(r.cells, s)
+	} else {
+		r.cells[s] = v
+	}
+}
+
+func (r *tableauRow) insertRow(o *tableauRow, coef float64) {
+	r.constant += o.constant * coef
+	for s, c := range o.cells {
+		r.insertSymbol(s, c*coef)
+	}
+}
+
+func (r *tableauRow) reverseSign() {
+	r.constant = -r.constant
+	for s, c := range r.cells {
+		r.cells[s] = -c
+	}
+}
+
+// solveFor rewrites the row so that s is its subject.
+func (r *tableauRow) solveFor(s symbol) {
+	coef := -1 / r.cells[s]
+	delete(r.cells, s)
+	r.constant *= coef
+	for k, c := range r.cells {
+		r.cells[k] = c * coef
+	}
+}
+
+func (r *tableauRow) solveForEx(lhs, rhs symbol) {
+	r.insertSymbol(lhs, -1)
+	r.solveFor(rhs)
+}
+
+func (r *tableauRow) coefficientFor(s symbol) float64 {
+	return r.cells[s]
+}
+
+func (r *tableauRow) substitute(s symbol, o *tableauRow) {
+	if c, ok := r.cells[s]; ok {
+		delete(r.cells, s)
+		r.insertRow(o, c)
+	}
+}
+
+type constraintTag struct {
+	marker symbol
+	other  symbol
+}
+
+type editInfo struct {
+	tag        constraintTag
+	constraint *Constraint
+	constant   float64
+}
+
+// Solver is an incremental Cassowary constraint solver.
+type Solver struct {
+	constraints map[*Constraint]constraintTag
+	order       []*Constraint
+	rows        map[symbol]*tableauRow
+	vars        map[*Variable]symbol
+	edits       map[*Variable]*editInfo
+	infeasible  []symbol
+	objective   *tableauRow
+	artificial  *tableauRow
+	nextID      uint64
+}
+
+func NewSolver() *Solver {
+	return &Solver{
+		constraints: make(map[*Constraint]constraintTag),
+		rows:        make(map[symbol]*tableauRow),
+		vars:        make(map[*Variable]symbol),
+		edits:       make(map[*Variable]*editInfo),
+		objective:   newRow(0),
+	}
+}
+
+func (s *Solver) newSymbol(kind symbolKind) symbol {
+	s.nextID++
+	return symbol{id: s.nextID, kind: kind}
+}
+
+// HasConstraint reports whether c was added to the solver.
+func (s *Solver) HasConstraint(c *Constraint) bool {
+	_, ok := s.constraints[c]
+	return ok
+}
+
+// AddConstraint adds c and re-optimizes. An unsatisfiable required
+// constraint is rejected with ErrUnsatisfiableConstraint and leaves the
+// solver unchanged.
+func (s *Solver) AddConstraint(c *Constraint) error {
+	if _, ok := s.constraints[c]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateConstraint, c)
+	}
+
+	row, tag := s.createRow(c)
+	subject := chooseSubject(row, tag)
+
+	if !subject.valid() && allDummies(row) {
+		if !nearZero(row.constant) {
+			s.rebuild()
+			return fmt.Errorf("%w: %s", ErrUnsatisfiableConstraint, c)
+		}
+		subject = tag.marker
+	}
+
+	if !subject.valid() {
+		ok, err := s.addWithArtificialVariable(row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.rebuild()
+			return fmt.Errorf("%w: %s", ErrUnsatisfiableConstraint, c)
+		}
+	} else {
+		row.solveFor(subject)
+		s.substitute(subject, row)
+		s.rows[subject] = row
+	}
+
+	s.constraints[c] = tag
+	s.order = append(s.order, c)
+	return s.optimize(s.objective)
+}
+
+// rebuild recreates the tableau from the accepted constraints. It is used
+// to roll back after an unsatisfiable constraint, whose artificial-variable
+// pivots may already have touched the tableau.
+func (s *Solver) rebuild() {
+	order := s.order
+	edits := s.edits
+
+	s.constraints = make(map[*Constraint]constraintTag)
+	s.rows = make(map[symbol]*tableauRow)
+	s.vars = make(map[*Variable]symbol)
+	s.edits = make(map[*Variable]*editInfo)
+	s.infeasible = nil
+	s.objective = newRow(0)
+	s.artificial = nil
+	s.order = nil
+
+	for _, c := range order {
+		s.AddConstraint(c)
+	}
+	for v, info := range edits {
+		s.edits[v] = &editInfo{tag: s.constraints[info.constraint], constraint: info.constraint}
+		s.SuggestValue(v, info.constant)
+	}
+}
+
+// RemoveConstraint removes a previously added constraint.
+func (s *Solver) RemoveConstraint(c *Constraint) error {
+	tag, ok := s.constraints[c]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownConstraint, c)
+	}
+	delete(s.constraints, c)
+	for i, o := range s.order {
+		if o == c {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	// Remove the error effects from the objective
+	if tag.marker.kind == symError {
+		s.removeMarkerEffects(tag.marker, c.Strength)
+	}
+	if tag.other.kind == symError {
+		s.removeMarkerEffects(tag.other, c.Strength)
+	}
+
+	if _, ok := s.rows[tag.marker]; ok {
+		delete(s.rows, tag.marker)
+	} else {
+		leaving := s.markerLeavingRow(tag.marker)
+		if !leaving.valid() {
+			return ErrInternalSolver
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForEx(leaving, tag.marker)
+		s.substitute(tag.marker, row)
+	}
+
+	return s.optimize(s.objective)
+}
+
+func (s *Solver) removeMarkerEffects(marker symbol, strength Strength) {
+	if row, ok := s.rows[marker]; ok {
+		s.objective.insertRow(row, -float64(strength))
+	} else {
+		s.objective.insertSymbol(marker, -float64(strength))
+	}
+}
+
+// AddEditVariable makes v suggestible through SuggestValue.
+func (s *Solver) AddEditVariable(v *Variable, strength Strength) error {
+	if _, ok := s.edits[v]; ok {
+		return ErrDuplicateEditVariable
+	}
+	strength = strength.clip()
+	if strength == Required {
+		return ErrBadRequiredStrength
+	}
+	c := NewConstraint(v, OpEq, Const(0), strength)
+	if err := s.AddConstraint(c); err != nil {
+		return err
+	}
+	s.edits[v] = &editInfo{tag: s.constraints[c], constraint: c}
+	return nil
+}
+
+func (s *Solver) RemoveEditVariable(v *Variable) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+	delete(s.edits, v)
+	return s.RemoveConstraint(info.constraint)
+}
+
+func (s *Solver) HasEditVariable(v *Variable) bool {
+	_, ok := s.edits[v]
+	return ok
+}
+
+// SuggestValue suggests a value for an edit variable. The tableau is
+// updated incrementally with the dual simplex method.
+func (s *Solver) SuggestValue(v *Variable, value float64) error {
+	info, ok := s.edits[v]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+
+	delta := value - info.constant
+	info.constant = value
+
+	if row, ok := s.rows[info.tag.marker]; ok {
+		if row.add(-delta) < 0 {
+			s.infeasible = append(s.infeasible, info.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+	if row, ok := s.rows[info.tag.other]; ok {
+		if row.add(delta) < 0 {
+			s.infeasible = append(s.infeasible, info.tag.other)
+		}
+		return s.dualOptimize()
+	}
+	for sym, row := range s.rows {
+		coef := row.coefficientFor(info.tag.marker)
+		if coef != 0 && row.add(delta*coef) < 0 && sym.kind != symExternal {
+			s.infeasible = append(s.infeasible, sym)
+		}
+	}
+	return s.dualOptimize()
+}
+
+// UpdateVariables copies the solution into every known Variable.
+func (s *Solver) UpdateVariables() {
+	for v, sym := range s.vars {
+		if row, ok := s.rows[sym]; ok {
+			v.value = row.constant
+		} else {
+			v.value = 0
+		}
+	}
+}
+
+func (s *Solver) varSymbol(v *Variable) symbol {
+	if sym, ok := s.vars[v]; ok {
+		return sym
+	}
+	sym := s.newSymbol(symExternal)
+	s.vars[v] = sym
+	return sym
+}
+
+func (s *Solver) createRow(c *Constraint) (*tableauRow, constraintTag) {
+	expr := c.Expression
+	row := newRow(expr.Constant)
+
+	// Substitute the current basic variables into the row
+	for _, t := range expr.Terms {
+		if nearZero(t.Coefficient) {
+			continue
+		}
+		sym := s.varSymbol(t.Var)
+		if basic, ok := s.rows[sym]; ok {
+			row.insertRow(basic, t.Coefficient)
+		} else {
+			row.insertSymbol(sym, t.Coefficient)
+		}
+	}
+
+	var tag constraintTag
+	strength := float64(c.Strength)
+	switch c.Op {
+	case OpLe, OpGe:
+		coef := 1.0
+		if c.Op == OpGe {
+			coef = -1.0
+		}
+		slack := s.newSymbol(symSlack)
+		tag.marker = slack
+		row.insertSymbol(slack, coef)
+		if c.Strength < Required {
+			errSym := s.newSymbol(symError)
+			tag.other = errSym
+			row.insertSymbol(errSym, -coef)
+			s.objective.insertSymbol(errSym, strength)
+		}
+	case OpEq:
+		if c.Strength < Required {
+			errPlus := s.newSymbol(symError)
+			errMinus := s.newSymbol(symError)
+			tag.marker = errPlus
+			tag.other = errMinus
+			row.insertSymbol(errPlus, -1)
+			row.insertSymbol(errMinus, 1)
+			s.objective.insertSymbol(errPlus, strength)
+			s.objective.insertSymbol(errMinus, strength)
+		} else {
+			dummy := s.newSymbol(symDummy)
+			tag.marker = dummy
+			row.insertSymbol(dummy, 1)
+		}
+	}
+
+	// The row constant must be non-negative
+	if row.constant < 0 {
+		row.reverseSign()
+	}
+	return row, tag
+}
+
+func chooseSubject(row *tableauRow, tag constraintTag) symbol {
+	var best symbol
+	for sym := range row.cells {
+		if sym.kind == symExternal && (!best.valid() || sym.less(best)) {
+			best = sym
+		}
+	}
+	if best.valid() {
+		return best
+	}
+	if tag.marker.kind == symSlack || tag.marker.kind == symError {
+		if row.coefficientFor(tag.marker) < 0 {
+			return tag.marker
+		}
+	}
+	if tag.other.kind == symSlack || tag.other.kind == symError {
+		if row.coefficientFor(tag.other) < 0 {
+			return tag.other
+		}
+	}
+	return symbol{}
+}
+
+func allDummies(row *tableauRow) bool {
+	for sym := range row.cells {
+		if sym.kind != symDummy {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Solver) addWithArtificialVariable(row *tableauRow) (bool, error) {
+	art := s.newSymbol(symSlack)
+	s.rows[art] = row.copy()
+	s.artificial = row.copy()
+
+	if err := s.optimize(s.artificial); err != nil {
+		return false, err
+	}
+	success := nearZero(s.artificial.constant)
+	s.artificial = nil
+
+	// If the artificial variable is basic, pivot it out of the basis
+	if r, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+		if len(r.cells) == 0 {
+			return success, nil
+		}
+		entering := anyPivotableSymbol(r)
+		if !entering.valid() {
+			return false, nil
+		}
+		r.solveForEx(art, entering)
+		s.substitute(entering, r)
+		s.rows[entering] = r
+	}
+
+	for _, r := range s.rows {
+		delete(r.cells, art)
+	}
+	delete(s.objective.cells, art)
+	return success, nil
+}
+
+func anyPivotableSymbol(row *tableauRow) symbol {
+	var best symbol
+	for sym := range row.cells {
+		if (sym.kind == symSlack || sym.kind == symError) && (!best.valid() || sym.less(best)) {
+			best = sym
+		}
+	}
+	return best
+}
+
+func (s *Solver) substitute(sym symbol, row *tableauRow) {
+	for rowSym, r := range s.rows {
+		r.substitute(sym, row)
+		if rowSym.kind != symExternal && r.constant < 0 {
+			s.infeasible = append(s.infeasible, rowSym)
+		}
+	}
+	s.objective.substitute(sym, row)
+	if s.artificial != nil {
+		s.artificial.substitute(sym, row)
+	}
+}
+
+// optimize runs the primal simplex method on objective.
+func (s *Solver) optimize(objective *tableauRow) error {
+	for {
+		entering := enteringSymbol(objective)
+		if !entering.valid() {
+			return nil
+		}
+		leaving := s.leavingSymbol(entering)
+		if !leaving.valid() {
+			return fmt.Errorf("%w: objective is unbounded", ErrInternalSolver)
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForEx(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+}
+
+// dualOptimize restores feasibility after edit suggestions.
+func (s *Solver) dualOptimize() error {
+	for len(s.infeasible) > 0 {
+		leaving := s.infeasible[len(s.infeasible)-1]
+		s.infeasible = s.infeasible[:len(s.infeasible)-1]
+
+		row, ok := s.rows[leaving]
+		if !ok || nearZero(row.constant) || row.constant >= 0 {
+			continue
+		}
+		entering := s.dualEnteringSymbol(row)
+		if !entering.valid() {
+			return fmt.Errorf("%w: dual optimize failed", ErrInternalSolver)
+		}
+		delete(s.rows, leaving)
+		row.solveForEx(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+	return nil
+}
+
+func enteringSymbol(objective *tableauRow) symbol {
+	var best symbol
+	for sym, c := range objective.cells {
+		if sym.kind != symDummy && c < 0 && (!best.valid() || sym.less(best)) {
+			best = sym
+		}
+	}
+	return best
+}
+
+func (s *Solver) dualEnteringSymbol(row *tableauRow) symbol {
+	var best symbol
+	ratio := math.MaxFloat64
+	for sym, c := range row.cells {
+		if c <= 0 || sym.kind == symDummy {
+			continue
+		}
+		r := s.objective.coefficientFor(sym) / c
+		if r < ratio || (r == ratio && sym.less(best)) {
+			ratio = r
+			best = sym
+		}
+	}
+	return best
+}
+
+func (s *Solver) leavingSymbol(entering symbol) symbol {
+	var best symbol
+	ratio := math.MaxFloat64
+	for sym, row := range s.rows {
+		if sym.kind == symExternal {
+			continue
+		}
+		c := row.coefficientFor(entering)
+		if c >= 0 {
+			continue
+		}
+		r := -row.constant / c
+		if r < ratio || (r == ratio && sym.less(best)) {
+			ratio = r
+			best = sym
+		}
+	}
+	return best
+}
+
+// markerLeavingRow finds the row to pivot when removing a constraint
+// whose marker is not basic.
+func (s *Solver) markerLeavingRow(marker symbol) symbol {
+	r1, r2 := math.MaxFloat64, math.MaxFloat64
+	var first, second, third symbol
+	for sym, row := range s.rows {
+		c := row.coefficientFor(marker)
+		if c == 0 {
+			continue
+		}
+		if sym.kind == symExternal {
+			if !third.valid() || sym.less(third) {
+				third = sym
+			}
+		} else if c < 0 {
+			r := -row.constant / c
+			if r < r1 || (r == r1 && sym.less(first)) {
+				r1 = r
+				first = sym
+			}
+		} else {
+			r := row.constant / c
+			if r < r2 || (r == r2 && sym.less(second)) {
+				r2 = r
+				second = sym
+			}
+		}
+	}
+	if first.valid() {
+		return first
+	}
+	if second.valid() {
+		return second
+	}
+	return third
+}
@@ -0,0 +1,280 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConstraintItem holds the solver variables of one child. Coordinates are
+// relative to the container's content area (inside Padding) and describe
+// the child's margin box.
+type ConstraintItem struct {
+	Left, Top, Width, Height *Variable
+
+	comp     Component
+	hints    Hints
+	prefW    int32
+	prefH    int32
+	implicit []*Constraint
+	hidden   []*Constraint // Taken out of the solver while comp is hidden
+}
+
+// uses reports whether an expression involves the item's variables.
+func (i *ConstraintItem) uses(e Expression) bool {
+	for _, t := range e.Terms {
+		if t.Var == i.Left || t.Var == i.Top || t.Var == i.Width || t.Var == i.Height {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *ConstraintItem) Right() Expression   { return i.Left.Plus(i.Width) }
+func (i *ConstraintItem) Bottom() Expression  { return i.Top.Plus(i.Height) }
+func (i *ConstraintItem) CenterX() Expression { return i.Left.Plus(i.Width.Scale(0.5)) }
+func (i *ConstraintItem) CenterY() Expression { return i.Top.Plus(i.Height.Scale(0.5)) }
+
+// ConstraintLayout positions children by solving linear constraints
+// between their edges, e.g.
+//
+//	l.Add(layout.Eq(label.Right().Plus(layout.Const(8)), field.Left))
+//	l.Add(layout.Ge(field.Width, layout.Const(200)).WithStrength(layout.Strong))
+//
+// Every child gets weak constraints towards its preferred size. The
+// container size is fed in through strong edit variables, so resizing is
+// incremental.
+//
+// The constraints of a hidden child are set aside until it is shown
+// again, and those of a component no longer among the children are
+// removed when the container is arranged, as by RemoveItem.
+type ConstraintLayout struct {
+	Padding int32
+
+	// OnError is called by Arrange for each constraint of a child shown
+	// again that no longer fits, e.g. because one added meanwhile
+	// conflicts with it. The constraint stays aside and is tried again on
+	// the next Arrange.
+	OnError func(c *Constraint, err error)
+
+	solver *Solver
+	parent *ConstraintItem
+	items  map[Component]*ConstraintItem
+}
+
+func NewConstraintLayout() *ConstraintLayout {
+	l := &ConstraintLayout{
+		solver: NewSolver(),
+		items:  make(map[Component]*ConstraintItem),
+	}
+	l.parent = &ConstraintItem{
+		Left:   NewVariable("parent.left"),
+		Top:    NewVariable("parent.top"),
+		Width:  NewVariable("parent.width"),
+		Height: NewVariable("parent.height"),
+	}
+	l.solver.AddConstraint(Eq(l.parent.Left, Const(0)))
+	l.solver.AddConstraint(Eq(l.parent.Top, Const(0)))
+	l.solver.AddEditVariable(l.parent.Width, Strong)
+	l.solver.AddEditVariable(l.parent.Height, Strong)
+	return l
+}
+
+// Parent returns the item representing the container's content area.
+func (l *ConstraintLayout) Parent() *ConstraintItem {
+	return l.parent
+}
+
+// Item returns the variables of c, registering it on first use.
+func (l *ConstraintLayout) Item(c Component) *ConstraintItem {
+	if it, ok := l.items[c]; ok {
+		return it
+	}
+	name := fmt.Sprintf("%T#%d", c, len(l.items))
+	it := &ConstraintItem{
+		Left:   NewVariable(name + ".left"),
+		Top:    NewVariable(name + ".top"),
+		Width:  NewVariable(name + ".width"),
+		Height: NewVariable(name + ".height"),
+		comp:   c,
+	}
+	l.solver.AddConstraint(Ge(it.Width, Const(0)))
+	l.solver.AddConstraint(Ge(it.Height, Const(0)))
+	l.items[c] = it
+	l.updatePreferred(it)
+	return it
+}
+
+// RemoveItem forgets c: the constraints and edit variables involving its
+// variables are removed from the solver.
+func (l *ConstraintLayout) RemoveItem(c Component) {
+	it, ok := l.items[c]
+	if !ok {
+		return
+	}
+	delete(l.items, c)
+	for _, v := range []*Variable{it.Left, it.Top, it.Width, it.Height} {
+		if l.solver.HasEditVariable(v) {
+			l.solver.RemoveEditVariable(v)
+		}
+	}
+	for _, con := range l.constraintsOf(it) {
+		l.solver.RemoveConstraint(con)
+	}
+	// Also those set aside with a hidden item
+	for _, other := range l.items {
+		kept := other.hidden[:0]
+		for _, con := range other.hidden {
+			if !it.uses(con.Expression) {
+				kept = append(kept, con)
+			}
+		}
+		other.hidden = kept
+	}
+}
+
+// constraintsOf returns the constraints in the solver involving the
+// variables of it, edit variables excepted.
+func (l *ConstraintLayout) constraintsOf(it *ConstraintItem) []*Constraint {
+	var list []*Constraint
+	for _, con := range l.solver.order {
+		if it.uses(con.Expression) && !l.isEdit(con) {
+			list = append(list, con)
+		}
+	}
+	return list
+}
+
+func (l *ConstraintLayout) isEdit(con *Constraint) bool {
+	for _, info := range l.solver.edits {
+		if info.constraint == con {
+			return true
+		}
+	}
+	return false
+}
+
+// hide takes the constraints of a hidden child out of the solver.
+func (l *ConstraintLayout) hide(it *ConstraintItem) {
+	for _, con := range l.constraintsOf(it) {
+		l.solver.RemoveConstraint(con)
+		it.hidden = append(it.hidden, con)
+	}
+}
+
+// show puts back the constraints set aside by hide. Those that no longer
+// fit stay aside and are reported to OnError.
+func (l *ConstraintLayout) show(it *ConstraintItem) {
+	var kept []*Constraint
+	for _, con := range it.hidden {
+		if err := l.solver.AddConstraint(con); err != nil {
+			kept = append(kept, con)
+			if l.OnError != nil {
+				l.OnError(con, err)
+			}
+		}
+	}
+	it.hidden = kept
+}
+
+// updatePreferred (re)creates the implicit constraints of a child when its
+// preferred size or hints changed: weak towards the preferred size and
+// strong for the min/max sizes.
+func (l *ConstraintLayout) updatePreferred(it *ConstraintItem) {
+	hints := HintsOf(it.comp)
+	w, h := outerSize(it.comp, hints)
+	if it.implicit != nil && w == it.prefW && h == it.prefH && hints == it.hints {
+		return
+	}
+	for _, c := range it.implicit {
+		l.solver.RemoveConstraint(c)
+	}
+	it.prefW, it.prefH, it.hints = w, h, hints
+
+	mh, mv := float64(hints.Margin.Horizontal()), float64(hints.Margin.Vertical())
+	it.implicit = []*Constraint{
+		NewConstraint(it.Width, OpEq, Const(float64(w)), Weak),
+		NewConstraint(it.Height, OpEq, Const(float64(h)), Weak),
+	}
+	if hints.MinWidth > 0 {
+		it.implicit = append(it.implicit, NewConstraint(it.Width, OpGe, Const(float64(hints.MinWidth)+mh), Strong))
+	}
+	if hints.MaxWidth > 0 {
+		it.implicit = append(it.implicit, NewConstraint(it.Width, OpLe, Const(float64(hints.MaxWidth)+mh), Strong))
+	}
+	if hints.MinHeight > 0 {
+		it.implicit = append(it.implicit, NewConstraint(it.Height, OpGe, Const(float64(hints.MinHeight)+mv), Strong))
+	}
+	if hints.MaxHeight > 0 {
+		it.implicit = append(it.implicit, NewConstraint(it.Height, OpLe, Const(float64(hints.MaxHeight)+mv), Strong))
+	}
+	for _, c := range it.implicit {
+		l.solver.AddConstraint(c)
+	}
+}
+
+// Add adds constraints in order. It stops at the first one that cannot be
+// added (for example ErrUnsatisfiableConstraint); the rejected constraint
+// has no effect on the layout.
+func (l *ConstraintLayout) Add(constraints ...*Constraint) error {
+	for _, c := range constraints {
+		if err := l.solver.AddConstraint(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *ConstraintLayout) Remove(c *Constraint) error {
+	return l.solver.RemoveConstraint(c)
+}
+
+// AddEditVariable registers v for interactive changes via SuggestValue,
+// e.g. the position of a splitter being dragged.
+func (l *ConstraintLayout) AddEditVariable(v *Variable, strength Strength) error {
+	return l.solver.AddEditVariable(v, strength)
+}
+
+// SuggestValue changes an edit variable. Re-arrange the container
+// afterwards to apply it.
+func (l *ConstraintLayout) SuggestValue(v *Variable, value float64) error {
+	return l.solver.SuggestValue(v, value)
+}
+
+func (l *ConstraintLayout) Arrange(container Container) {
+	content := contentRect(container, l.Padding)
+
+	l.solver.SuggestValue(l.parent.Width, float64(content.Width))
+	l.solver.SuggestValue(l.parent.Height, float64(content.Height))
+
+	children := container.GetChildren()
+	present := make(map[Component]bool, len(children))
+	for _, child := range children {
+		present[child] = true
+		it := l.Item(child)
+		if !child.IsVisible() {
+			l.hide(it)
+			continue
+		}
+		l.show(it)
+		l.updatePreferred(it)
+	}
+	for c := range l.items {
+		if !present[c] {
+			l.RemoveItem(c)
+		}
+	}
+	l.solver.UpdateVariables()
+
+	for _, child := range children {
+		if !child.IsVisible() {
+			continue
+		}
+		it := l.items[child]
+		x0 := int32(math.Round(it.Left.Value()))
+		y0 := int32(math.Round(it.Top.Value()))
+		x1 := int32(math.Round(it.Left.Value() + it.Width.Value()))
+		y1 := int32(math.Round(it.Top.Value() + it.Height.Value()))
+		slot := Rect{X: content.X + x0, Y: content.Y + y0, Width: x1 - x0, Height: y1 - y0}
+		placeInSlot(child, it.hints, slot, pickAlign(it.hints.HAlign, AlignStretch), pickAlign(it.hints.VAlign, AlignStretch))
+	}
+}
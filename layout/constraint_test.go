@@ -0,0 +1,88 @@
+package layout
+
+import "testing"
+
+// hideBox is a box that can be hidden.
+type hideBox struct {
+	box
+	hidden bool
+}
+
+func (b *hideBox) IsVisible() bool { return !b.hidden }
+
+func TestConstraintLayoutItems(t *testing.T) {
+	a := &hideBox{box: box{w: 50, h: 20}}
+	b := &hideBox{box: box{w: 50, h: 20}}
+	p := &panel{bounds: Rect{0, 0, 200, 100}, children: []Component{a, b}}
+
+	l := NewConstraintLayout()
+	ia, ib := l.Item(a), l.Item(b)
+	before := len(l.solver.order)
+	if err := l.Add(
+		Eq(ia.Left, l.Parent().Left),
+		Eq(ia.Width, Const(120)),
+		Eq(ib.Left, ia.Right()),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Arrange(p)
+	if got := b.bounds.X; got != 120 {
+		t.Errorf("b.X = %d, want 120", got)
+	}
+
+	// The constraints of a hidden item no longer hold
+	a.hidden = true
+	l.Arrange(p)
+	if got := b.bounds.X; got != 0 {
+		t.Errorf("b.X with a hidden = %d, want 0", got)
+	}
+	a.hidden = false
+	l.Arrange(p)
+	if got := b.bounds.X; got != 120 {
+		t.Errorf("b.X with a shown again = %d, want 120", got)
+	}
+
+	// A constraint that no longer fits is reported and kept aside
+	var failed []*Constraint
+	l.OnError = func(c *Constraint, err error) { failed = append(failed, c) }
+	a.hidden = true
+	l.Arrange(p)
+	conflict := Eq(ia.Width, Const(60))
+	if err := l.Add(conflict); err != nil {
+		t.Fatal(err)
+	}
+	a.hidden = false
+	l.Arrange(p)
+	if len(failed) != 1 || len(ia.hidden) != 1 {
+		t.Fatalf("%d errors, %d constraints aside, want 1 and 1", len(failed), len(ia.hidden))
+	}
+	if got := b.bounds.X; got != 60 {
+		t.Errorf("b.X with the conflict = %d, want 60", got)
+	}
+	l.Remove(conflict)
+	l.Arrange(p)
+	if got := b.bounds.X; got != 120 || len(ia.hidden) != 0 {
+		t.Errorf("b.X after removing the conflict = %d, want 120", got)
+	}
+
+	l.RemoveItem(a)
+	if _, ok := l.items[a]; ok {
+		t.Error("a is still an item")
+	}
+	// b's own constraints stay, those involving a are gone
+	if got := len(l.solver.order); got != before-4 {
+		t.Errorf("%d constraints left, want %d", got, before-4)
+	}
+
+	// Components that are no longer children are pruned
+	l.Item(a)
+	p.children = []Component{b}
+	l.Arrange(p)
+	if len(l.items) != 1 {
+		t.Errorf("%d items after pruning, want 1", len(l.items))
+	}
+	if got := len(l.solver.order); got != before-4 {
+		t.Errorf("%d constraints left after pruning, want %d", got, before-4)
+	}
+}
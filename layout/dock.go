@@ -0,0 +1,123 @@
+package layout
+
+// Dock is the edge a child of a DockLayout is attached to.
+type Dock int
+
+const (
+	DockTop Dock = iota
+	DockBottom
+	DockLeft
+	DockRight
+	DockFill
+)
+
+// DockLayout attaches children to the edges of the container in order,
+// each taking its preferred size from the remaining space, like WinForms
+// Dock or WPF DockPanel. DockFill children share whatever is left in
+// equal parts, side by side after a left or right dock and stacked
+// otherwise.
+type DockLayout struct {
+	Spacing int32
+	Padding int32
+
+	// LastChildFill makes the last visible child fill the remaining space
+	// regardless of its dock.
+	LastChildFill bool
+
+	props map[Component]Dock
+}
+
+func NewDockLayout() *DockLayout {
+	return &DockLayout{
+		props: make(map[Component]Dock),
+	}
+}
+
+// SetDock attaches c to an edge. Children default to DockTop.
+func (l *DockLayout) SetDock(c Component, dock Dock) {
+	if l.props == nil {
+		l.props = make(map[Component]Dock)
+	}
+	l.props[c] = dock
+}
+
+func (l *DockLayout) Arrange(container Container) {
+	rest := contentRect(container, l.Padding)
+
+	var visible []Component
+	for _, child := range container.GetChildren() {
+		if child.IsVisible() {
+			visible = append(visible, child)
+		}
+	}
+
+	place := func(c Component, hints Hints, slot Rect) {
+		placeInSlot(c, hints, slot, pickAlign(hints.HAlign, AlignStretch), pickAlign(hints.VAlign, AlignStretch))
+	}
+
+	var fill []Component
+	sideBySide := false // The last docked child was left or right
+	for i, child := range visible {
+		dock := l.props[child]
+		if dock == DockFill || (l.LastChildFill && i == len(visible)-1) {
+			fill = append(fill, child)
+			continue
+		}
+
+		hints := HintsOf(child)
+		w, h := outerSize(child, hints)
+		if w > rest.Width {
+			w = rest.Width
+		}
+		if h > rest.Height {
+			h = rest.Height
+		}
+
+		sideBySide = dock == DockLeft || dock == DockRight
+		switch dock {
+		case DockTop:
+			place(child, hints, Rect{X: rest.X, Y: rest.Y, Width: rest.Width, Height: h})
+			rest.Y += h + l.Spacing
+			rest.Height -= h + l.Spacing
+		case DockBottom:
+			place(child, hints, Rect{X: rest.X, Y: rest.Y + rest.Height - h, Width: rest.Width, Height: h})
+			rest.Height -= h + l.Spacing
+		case DockLeft:
+			place(child, hints, Rect{X: rest.X, Y: rest.Y, Width: w, Height: rest.Height})
+			rest.X += w + l.Spacing
+			rest.Width -= w + l.Spacing
+		case DockRight:
+			place(child, hints, Rect{X: rest.X + rest.Width - w, Y: rest.Y, Width: w, Height: rest.Height})
+			rest.Width -= w + l.Spacing
+		}
+		if rest.Width < 0 {
+			rest.Width = 0
+		}
+		if rest.Height < 0 {
+			rest.Height = 0
+		}
+	}
+
+	if len(fill) == 0 {
+		return
+	}
+	n := int32(len(fill))
+	total := rest.Height
+	if sideBySide {
+		total = rest.Width
+	}
+	avail := max(total-(n-1)*l.Spacing, 0)
+	pos := int32(0)
+	for i, child := range fill {
+		size := avail / n
+		if int32(i) < avail%n {
+			size++
+		}
+		slot := Rect{X: rest.X, Y: rest.Y + pos, Width: rest.Width, Height: size}
+		if sideBySide {
+			slot = Rect{X: rest.X + pos, Y: rest.Y, Width: size, Height: rest.Height}
+		}
+		place(child, HintsOf(child), slot)
+		pos += size + l.Spacing
+	}
+}
@@ -0,0 +1,35 @@
+package layout
+
+import "testing"
+
+func TestDockLayout(t *testing.T) {
+	top := &box{w: 50, h: 20}
+	left := &box{w: 30, h: 20}
+	a := &box{w: 10, h: 10}
+	b := &box{w: 10, h: 10}
+	p := &panel{bounds: Rect{0, 0, 200, 100}, children: []Component{top, left, a, b}}
+
+	l := NewDockLayout()
+	l.Spacing = 10
+	l.SetDock(left, DockLeft)
+	l.SetDock(a, DockFill)
+	l.SetDock(b, DockFill)
+	l.Arrange(p)
+
+	want := []Rect{{0, 0, 200, 20}, {0, 30, 30, 70}, {40, 30, 75, 70}, {125, 30, 75, 70}}
+	for i, c := range []*box{top, left, a, b} {
+		if c.bounds != want[i] {
+			t.Errorf("child %d: got %v, want %v", i, c.bounds, want[i])
+		}
+	}
+
+	// Fill children are stacked after a top dock
+	p.children = []Component{top, a, b}
+	l.Arrange(p)
+	want = []Rect{{0, 0, 200, 20}, {0, 30, 200, 30}, {0, 70, 200, 30}}
+	for i, c := range []*box{top, a, b} {
+		if c.bounds != want[i] {
+			t.Errorf("stacked child %d: got %v, want %v", i, c.bounds, want[i])
+		}
+	}
+}
@@ -1,13 +1,28 @@
 package layout
 
+import (
+	"math"
+	"sort"
+)
+
 // FlexDirection defines the direction of the flex container
 type FlexDirection int
 
 const (
 	FlexRow FlexDirection = iota
 	FlexColumn
+	FlexRowReverse
+	FlexColumnReverse
 )
 
+func (d FlexDirection) isRow() bool {
+	return d == FlexRow || d == FlexRowReverse
+}
+
+func (d FlexDirection) isReverse() bool {
+	return d == FlexRowReverse || d == FlexColumnReverse
+}
+
 type FlexJustify int
 
 const (
@@ -16,28 +31,85 @@ const (
 	FlexCenter
 	FlexSpaceBetween
 	FlexSpaceAround
+	FlexSpaceEvenly
 )
 
 type FlexAlign int
 
 const (
-	AlignStart FlexAlign = iota
+	// AlignAuto, the zero value, defers to the next level: FlexProps.AlignSelf
+	// to the container's AlignItems, component hints to the layout. As the
+	// AlignItems of a FlexLayout it means AlignStart.
+	AlignAuto FlexAlign = iota
+	AlignStart
 	AlignEnd
 	AlignCenter
 	AlignStretch
 )
 
+// FlexWrapMode controls whether items may break onto multiple lines.
+type FlexWrapMode int
+
+const (
+	FlexNoWrap FlexWrapMode = iota
+	FlexWrap
+	FlexWrapReverse
+)
+
+// FlexAlignContent distributes lines along the cross axis of a wrapping
+// container. It has no effect on single-line containers.
+type FlexAlignContent int
+
+const (
+	ContentStretch FlexAlignContent = iota
+	ContentStart
+	ContentEnd
+	ContentCenter
+	ContentSpaceBetween
+	ContentSpaceAround
+	ContentSpaceEvenly
+)
+
+const (
+	// FlexBasisAuto, the zero value, makes an item use its preferred main
+	// size as flex-basis.
+	FlexBasisAuto int32 = 0
+	// FlexBasisZero is a flex-basis of 0 px, as in the CSS flex: 1.
+	FlexBasisZero int32 = -1
+)
+
+// FlexProps are the per-item flex properties. MinSize and MaxSize clamp
+// the main size; zero means unconstrained. The zero value has basis and
+// align-self auto, but does not shrink.
 type FlexProps struct {
-	Grow   int
-	Shrink int
+	Grow      int
+	Shrink    int
+	Basis     int32
+	AlignSelf FlexAlign
+	Order     int
+	MinSize   int32
+	MaxSize   int32
+}
+
+// DefaultFlexProps returns the CSS initial values: grow 0, shrink 1,
+// basis auto and align-self auto.
+func DefaultFlexProps() FlexProps {
+	return FlexProps{Shrink: 1}
 }
 
 type FlexLayout struct {
 	Direction      FlexDirection
 	JustifyContent FlexJustify
 	AlignItems     FlexAlign
-	Spacing        int32
-	Padding        int32
+	AlignContent   FlexAlignContent
+	Wrap           FlexWrapMode
+
+	// Spacing is the gap used on both axes unless RowGap or ColumnGap
+	// override it (like the CSS gap shorthand).
+	Spacing   int32
+	RowGap    int32
+	ColumnGap int32
+	Padding   int32
 
 	// Layout properties for children
 	props map[Component]FlexProps
@@ -50,238 +122,504 @@ func NewFlexLayout(direction FlexDirection) *FlexLayout {
 	}
 }
 
-func (l *FlexLayout) SetGrow(c Component, grow int) {
+// Props returns the flex properties of c, or the defaults if none were set.
+func (l *FlexLayout) Props(c Component) FlexProps {
+	if p, ok := l.props[c]; ok {
+		return p
+	}
+	return DefaultFlexProps()
+}
+
+// SetProps replaces all flex properties of c.
+func (l *FlexLayout) SetProps(c Component, p FlexProps) {
 	// We need to store props, but map keys must be comparable.
 	// Interfaces are comparable.
 	if l.props == nil {
 		l.props = make(map[Component]FlexProps)
 	}
-	p := l.props[c]
-	p.Grow = grow
 	l.props[c] = p
 }
 
-func (l *FlexLayout) Arrange(container Container) {
-	bounds := container.GetBounds()
-	width := bounds.Width - 2*l.Padding
-	height := bounds.Height - 2*l.Padding
-	startX := bounds.X + l.Padding
-	startY := bounds.Y + l.Padding
-
-	if l.Direction == FlexRow {
-		l.arrangeRow(container, startX, startY, width, height)
-	} else {
-		l.arrangeColumn(container, startX, startY, width, height)
-	}
+func (l *FlexLayout) update(c Component, fn func(p *FlexProps)) {
+	p := l.Props(c)
+	fn(&p)
+	l.SetProps(c, p)
 }
 
-func (l *FlexLayout) arrangeRow(container Container, x, y, width, height int32) {
-	children := container.GetChildren()
-	if len(children) == 0 {
-		return
+func (l *FlexLayout) SetGrow(c Component, grow int) {
+	l.update(c, func(p *FlexProps) { p.Grow = grow })
+}
+
+func (l *FlexLayout) SetShrink(c Component, shrink int) {
+	l.update(c, func(p *FlexProps) { p.Shrink = shrink })
+}
+
+// SetBasis sets the flex-basis of c in pixels, FlexBasisAuto or
+// FlexBasisZero.
+func (l *FlexLayout) SetBasis(c Component, basis int32) {
+	l.update(c, func(p *FlexProps) { p.Basis = basis })
+}
+
+// SetFlex is shorthand for the CSS flex property.
+func (l *FlexLayout) SetFlex(c Component, grow, shrink int, basis int32) {
+	l.update(c, func(p *FlexProps) {
+		p.Grow = grow
+		p.Shrink = shrink
+		p.Basis = basis
+	})
+}
+
+func (l *FlexLayout) SetAlignSelf(c Component, align FlexAlign) {
+	l.update(c, func(p *FlexProps) { p.AlignSelf = align })
+}
+
+func (l *FlexLayout) SetOrder(c Component, order int) {
+	l.update(c, func(p *FlexProps) { p.Order = order })
+}
+
+// SetMinMax clamps the main size of c. Zero disables a bound.
+func (l *FlexLayout) SetMinMax(c Component, min, max int32) {
+	l.update(c, func(p *FlexProps) {
+		p.MinSize = min
+		p.MaxSize = max
+	})
+}
+
+// flexItem holds the working state of one child during Arrange.
+// All sizes are in main/cross axis terms.
+type flexItem struct {
+	comp  Component
+	props FlexProps
+
+	// Limits merged from FlexProps and the component's Hints
+	minMain, maxMain   float64
+	minCross, maxCross float64
+
+	// Margins: main is start+end, the *Start fields the margin on the
+	// start side, which is the right or bottom one when reversed
+	mainMargin, mainMarginStart   float64
+	crossMargin, crossMarginStart float64
+	crossAlign                    FlexAlign
+
+	base   float64 // flex base size
+	hypo   float64 // hypothetical main size (base clamped to min/max)
+	target float64 // resolved main size
+	frozen bool
+
+	cross float64 // hypothetical cross size
+
+	mainPos  float64
+	crossPos float64
+	crossLen float64
+}
+
+func (it *flexItem) clamp(size float64) float64 {
+	return clampFloat(size, it.minMain, it.maxMain)
+}
+
+func clampFloat(v, min, max float64) float64 {
+	if max > 0 && v > max {
+		v = max
+	}
+	if v < min {
+		v = min
 	}
+	if v < 0 {
+		v = 0
+	}
+	return v
+}
 
-	// 1. Measure total fixed width and total grow
-	totalFixedWidth := int32(0)
-	totalGrow := 0
-	visibleCount := 0
+// tighter merges two min/max pairs where 0 means unconstrained.
+func tighter(min1, max1, min2, max2 int32) (float64, float64) {
+	min := min1
+	if min2 > min {
+		min = min2
+	}
+	max := max1
+	if max == 0 || (max2 > 0 && max2 < max) {
+		max = max2
+	}
+	return float64(min), float64(max)
+}
 
-	childSizes := make([]struct{ w, h int32 }, len(children))
+type flexLine struct {
+	items []*flexItem
+	cross float64
+	pos   float64
+}
 
-	for i, child := range children {
-		if !child.IsVisible() {
-			continue
-		}
-		visibleCount++
-		w, h := child.GetPreferredSize()
-		if w <= 0 {
-			w = child.GetBounds().Width
-		}
-		if h <= 0 {
-			h = child.GetBounds().Height
-		}
+func (l *FlexLayout) gaps() (mainGap, crossGap float64) {
+	rowGap, colGap := l.RowGap, l.ColumnGap
+	if rowGap == 0 {
+		rowGap = l.Spacing
+	}
+	if colGap == 0 {
+		colGap = l.Spacing
+	}
+	if l.Direction.isRow() {
+		return float64(colGap), float64(rowGap)
+	}
+	return float64(rowGap), float64(colGap)
+}
 
-		childSizes[i] = struct{ w, h int32 }{w, h}
+func (l *FlexLayout) Arrange(container Container) {
+	content := contentRect(container, l.Padding)
+	width, height := content.Width, content.Height
+	startX, startY := content.X, content.Y
+
+	isRow := l.Direction.isRow()
+	mainSize, crossSize := float64(width), float64(height)
+	if !isRow {
+		mainSize, crossSize = crossSize, mainSize
+	}
+	mainGap, crossGap := l.gaps()
 
-		totalFixedWidth += w
-		if p, ok := l.props[child]; ok {
-			totalGrow += p.Grow
-		}
+	// 1. Collect visible items in order-modified document order
+	items := l.collectItems(container, isRow)
+	if len(items) == 0 {
+		return
 	}
 
-	if visibleCount > 0 {
-		totalFixedWidth += int32(visibleCount-1) * l.Spacing
+	// 2. Break into lines
+	lines := l.buildLines(items, mainSize, mainGap)
+
+	// 3. Resolve flexible lengths and justify each line
+	for _, line := range lines {
+		resolveFlexibleLengths(line.items, mainSize, mainGap)
+		l.justifyLine(line.items, mainSize, mainGap)
 	}
 
-	remainingSpace := width - totalFixedWidth
-	if remainingSpace < 0 {
-		remainingSpace = 0 // Overflow, we don't handle shrink yet properly, just clip
+	// 4. Cross sizes of lines
+	if l.Wrap == FlexNoWrap {
+		lines[0].cross = crossSize
+	} else {
+		for _, line := range lines {
+			for _, it := range line.items {
+				if outer := it.cross + it.crossMargin; outer > line.cross {
+					line.cross = outer
+				}
+			}
+		}
 	}
 
-	// 2. Distribute space
-	currentX := x
+	// 5. Align lines (align-content) and items (align-self)
+	l.alignContent(lines, crossSize, crossGap)
+	for _, line := range lines {
+		for _, it := range line.items {
+			l.alignItem(it, line)
+		}
+	}
 
-	// Calculate gap for justification if no grow
-	justifyGap := int32(0)
-	if totalGrow == 0 && remainingSpace > 0 {
-		switch l.JustifyContent {
-		case FlexEnd:
-			currentX += remainingSpace
-		case FlexCenter:
-			currentX += remainingSpace / 2
-		case FlexSpaceBetween:
-			if visibleCount > 1 {
-				justifyGap = remainingSpace / int32(visibleCount-1)
+	// 6. Mirror for reverse directions and write back bounds
+	for _, line := range lines {
+		for _, it := range line.items {
+			mainPos := it.mainPos
+			if l.Direction.isReverse() {
+				mainPos = mainSize - mainPos - it.target
+			}
+			crossPos := it.crossPos
+			if l.Wrap == FlexWrapReverse {
+				crossPos = crossSize - crossPos - it.crossLen
 			}
-		case FlexSpaceAround:
-			if visibleCount > 0 {
-				justifyGap = remainingSpace / int32(visibleCount) // Approximate
-				currentX += justifyGap / 2
+
+			m0, m1 := roundEdge(mainPos), roundEdge(mainPos+it.target)
+			c0, c1 := roundEdge(crossPos), roundEdge(crossPos+it.crossLen)
+			if isRow {
+				it.comp.SetBounds(startX+m0, startY+c0, m1-m0, c1-c0)
+			} else {
+				it.comp.SetBounds(startX+c0, startY+m0, c1-c0, m1-m0)
 			}
 		}
 	}
+}
+
+func roundEdge(v float64) int32 {
+	return int32(math.Round(v))
+}
 
-	for i, child := range children {
+func (l *FlexLayout) collectItems(container Container, isRow bool) []*flexItem {
+	var items []*flexItem
+	for _, child := range container.GetChildren() {
 		if !child.IsVisible() {
 			continue
 		}
-
-		w, h := childSizes[i].w, childSizes[i].h
-
-		// Apply Grow
-		grow := 0
-		if p, ok := l.props[child]; ok {
-			grow = p.Grow
+		hints := HintsOf(child)
+		w, h := preferredSize(child, hints)
+		m := hints.Margin
+
+		it := &flexItem{comp: child, props: l.Props(child)}
+		mainPref := w
+		if isRow {
+			it.cross = float64(h)
+			it.minMain, it.maxMain = tighter(it.props.MinSize, it.props.MaxSize, hints.MinWidth, hints.MaxWidth)
+			it.minCross, it.maxCross = float64(hints.MinHeight), float64(hints.MaxHeight)
+			it.mainMargin, it.mainMarginStart = float64(m.Horizontal()), float64(m.Left)
+			it.crossMargin, it.crossMarginStart = float64(m.Vertical()), float64(m.Top)
+			it.crossAlign = hints.VAlign
+		} else {
+			mainPref = h
+			it.cross = float64(w)
+			it.minMain, it.maxMain = tighter(it.props.MinSize, it.props.MaxSize, hints.MinHeight, hints.MaxHeight)
+			it.minCross, it.maxCross = float64(hints.MinWidth), float64(hints.MaxWidth)
+			it.mainMargin, it.mainMarginStart = float64(m.Vertical()), float64(m.Top)
+			it.crossMargin, it.crossMarginStart = float64(m.Horizontal()), float64(m.Left)
+			it.crossAlign = hints.HAlign
 		}
-
-		if totalGrow > 0 && remainingSpace > 0 {
-			extra := int32(float32(remainingSpace) * (float32(grow) / float32(totalGrow)))
-			w += extra
+		// Positions are mirrored after alignment, so the start side of a
+		// reversed axis is the physical end
+		if l.Direction.isReverse() {
+			it.mainMarginStart = it.mainMargin - it.mainMarginStart
+		}
+		if l.Wrap == FlexWrapReverse {
+			it.crossMarginStart = it.crossMargin - it.crossMarginStart
 		}
 
-		// Apply AlignItems (Cross Axis)
-		childY := y
-		childH := h
-
-		switch l.AlignItems {
-		case AlignStart:
-			childY = y
-		case AlignEnd:
-			childY = y + height - h
-		case AlignCenter:
-			childY = y + (height-h)/2
-		case AlignStretch:
-			childY = y
-			childH = height
+		switch {
+		case it.props.Basis == FlexBasisZero:
+			it.base = 0
+		case it.props.Basis > 0:
+			it.base = float64(it.props.Basis)
+		default:
+			it.base = float64(mainPref)
 		}
+		it.hypo = it.clamp(it.base)
+		items = append(items, it)
+	}
 
-		child.SetBounds(currentX, childY, w, childH)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].props.Order < items[j].props.Order
+	})
+	return items
+}
 
-		currentX += w + l.Spacing + justifyGap
+func (l *FlexLayout) buildLines(items []*flexItem, mainSize, mainGap float64) []*flexLine {
+	if l.Wrap == FlexNoWrap {
+		return []*flexLine{{items: items}}
 	}
+
+	var lines []*flexLine
+	cur := &flexLine{}
+	used := 0.0
+	for _, it := range items {
+		next := used + it.hypo + it.mainMargin
+		if len(cur.items) > 0 {
+			next += mainGap
+		}
+		if len(cur.items) > 0 && next > mainSize {
+			lines = append(lines, cur)
+			cur = &flexLine{}
+			next = it.hypo + it.mainMargin
+		}
+		cur.items = append(cur.items, it)
+		used = next
+	}
+	return append(lines, cur)
 }
 
-func (l *FlexLayout) arrangeColumn(container Container, x, y, width, height int32) {
-	children := container.GetChildren()
-	if len(children) == 0 {
-		return
+// resolveFlexibleLengths implements CSS Flexbox §9.7 for one line.
+func resolveFlexibleLengths(items []*flexItem, mainSize, mainGap float64) {
+	// Gaps and margins are fixed space
+	gapTotal := mainGap * float64(len(items)-1)
+	for _, it := range items {
+		gapTotal += it.mainMargin
 	}
 
-	// 1. Measure
-	totalFixedHeight := int32(0)
-	totalGrow := 0
-	visibleCount := 0
+	sumHypo := gapTotal
+	for _, it := range items {
+		sumHypo += it.hypo
+	}
+	growing := sumHypo < mainSize
+
+	// Size inflexible items
+	for _, it := range items {
+		it.target = it.hypo
+		it.frozen = false
+		factor := it.props.Shrink
+		if growing {
+			factor = it.props.Grow
+		}
+		if factor <= 0 || (growing && it.base > it.hypo) || (!growing && it.base < it.hypo) {
+			it.frozen = true
+		}
+	}
 
-	childSizes := make([]struct{ w, h int32 }, len(children))
+	for {
+		// Remaining free space
+		free := mainSize - gapTotal
+		sumFactors := 0.0
+		unfrozen := 0
+		for _, it := range items {
+			if it.frozen {
+				free -= it.target
+				continue
+			}
+			free -= it.base
+			unfrozen++
+			if growing {
+				sumFactors += float64(it.props.Grow)
+			} else {
+				sumFactors += float64(it.props.Shrink) * it.base
+			}
+		}
+		if unfrozen == 0 {
+			return
+		}
 
-	for i, child := range children {
-		if !child.IsVisible() {
-			continue
+		// Distribute it proportionally
+		for _, it := range items {
+			if it.frozen {
+				continue
+			}
+			it.target = it.base
+			if sumFactors == 0 || free == 0 {
+				continue
+			}
+			if growing {
+				it.target += free * float64(it.props.Grow) / sumFactors
+			} else {
+				it.target += free * float64(it.props.Shrink) * it.base / sumFactors
+			}
 		}
-		visibleCount++
-		w, h := child.GetPreferredSize()
-		if w <= 0 {
-			w = child.GetBounds().Width
+
+		// Fix min/max violations
+		totalViolation := 0.0
+		for _, it := range items {
+			if it.frozen {
+				continue
+			}
+			clamped := it.clamp(it.target)
+			totalViolation += clamped - it.target
 		}
-		if h <= 0 {
-			h = child.GetBounds().Height
+		for _, it := range items {
+			if it.frozen {
+				continue
+			}
+			clamped := it.clamp(it.target)
+			switch {
+			case totalViolation == 0:
+				it.frozen = true
+			case totalViolation > 0 && clamped > it.target:
+				it.frozen = true
+			case totalViolation < 0 && clamped < it.target:
+				it.frozen = true
+			}
+			it.target = clamped
 		}
+	}
+}
 
-		childSizes[i] = struct{ w, h int32 }{w, h}
+func (l *FlexLayout) justifyLine(items []*flexItem, mainSize, mainGap float64) {
+	used := mainGap * float64(len(items)-1)
+	for _, it := range items {
+		used += it.target + it.mainMargin
+	}
+	free := mainSize - used
+	n := float64(len(items))
 
-		totalFixedHeight += h
-		if p, ok := l.props[child]; ok {
-			totalGrow += p.Grow
+	justify := l.JustifyContent
+	// Negative free space makes the distributed modes fall back (CSS §8.2)
+	if free < 0 {
+		switch justify {
+		case FlexSpaceBetween:
+			justify = FlexStart
+		case FlexSpaceAround, FlexSpaceEvenly:
+			justify = FlexCenter
 		}
 	}
 
-	if visibleCount > 0 {
-		totalFixedHeight += int32(visibleCount-1) * l.Spacing
+	offset, extraGap := 0.0, 0.0
+	switch justify {
+	case FlexEnd:
+		offset = free
+	case FlexCenter:
+		offset = free / 2
+	case FlexSpaceBetween:
+		if n > 1 {
+			extraGap = free / (n - 1)
+		}
+	case FlexSpaceAround:
+		extraGap = free / n
+		offset = extraGap / 2
+	case FlexSpaceEvenly:
+		extraGap = free / (n + 1)
+		offset = extraGap
 	}
 
-	remainingSpace := height - totalFixedHeight
-	if remainingSpace < 0 {
-		remainingSpace = 0
+	pos := offset
+	for _, it := range items {
+		it.mainPos = pos + it.mainMarginStart
+		pos += it.target + it.mainMargin + mainGap + extraGap
 	}
+}
 
-	// 2. Distribute space
-	currentY := y
+func (l *FlexLayout) alignContent(lines []*flexLine, crossSize, crossGap float64) {
+	if l.Wrap == FlexNoWrap {
+		lines[0].pos = 0
+		return
+	}
 
-	justifyGap := int32(0)
-	if totalGrow == 0 && remainingSpace > 0 {
-		switch l.JustifyContent {
-		case FlexEnd:
-			currentY += remainingSpace
-		case FlexCenter:
-			currentY += remainingSpace / 2
-		case FlexSpaceBetween:
-			if visibleCount > 1 {
-				justifyGap = remainingSpace / int32(visibleCount-1)
-			}
-		case FlexSpaceAround:
-			if visibleCount > 0 {
-				justifyGap = remainingSpace / int32(visibleCount)
-				currentY += justifyGap / 2
-			}
+	used := crossGap * float64(len(lines)-1)
+	for _, line := range lines {
+		used += line.cross
+	}
+	free := crossSize - used
+	n := float64(len(lines))
+
+	mode := l.AlignContent
+	if free < 0 {
+		switch mode {
+		case ContentStretch, ContentSpaceBetween:
+			mode = ContentStart
+		case ContentSpaceAround, ContentSpaceEvenly:
+			mode = ContentCenter
 		}
 	}
 
-	for i, child := range children {
-		if !child.IsVisible() {
-			continue
+	offset, extraGap := 0.0, 0.0
+	switch mode {
+	case ContentStretch:
+		for _, line := range lines {
+			line.cross += free / n
 		}
-
-		w, h := childSizes[i].w, childSizes[i].h
-
-		// Apply Grow
-		grow := 0
-		if p, ok := l.props[child]; ok {
-			grow = p.Grow
+	case ContentEnd:
+		offset = free
+	case ContentCenter:
+		offset = free / 2
+	case ContentSpaceBetween:
+		if n > 1 {
+			extraGap = free / (n - 1)
 		}
+	case ContentSpaceAround:
+		extraGap = free / n
+		offset = extraGap / 2
+	case ContentSpaceEvenly:
+		extraGap = free / (n + 1)
+		offset = extraGap
+	}
 
-		if totalGrow > 0 && remainingSpace > 0 {
-			extra := int32(float32(remainingSpace) * (float32(grow) / float32(totalGrow)))
-			h += extra
-		}
+	pos := offset
+	for _, line := range lines {
+		line.pos = pos
+		pos += line.cross + crossGap + extraGap
+	}
+}
 
-		// Apply AlignItems (Cross Axis)
-		childX := x
-		childW := w
-
-		switch l.AlignItems {
-		case AlignStart:
-			childX = x
-		case AlignEnd:
-			childX = x + width - w
-		case AlignCenter:
-			childX = x + (width-w)/2
-		case AlignStretch:
-			childX = x
-			childW = width
-		}
+func (l *FlexLayout) alignItem(it *flexItem, line *flexLine) {
+	align := pickAlign(it.props.AlignSelf, it.crossAlign, l.AlignItems, AlignStart)
 
-		child.SetBounds(childX, currentY, childW, h)
+	start := line.pos + it.crossMarginStart
+	avail := line.cross - it.crossMargin
+	it.crossLen = it.cross
+	if align == AlignStretch {
+		it.crossLen = clampFloat(avail, it.minCross, it.maxCross)
+	}
 
-		currentY += h + l.Spacing + justifyGap
+	it.crossPos = start
+	switch align {
+	case AlignEnd:
+		it.crossPos = start + avail - it.crossLen
+	case AlignCenter:
+		it.crossPos = start + (avail-it.crossLen)/2
 	}
 }
@@ -0,0 +1,232 @@
+package layout
+
+import "testing"
+
+// box is a component with a fixed preferred size.
+type box struct {
+	w, h   int32
+	bounds Rect
+	hints  Hints
+}
+
+func (b *box) SetBounds(x, y, width, height int32) { b.bounds = Rect{x, y, width, height} }
+func (b *box) GetBounds() Rect                     { return b.bounds }
+func (b *box) GetPreferredSize() (int32, int32)    { return b.w, b.h }
+func (b *box) IsVisible() bool                     { return true }
+func (b *box) LayoutHints() Hints                  { return b.hints }
+
+type panel struct {
+	bounds   Rect
+	children []Component
+}
+
+func (p *panel) GetBounds() Rect          { return p.bounds }
+func (p *panel) GetChildren() []Component { return p.children }
+
+// flexSpec is an item of a flexCase.
+type flexSpec struct {
+	w, h     int32 // Preferred size
+	grow     int
+	shrink   int
+	basis    int32
+	min, max int32
+	order    int
+	self     FlexAlign
+	margin   Insets
+}
+
+// flexCase is a container and its items, with the bounds a browser gives
+// the items.
+type flexCase struct {
+	name          string
+	width, height int32
+	flex          FlexLayout
+	items         []flexSpec
+	want          []Rect
+}
+
+var flexCases = []flexCase{
+	{
+		name: "grow", width: 300, height: 50,
+		items: []flexSpec{{w: 50, h: 20, grow: 1}, {w: 50, h: 20, grow: 2}, {w: 50, h: 20}},
+		want:  []Rect{{0, 0, 100, 20}, {100, 0, 150, 20}, {250, 0, 50, 20}},
+	},
+	{
+		name: "grow freezes items at their max", width: 300, height: 50,
+		items: []flexSpec{{w: 50, h: 20, grow: 1, max: 80}, {w: 50, h: 20, grow: 1}},
+		want:  []Rect{{0, 0, 80, 20}, {80, 0, 220, 20}},
+	},
+	{
+		name: "shrink freezes items at their min", width: 200, height: 50,
+		items: []flexSpec{{w: 150, h: 20, shrink: 1, min: 120}, {w: 150, h: 20, shrink: 1}},
+		want:  []Rect{{0, 0, 120, 20}, {120, 0, 80, 20}},
+	},
+	{
+		name: "shrink is scaled by the basis", width: 200, height: 50,
+		items: []flexSpec{{w: 100, h: 20, shrink: 1}, {w: 200, h: 20, shrink: 1}},
+		want:  []Rect{{0, 0, 67, 20}, {67, 0, 133, 20}},
+	},
+	{
+		name: "basis overrides the preferred size", width: 300, height: 50,
+		items: []flexSpec{{w: 10, h: 20, grow: 1, basis: 100}, {w: 10, h: 20, grow: 1, basis: 50}},
+		want:  []Rect{{0, 0, 175, 20}, {175, 0, 125, 20}},
+	},
+	{
+		name: "zero basis", width: 300, height: 50,
+		items: []flexSpec{{w: 100, h: 20, grow: 1, basis: FlexBasisZero}, {w: 20, h: 20, grow: 2, basis: FlexBasisZero}},
+		want:  []Rect{{0, 0, 100, 20}, {100, 0, 200, 20}},
+	},
+	{
+		name: "space-between with a gap", width: 300, height: 50,
+		flex:  FlexLayout{JustifyContent: FlexSpaceBetween, Spacing: 10},
+		items: []flexSpec{{w: 50, h: 20}, {w: 50, h: 20}, {w: 50, h: 20}},
+		want:  []Rect{{0, 0, 50, 20}, {125, 0, 50, 20}, {250, 0, 50, 20}},
+	},
+	{
+		name: "space-evenly", width: 300, height: 50,
+		flex:  FlexLayout{JustifyContent: FlexSpaceEvenly},
+		items: []flexSpec{{w: 50, h: 20}, {w: 50, h: 20}},
+		want:  []Rect{{67, 0, 50, 20}, {183, 0, 50, 20}},
+	},
+	{
+		name: "order", width: 300, height: 50,
+		items: []flexSpec{{w: 50, h: 20, order: 2}, {w: 50, h: 20}, {w: 50, h: 20, order: 1}},
+		want:  []Rect{{100, 0, 50, 20}, {0, 0, 50, 20}, {50, 0, 50, 20}},
+	},
+	{
+		name: "align-items and align-self", width: 300, height: 100,
+		flex: FlexLayout{AlignItems: AlignCenter},
+		items: []flexSpec{
+			{w: 50, h: 20},
+			{w: 50, h: 40, self: AlignEnd},
+			{w: 50, h: 30, self: AlignStretch},
+		},
+		want: []Rect{{0, 40, 50, 20}, {50, 60, 50, 40}, {100, 0, 50, 100}},
+	},
+	{
+		name: "align-self start overrides align-items", width: 300, height: 100,
+		flex:  FlexLayout{AlignItems: AlignStretch},
+		items: []flexSpec{{w: 50, h: 20, self: AlignStart}, {w: 50, h: 20}},
+		want:  []Rect{{0, 0, 50, 20}, {50, 0, 50, 100}},
+	},
+	{
+		name: "align-items defaults to start", width: 300, height: 100,
+		items: []flexSpec{{w: 50, h: 20, grow: 1}},
+		want:  []Rect{{0, 0, 300, 20}},
+	},
+	{
+		name: "margins", width: 300, height: 100,
+		items: []flexSpec{
+			{w: 50, h: 20, margin: Insets{Left: 10, Top: 5}},
+			{w: 50, h: 20, margin: Insets{Right: 20}},
+			{w: 50, h: 20},
+		},
+		want: []Rect{{10, 5, 50, 20}, {60, 0, 50, 20}, {130, 0, 50, 20}},
+	},
+	{
+		name: "wrap with align-content center and gaps", width: 200, height: 200,
+		flex:  FlexLayout{Wrap: FlexWrap, AlignContent: ContentCenter, Spacing: 10},
+		items: []flexSpec{{w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}},
+		want:  []Rect{{0, 65, 80, 30}, {90, 65, 80, 30}, {0, 105, 80, 30}, {90, 105, 80, 30}},
+	},
+	{
+		name: "wrap with align-content stretch", width: 200, height: 200,
+		flex:  FlexLayout{Wrap: FlexWrap, AlignItems: AlignStretch},
+		items: []flexSpec{{w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}},
+		want:  []Rect{{0, 0, 80, 100}, {80, 0, 80, 100}, {0, 100, 80, 100}, {80, 100, 80, 100}},
+	},
+	{
+		name: "wrap with align-content space-between and a row gap", width: 200, height: 200,
+		flex:  FlexLayout{Wrap: FlexWrap, AlignContent: ContentSpaceBetween, RowGap: 10},
+		items: []flexSpec{{w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}},
+		want:  []Rect{{0, 0, 80, 30}, {80, 0, 80, 30}, {0, 170, 80, 30}, {80, 170, 80, 30}},
+	},
+	{
+		name: "wrapped lines grow separately", width: 200, height: 100,
+		flex:  FlexLayout{Wrap: FlexWrap, AlignContent: ContentStart},
+		items: []flexSpec{{w: 80, h: 30, grow: 1}, {w: 80, h: 30, grow: 1}, {w: 80, h: 20, grow: 1}},
+		want:  []Rect{{0, 0, 100, 30}, {100, 0, 100, 30}, {0, 30, 200, 20}},
+	},
+	{
+		name: "wrap-reverse", width: 200, height: 100,
+		flex:  FlexLayout{Wrap: FlexWrapReverse, AlignContent: ContentStart},
+		items: []flexSpec{{w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}, {w: 80, h: 30}},
+		want:  []Rect{{0, 70, 80, 30}, {80, 70, 80, 30}, {0, 40, 80, 30}, {80, 40, 80, 30}},
+	},
+	{
+		name: "row-reverse", width: 300, height: 50,
+		flex:  FlexLayout{Direction: FlexRowReverse},
+		items: []flexSpec{{w: 50, h: 20}, {w: 100, h: 20}},
+		want:  []Rect{{250, 0, 50, 20}, {150, 0, 100, 20}},
+	},
+	{
+		name: "column-reverse with justify end and a gap", width: 100, height: 300,
+		flex:  FlexLayout{Direction: FlexColumnReverse, JustifyContent: FlexEnd, Spacing: 10},
+		items: []flexSpec{{w: 40, h: 50}, {w: 40, h: 50}},
+		want:  []Rect{{0, 60, 40, 50}, {0, 0, 40, 50}},
+	},
+	{
+		name: "row-reverse with margins", width: 300, height: 50,
+		flex: FlexLayout{Direction: FlexRowReverse},
+		items: []flexSpec{
+			{w: 50, h: 20, margin: Insets{Left: 10}},
+			{w: 50, h: 20, margin: Insets{Right: 20}},
+		},
+		want: []Rect{{250, 0, 50, 20}, {170, 0, 50, 20}},
+	},
+	{
+		name: "column-reverse with margins", width: 100, height: 300,
+		flex: FlexLayout{Direction: FlexColumnReverse},
+		items: []flexSpec{
+			{w: 40, h: 50, margin: Insets{Top: 10}},
+			{w: 40, h: 50, margin: Insets{Bottom: 5, Left: 8}},
+		},
+		want: []Rect{{0, 250, 40, 50}, {8, 185, 40, 50}},
+	},
+	{
+		name: "wrap-reverse with margins", width: 200, height: 100,
+		flex: FlexLayout{Wrap: FlexWrapReverse, AlignContent: ContentStart},
+		items: []flexSpec{
+			{w: 80, h: 30, margin: Insets{Top: 10}},
+			{w: 80, h: 30, margin: Insets{Bottom: 4}},
+			{w: 80, h: 30, margin: Insets{Left: 10}},
+		},
+		want: []Rect{{0, 70, 80, 30}, {80, 66, 80, 30}, {10, 30, 80, 30}},
+	},
+	{
+		name: "column grows with a max", width: 100, height: 300,
+		flex:  FlexLayout{Direction: FlexColumn, AlignItems: AlignStretch},
+		items: []flexSpec{{w: 40, h: 50, grow: 1, max: 100}, {w: 40, h: 50, grow: 1}},
+		want:  []Rect{{0, 0, 100, 100}, {0, 100, 100, 200}},
+	},
+}
+
+func TestFlexLayout(t *testing.T) {
+	for _, tc := range flexCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := tc.flex
+			p := &panel{bounds: Rect{0, 0, tc.width, tc.height}}
+			boxes := make([]*box, len(tc.items))
+			for i, s := range tc.items {
+				b := &box{w: s.w, h: s.h, hints: Hints{Margin: s.margin}}
+				l.SetProps(b, FlexProps{
+					Grow:      s.grow,
+					Shrink:    s.shrink,
+					Basis:     s.basis,
+					AlignSelf: s.self,
+					Order:     s.order,
+					MinSize:   s.min,
+					MaxSize:   s.max,
+				})
+				boxes[i] = b
+				p.children = append(p.children, b)
+			}
+			l.Arrange(p)
+			for i, b := range boxes {
+				if b.bounds != tc.want[i] {
+					t.Errorf("item %d: got %v, want %v", i, b.bounds, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -1,99 +1,646 @@
 package layout
 
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TrackKind describes how one side of a grid track is sized.
+type TrackKind int
+
+const (
+	TrackFixed TrackKind = iota // Value pixels
+	TrackAuto                   // Sized to content
+	TrackFr                     // Value fractions of the leftover space
+)
+
+// TrackBreadth is the minimum or maximum sizing function of a track.
+type TrackBreadth struct {
+	Kind  TrackKind
+	Value float64
+}
+
+// Track is a row or column definition, equivalent to CSS minmax(Min, Max).
+// Use Px, Fr, Auto and MinMax to build them.
+type Track struct {
+	Min, Max TrackBreadth
+}
+
+// Px returns a fixed track of the given size.
+func Px(size int32) Track {
+	b := TrackBreadth{Kind: TrackFixed, Value: float64(size)}
+	return Track{Min: b, Max: b}
+}
+
+// Fr returns a flexible track, like CSS "1fr" (minmax(auto, 1fr)).
+func Fr(fr float64) Track {
+	return Track{
+		Min: TrackBreadth{Kind: TrackAuto},
+		Max: TrackBreadth{Kind: TrackFr, Value: fr},
+	}
+}
+
+// Auto returns a content-sized track.
+func Auto() Track {
+	return Track{Min: TrackBreadth{Kind: TrackAuto}, Max: TrackBreadth{Kind: TrackAuto}}
+}
+
+// MinMax combines the minimum of min and the maximum of max,
+// e.g. MinMax(Px(100), Fr(1)).
+func MinMax(min, max Track) Track {
+	return Track{Min: min.Min, Max: max.Max}
+}
+
+// Tracks repeats t n times, like CSS repeat(n, t).
+func Tracks(n int, t Track) []Track {
+	res := make([]Track, n)
+	for i := range res {
+		res[i] = t
+	}
+	return res
+}
+
+// GridProps are the per-child grid properties. A negative Row or Col
+// means the child is auto-placed.
 type GridProps struct {
 	Row, Col         int
 	RowSpan, ColSpan int
+	Area             string
+	HAlign, VAlign   FlexAlign
+}
+
+// DefaultGridProps returns the properties of a child that was never configured.
+func DefaultGridProps() GridProps {
+	return GridProps{
+		Row:     -1,
+		Col:     -1,
+		RowSpan: 1,
+		ColSpan: 1,
+		HAlign:  AlignAuto,
+		VAlign:  AlignAuto,
+	}
+}
+
+type gridArea struct {
+	row, col         int
+	rowSpan, colSpan int
 }
 
 type GridLayout struct {
+	// Rows and Cols define uniform equal-sized tracks when RowTracks or
+	// ColTracks are empty.
 	Rows, Cols int
-	Spacing    int32
-	Padding    int32
+	RowTracks  []Track
+	ColTracks  []Track
+
+	// AutoRows and AutoCols size implicit tracks created by placement
+	// outside the explicit grid. They default to Auto().
+	AutoRows *Track
+	AutoCols *Track
+
+	// Spacing is the gap on both axes unless RowGap or ColGap override it.
+	Spacing int32
+	RowGap  int32
+	ColGap  int32
+	Padding int32
+
+	// Default alignment of children inside their cell
+	JustifyItems FlexAlign
+	AlignItems   FlexAlign
 
 	// Store child positions
 	props map[Component]GridProps
+	areas map[string]gridArea
 }
 
 func NewGridLayout(rows, cols int) *GridLayout {
 	return &GridLayout{
-		Rows:  rows,
-		Cols:  cols,
-		props: make(map[Component]GridProps),
+		Rows:         rows,
+		Cols:         cols,
+		JustifyItems: AlignStretch,
+		AlignItems:   AlignStretch,
+		props:        make(map[Component]GridProps),
 	}
 }
 
-func (l *GridLayout) SetPosition(c Component, row, col int) {
+// NewTrackGridLayout creates a grid from explicit track definitions.
+func NewTrackGridLayout(rows, cols []Track) *GridLayout {
+	l := NewGridLayout(len(rows), len(cols))
+	l.RowTracks = rows
+	l.ColTracks = cols
+	return l
+}
+
+// Props returns the grid properties of c, or the defaults if none were set.
+func (l *GridLayout) Props(c Component) GridProps {
+	if p, ok := l.props[c]; ok {
+		return p
+	}
+	return DefaultGridProps()
+}
+
+func (l *GridLayout) SetProps(c Component, p GridProps) {
 	if l.props == nil {
 		l.props = make(map[Component]GridProps)
 	}
-	p := l.props[c]
+	l.props[c] = p
+}
+
+func (l *GridLayout) SetPosition(c Component, row, col int) {
+	p := l.Props(c)
 	p.Row = row
 	p.Col = col
-	l.props[c] = p
+	l.SetProps(c, p)
 }
 
 func (l *GridLayout) SetSpan(c Component, rowSpan, colSpan int) {
-	if l.props == nil {
-		l.props = make(map[Component]GridProps)
-	}
-	p := l.props[c]
+	p := l.Props(c)
 	p.RowSpan = rowSpan
 	p.ColSpan = colSpan
-	l.props[c] = p
+	l.SetProps(c, p)
+}
+
+// SetAlign overrides JustifyItems/AlignItems for one child.
+func (l *GridLayout) SetAlign(c Component, h, v FlexAlign) {
+	p := l.Props(c)
+	p.HAlign = h
+	p.VAlign = v
+	l.SetProps(c, p)
+}
+
+// SetAreas defines named areas like CSS grid-template-areas. Each string
+// is one row of whitespace-separated cell names; "." marks an empty cell.
+// Every name must form a rectangle.
+func (l *GridLayout) SetAreas(rows ...string) error {
+	areas := make(map[string]gridArea)
+	width := -1
+	for r, row := range rows {
+		cells := strings.Fields(row)
+		if width >= 0 && len(cells) != width {
+			return fmt.Errorf("layout: grid area row %d has %d cells, want %d", r, len(cells), width)
+		}
+		width = len(cells)
+		for c, name := range cells {
+			if name == "." {
+				continue
+			}
+			a, ok := areas[name]
+			if !ok {
+				areas[name] = gridArea{row: r, col: c, rowSpan: 1, colSpan: 1}
+				continue
+			}
+			// Extend the area; validated as a rectangle below
+			if c >= a.col+a.colSpan {
+				a.colSpan = c - a.col + 1
+			}
+			if r >= a.row+a.rowSpan {
+				a.rowSpan = r - a.row + 1
+			}
+			areas[name] = a
+		}
+	}
+
+	for name, a := range areas {
+		for r := a.row; r < a.row+a.rowSpan; r++ {
+			cells := strings.Fields(rows[r])
+			for c := a.col; c < a.col+a.colSpan; c++ {
+				if cells[c] != name {
+					return fmt.Errorf("layout: grid area %q is not rectangular", name)
+				}
+			}
+		}
+	}
+
+	l.areas = areas
+	return nil
+}
+
+// SetArea places c in a named area defined by SetAreas. An unknown name
+// is an error, and c is left as it was. A child whose area no longer
+// exists when the grid is arranged, e.g. after SetAreas changed the
+// areas, is auto-placed.
+func (l *GridLayout) SetArea(c Component, name string) error {
+	if _, ok := l.areas[name]; !ok {
+		return fmt.Errorf("layout: unknown grid area %q", name)
+	}
+	p := l.Props(c)
+	p.Area = name
+	l.SetProps(c, p)
+	return nil
+}
+
+// gridItem is a child with its resolved placement.
+type gridItem struct {
+	comp     Component
+	props    GridProps
+	hints    Hints
+	row, col int
+	prefW    int32 // including margins
+	prefH    int32
+	rowSpan  int
+	colSpan  int
+}
+
+// gridTrack is a track being sized.
+type gridTrack struct {
+	def   Track
+	base  float64
+	limit float64
+	pos   float64
+}
+
+func (l *GridLayout) gaps() (rowGap, colGap int32) {
+	rowGap, colGap = l.RowGap, l.ColGap
+	if rowGap == 0 {
+		rowGap = l.Spacing
+	}
+	if colGap == 0 {
+		colGap = l.Spacing
+	}
+	return rowGap, colGap
+}
+
+func (l *GridLayout) explicitTracks(tracks []Track, count int) []Track {
+	if len(tracks) > 0 {
+		return tracks
+	}
+	// Legacy uniform grid: equal shares regardless of content
+	return Tracks(count, MinMax(Px(0), Fr(1)))
 }
 
 func (l *GridLayout) Arrange(container Container) {
-	bounds := container.GetBounds()
-	width := bounds.Width - 2*l.Padding
-	height := bounds.Height - 2*l.Padding
-	startX := bounds.X + l.Padding
-	startY := bounds.Y + l.Padding
+	content := contentRect(container, l.Padding)
+	width, height := content.Width, content.Height
+	startX, startY := content.X, content.Y
 
-	if l.Rows == 0 || l.Cols == 0 {
+	rowDefs := l.explicitTracks(l.RowTracks, l.Rows)
+	colDefs := l.explicitTracks(l.ColTracks, l.Cols)
+
+	items, numRows, numCols := l.place(container, len(rowDefs), len(colDefs))
+	if len(items) == 0 || numRows == 0 || numCols == 0 {
 		return
 	}
 
-	// Calculate cell size (Uniform)
-	// Avoid divide by zero
-	if l.Cols == 0 { l.Cols = 1 }
-	if l.Rows == 0 { l.Rows = 1 }
-	
-	cellW := (width - int32(l.Cols-1)*l.Spacing) / int32(l.Cols)
-	cellH := (height - int32(l.Rows-1)*l.Spacing) / int32(l.Rows)
+	rows := makeTracks(rowDefs, numRows, l.AutoRows)
+	cols := makeTracks(colDefs, numCols, l.AutoCols)
+	rowGap, colGap := l.gaps()
 
-	if cellW < 0 {
-		cellW = 0
+	colItems := make([]trackContent, len(items))
+	rowItems := make([]trackContent, len(items))
+	for i, it := range items {
+		colItems[i] = trackContent{start: it.col, span: it.colSpan, size: float64(it.prefW)}
+		rowItems[i] = trackContent{start: it.row, span: it.rowSpan, size: float64(it.prefH)}
 	}
-	if cellH < 0 {
-		cellH = 0
+	sizeTracks(cols, colItems, float64(width), float64(colGap))
+	sizeTracks(rows, rowItems, float64(height), float64(rowGap))
+
+	for _, it := range items {
+		x0 := cols[it.col].pos
+		x1 := cols[it.col+it.colSpan-1].pos + cols[it.col+it.colSpan-1].base
+		y0 := rows[it.row].pos
+		y1 := rows[it.row+it.rowSpan-1].pos + rows[it.row+it.rowSpan-1].base
+
+		hAlign := pickAlign(it.props.HAlign, it.hints.HAlign, l.JustifyItems)
+		vAlign := pickAlign(it.props.VAlign, it.hints.VAlign, l.AlignItems)
+
+		x, y := roundEdge(x0), roundEdge(y0)
+		slot := Rect{X: startX + x, Y: startY + y, Width: roundEdge(x1) - x, Height: roundEdge(y1) - y}
+		placeInSlot(it.comp, it.hints, slot, hAlign, vAlign)
 	}
+}
 
+// place resolves the cell of every visible child as CSS Grid does:
+// explicit positions and named areas first, then children with a fixed
+// row into the first free columns of that row, then row-major
+// auto-placement into free cells.
+func (l *GridLayout) place(container Container, explicitRows, explicitCols int) ([]*gridItem, int, int) {
+	numCols := explicitCols
+	for _, a := range l.areas {
+		if a.col+a.colSpan > numCols {
+			numCols = a.col + a.colSpan
+		}
+	}
+
+	var placed, rowFixed, auto []*gridItem
 	for _, child := range container.GetChildren() {
 		if !child.IsVisible() {
 			continue
 		}
+		p := l.Props(child)
+		hints := HintsOf(child)
+		w, h := outerSize(child, hints)
+		it := &gridItem{comp: child, props: p, hints: hints, row: p.Row, col: p.Col, prefW: w, prefH: h,
+			rowSpan: max(p.RowSpan, 1), colSpan: max(p.ColSpan, 1)}
+
+		if a, ok := l.areas[p.Area]; ok && p.Area != "" {
+			it.row, it.col, it.rowSpan, it.colSpan = a.row, a.col, a.rowSpan, a.colSpan
+		}
+		if it.row >= 0 && it.col >= 0 {
+			if it.col+it.colSpan > numCols {
+				numCols = it.col + it.colSpan
+			}
+			placed = append(placed, it)
+		} else if it.row >= 0 {
+			rowFixed = append(rowFixed, it)
+		} else {
+			auto = append(auto, it)
+		}
+	}
+	if numCols == 0 {
+		numCols = 1
+	}
+
+	occupied := map[[2]int]bool{}
+	mark := func(it *gridItem) {
+		for r := it.row; r < it.row+it.rowSpan; r++ {
+			for c := it.col; c < it.col+it.colSpan; c++ {
+				occupied[[2]int{r, c}] = true
+			}
+		}
+	}
+	fits := func(row, col, rowSpan, colSpan int) bool {
+		for r := row; r < row+rowSpan; r++ {
+			for c := col; c < col+colSpan; c++ {
+				if occupied[[2]int{r, c}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for _, it := range placed {
+		mark(it)
+	}
+
+	// Children with a fixed row go after those before them in the same
+	// row; if their row is full they are auto-placed like the others
+	rowCursor := map[int]int{}
+	var fitted []*gridItem
+	for _, it := range rowFixed {
+		if it.colSpan > numCols {
+			it.colSpan = numCols
+		}
+		it.col = -1
+		for c := rowCursor[it.row]; c+it.colSpan <= numCols; c++ {
+			if fits(it.row, c, it.rowSpan, it.colSpan) {
+				it.col = c
+				break
+			}
+		}
+		if it.col < 0 {
+			it.row = -1
+			auto = append(auto, it)
+			continue
+		}
+		rowCursor[it.row] = it.col + it.colSpan
+		mark(it)
+		fitted = append(fitted, it)
+	}
+
+	// Sparse auto-placement: the cursor only moves forward
+	curRow, curCol := 0, 0
+	for _, it := range auto {
+		if it.colSpan > numCols {
+			it.colSpan = numCols
+		}
+		if it.col >= 0 {
+			// Column fixed, find the first free row
+			if it.col+it.colSpan > numCols {
+				it.col = numCols - it.colSpan
+			}
+			it.row = 0
+			for !fits(it.row, it.col, it.rowSpan, it.colSpan) {
+				it.row++
+			}
+		} else {
+			for {
+				if curCol+it.colSpan > numCols {
+					curRow++
+					curCol = 0
+					continue
+				}
+				if fits(curRow, curCol, it.rowSpan, it.colSpan) {
+					break
+				}
+				curCol++
+			}
+			it.row, it.col = curRow, curCol
+			curCol += it.colSpan
+		}
+		mark(it)
+	}
+
+	numRows := explicitRows
+	items := append(append(placed, fitted...), auto...)
+	for _, it := range items {
+		if it.row+it.rowSpan > numRows {
+			numRows = it.row + it.rowSpan
+		}
+	}
+	return items, numRows, numCols
+}
+
+func makeTracks(defs []Track, n int, implicit *Track) []*gridTrack {
+	auto := Auto()
+	if implicit != nil {
+		auto = *implicit
+	}
+	tracks := make([]*gridTrack, n)
+	for i := range tracks {
+		def := auto
+		if i < len(defs) {
+			def = defs[i]
+		}
+		tracks[i] = &gridTrack{def: def}
+	}
+	return tracks
+}
+
+// trackContent is an item's contribution to one axis.
+type trackContent struct {
+	start, span int
+	size        float64
+}
+
+// sizeTracks is a simplified version of the CSS Grid track sizing
+// algorithm: intrinsic sizes, maximize, expand flexible tracks, then
+// stretch auto tracks. Track positions are filled in at the end.
+func sizeTracks(tracks []*gridTrack, items []trackContent, avail, gap float64) {
+	if avail < 0 {
+		avail = 0
+	}
+	space := avail - gap*float64(len(tracks)-1)
+
+	// 1. Initialize base sizes and growth limits
+	for _, t := range tracks {
+		t.base, t.limit = 0, 0
+		if t.def.Min.Kind == TrackFixed {
+			t.base = t.def.Min.Value
+		}
+		switch t.def.Max.Kind {
+		case TrackFixed:
+			t.limit = t.def.Max.Value
+		case TrackFr:
+			t.limit = math.Inf(1)
+		}
+	}
 
-		p, ok := l.props[child]
-		if !ok {
-			// If not positioned, maybe auto-flow?
-			// For now, skip or put at 0,0
-			p = GridProps{Row: 0, Col: 0, RowSpan: 1, ColSpan: 1}
+	// 2. Intrinsic contributions, single-span items first
+	for _, it := range items {
+		if it.span != 1 {
+			continue
+		}
+		t := tracks[it.start]
+		if t.def.Min.Kind == TrackAuto && it.size > t.base {
+			t.base = it.size
 		}
-		if p.RowSpan == 0 {
-			p.RowSpan = 1
+		if t.def.Max.Kind == TrackAuto && it.size > t.limit {
+			t.limit = it.size
 		}
-		if p.ColSpan == 0 {
-			p.ColSpan = 1
+	}
+	for _, it := range items {
+		if it.span == 1 {
+			continue
+		}
+		span := tracks[it.start : it.start+it.span]
+		spanSize := gap * float64(it.span-1)
+		var grow []*gridTrack
+		flexible := false
+		for _, t := range span {
+			spanSize += t.base
+			if t.def.Max.Kind == TrackFr {
+				flexible = true
+			}
+			if t.def.Min.Kind == TrackAuto {
+				grow = append(grow, t)
+			}
+		}
+		// Items spanning flexible tracks come last
+		if flexible || len(grow) == 0 || it.size <= spanSize {
+			continue
+		}
+		extra := (it.size - spanSize) / float64(len(grow))
+		for _, t := range grow {
+			t.base += extra
+		}
+	}
+	// Items spanning flexible tracks grow only those with an auto minimum,
+	// in proportion to their fr (CSS §11.5 step 4)
+	for _, it := range items {
+		if it.span == 1 {
+			continue
 		}
+		span := tracks[it.start : it.start+it.span]
+		spanSize := gap * float64(it.span-1)
+		var grow []*gridTrack
+		sumFr := 0.0
+		for _, t := range span {
+			spanSize += t.base
+			if t.def.Max.Kind == TrackFr && t.def.Min.Kind == TrackAuto {
+				grow = append(grow, t)
+				sumFr += t.def.Max.Value
+			}
+		}
+		if len(grow) == 0 || it.size <= spanSize {
+			continue
+		}
+		for _, t := range grow {
+			if sumFr > 0 {
+				t.base += (it.size - spanSize) * t.def.Max.Value / sumFr
+			} else {
+				t.base += (it.size - spanSize) / float64(len(grow))
+			}
+		}
+	}
+	for _, t := range tracks {
+		if t.limit < t.base {
+			t.limit = t.base
+		}
+	}
 
-		// Calculate position
-		x := startX + int32(p.Col)*(cellW+l.Spacing)
-		y := startY + int32(p.Row)*(cellH+l.Spacing)
+	// 3. Maximize tracks up to their growth limits
+	for {
+		free := space
+		var growable []*gridTrack
+		for _, t := range tracks {
+			free -= t.base
+			if !math.IsInf(t.limit, 1) && t.limit > t.base {
+				growable = append(growable, t)
+			}
+		}
+		if free <= 0.5 || len(growable) == 0 {
+			break
+		}
+		share := free / float64(len(growable))
+		for _, t := range growable {
+			t.base = math.Min(t.limit, t.base+share)
+		}
+	}
 
-		// Calculate size
-		w := int32(p.ColSpan)*cellW + int32(p.ColSpan-1)*l.Spacing
-		h := int32(p.RowSpan)*cellH + int32(p.RowSpan-1)*l.Spacing
+	// 4. Expand flexible tracks
+	hasFlex := false
+	for _, t := range tracks {
+		if t.def.Max.Kind == TrackFr {
+			hasFlex = true
+		}
+	}
+	if hasFlex {
+		inflexible := map[*gridTrack]bool{}
+		for {
+			leftover := space
+			sumFr := 0.0
+			for _, t := range tracks {
+				if t.def.Max.Kind != TrackFr || inflexible[t] {
+					leftover -= t.base
+				} else {
+					sumFr += t.def.Max.Value
+				}
+			}
+			if sumFr <= 0 {
+				break
+			}
+			if sumFr < 1 {
+				sumFr = 1
+			}
+			frSize := math.Max(leftover, 0) / sumFr
+			restart := false
+			for _, t := range tracks {
+				if t.def.Max.Kind == TrackFr && !inflexible[t] && t.base > frSize*t.def.Max.Value {
+					inflexible[t] = true
+					restart = true
+				}
+			}
+			if restart {
+				continue
+			}
+			for _, t := range tracks {
+				if t.def.Max.Kind == TrackFr && !inflexible[t] {
+					t.base = frSize * t.def.Max.Value
+				}
+			}
+			break
+		}
+	} else {
+		// 5. Stretch auto tracks into what is left
+		free := space
+		var autos []*gridTrack
+		for _, t := range tracks {
+			free -= t.base
+			if t.def.Max.Kind == TrackAuto {
+				autos = append(autos, t)
+			}
+		}
+		if free > 0 && len(autos) > 0 {
+			for _, t := range autos {
+				t.base += free / float64(len(autos))
+			}
+		}
+	}
 
-		child.SetBounds(x, y, w, h)
+	pos := 0.0
+	for _, t := range tracks {
+		t.pos = pos
+		pos += t.base + gap
 	}
 }
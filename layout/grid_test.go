@@ -0,0 +1,67 @@
+package layout
+
+import "testing"
+
+func TestGridAutoPlacement(t *testing.T) {
+	l := NewTrackGridLayout(Tracks(2, Px(20)), Tracks(3, Px(100)))
+	var boxes []*box
+	p := &panel{bounds: Rect{0, 0, 300, 60}}
+	for _, row := range []int{1, -1, 0, 1, 0} {
+		b := &box{w: 10, h: 20}
+		l.SetPosition(b, row, -1)
+		boxes = append(boxes, b)
+		p.children = append(p.children, b)
+	}
+	l.SetSpan(boxes[4], 1, 3) // Does not fit in row 0 any more
+
+	l.Arrange(p)
+	want := []Rect{
+		{0, 20, 100, 20},  // Row 1 first
+		{100, 0, 100, 20}, // Auto, after the fixed rows
+		{0, 0, 100, 20},   // Row 0 first
+		{100, 20, 100, 20},
+		{0, 40, 300, 20}, // Auto-placed in a new row
+	}
+	for i, b := range boxes {
+		if b.bounds != want[i] {
+			t.Errorf("item %d: got %v, want %v", i, b.bounds, want[i])
+		}
+	}
+}
+
+func TestGridSpanningFlexibleTracks(t *testing.T) {
+	l := NewTrackGridLayout([]Track{Auto()}, []Track{Px(50), Fr(1), Fr(3)})
+	wide := &box{w: 460, h: 20}
+	small := &box{w: 10, h: 20}
+	l.SetPosition(wide, 0, 0)
+	l.SetSpan(wide, 1, 3)
+	l.SetPosition(small, 1, 1)
+	p := &panel{bounds: Rect{0, 0, 200, 40}, children: []Component{wide, small}}
+
+	// The fr tracks lack 400 px beyond the 50 px track and the small
+	// item, split 1:3
+	l.Arrange(p)
+	if want := (Rect{0, 0, 460, 20}); wide.bounds != want {
+		t.Errorf("spanning item: got %v, want %v", wide.bounds, want)
+	}
+	if want := (Rect{50, 20, 110, 20}); small.bounds != want {
+		t.Errorf("item in the 1fr track: got %v, want %v", small.bounds, want)
+	}
+}
+
+func TestGridAreas(t *testing.T) {
+	l := NewGridLayout(0, 0)
+	if err := l.SetAreas("head head", "side main"); err != nil {
+		t.Fatal(err)
+	}
+	b := &box{}
+	if err := l.SetArea(b, "main"); err != nil {
+		t.Error(err)
+	}
+	if err := l.SetArea(b, "footer"); err == nil {
+		t.Error("unknown area accepted")
+	}
+	if got := l.Props(b).Area; got != "main" {
+		t.Errorf("area %q after an unknown one, want main", got)
+	}
+}
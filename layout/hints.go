@@ -0,0 +1,149 @@
+package layout
+
+// Insets are per-side distances, used for padding and margins.
+type Insets struct {
+	Top, Right, Bottom, Left int32
+}
+
+// UniformInsets returns insets of v on every side.
+func UniformInsets(v int32) Insets {
+	return Insets{Top: v, Right: v, Bottom: v, Left: v}
+}
+
+// SymmetricInsets returns insets of vertical on top/bottom and horizontal
+// on left/right.
+func SymmetricInsets(vertical, horizontal int32) Insets {
+	return Insets{Top: vertical, Right: horizontal, Bottom: vertical, Left: horizontal}
+}
+
+func (i Insets) Horizontal() int32 { return i.Left + i.Right }
+func (i Insets) Vertical() int32   { return i.Top + i.Bottom }
+
+// Shrink returns r reduced by the insets.
+func (i Insets) Shrink(r Rect) Rect {
+	r.X += i.Left
+	r.Y += i.Top
+	r.Width -= i.Horizontal()
+	r.Height -= i.Vertical()
+	if r.Width < 0 {
+		r.Width = 0
+	}
+	if r.Height < 0 {
+		r.Height = 0
+	}
+	return r
+}
+
+// Hints are the layout properties a component carries itself. Layouts
+// honour them in addition to their own per-child properties.
+//
+// Margin is space kept free around the component, Padding is space
+// inside a container kept free of children, HAlign/VAlign position the
+// component inside the slot a layout gives it (AlignAuto lets the layout
+// decide) and the Min/Max sizes clamp its bounds (0 means unconstrained).
+type Hints struct {
+	Margin    Insets
+	Padding   Insets
+	HAlign    FlexAlign
+	VAlign    FlexAlign
+	MinWidth  int32
+	MinHeight int32
+	MaxWidth  int32
+	MaxHeight int32
+}
+
+// Hinted is implemented by components that provide Hints.
+// component.BaseComponent implements it for every built-in component.
+type Hinted interface {
+	LayoutHints() Hints
+}
+
+// HintsOf returns the hints of c, or neutral hints if it has none.
+func HintsOf(c interface{}) Hints {
+	if h, ok := c.(Hinted); ok {
+		return h.LayoutHints()
+	}
+	return Hints{HAlign: AlignAuto, VAlign: AlignAuto}
+}
+
+func clampSize(v, min, max int32) int32 {
+	if max > 0 && v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
+
+// ClampWidth applies MinWidth/MaxWidth to w.
+func (h Hints) ClampWidth(w int32) int32 { return clampSize(w, h.MinWidth, h.MaxWidth) }
+
+// ClampHeight applies MinHeight/MaxHeight to v.
+func (h Hints) ClampHeight(v int32) int32 { return clampSize(v, h.MinHeight, h.MaxHeight) }
+
+// contentRect is the area of container available to children: its bounds
+// minus the layout's uniform padding and the container's own padding.
+func contentRect(container Container, padding int32) Rect {
+	r := UniformInsets(padding).Shrink(container.GetBounds())
+	return HintsOf(container).Padding.Shrink(r)
+}
+
+// preferredSize returns the clamped preferred size of c, falling back to
+// its current bounds when the preferred size is unknown.
+func preferredSize(c Component, hints Hints) (int32, int32) {
+	w, h := c.GetPreferredSize()
+	if w <= 0 {
+		w = c.GetBounds().Width
+	}
+	if h <= 0 {
+		h = c.GetBounds().Height
+	}
+	return hints.ClampWidth(w), hints.ClampHeight(h)
+}
+
+// outerSize is the preferred size of c including its margins.
+func outerSize(c Component, hints Hints) (int32, int32) {
+	w, h := preferredSize(c, hints)
+	return w + hints.Margin.Horizontal(), h + hints.Margin.Vertical()
+}
+
+// pickAlign returns the first alignment that is not AlignAuto, so callers
+// can list per-child layout properties, component hints and layout
+// defaults in order of precedence.
+func pickAlign(aligns ...FlexAlign) FlexAlign {
+	for _, a := range aligns {
+		if a != AlignAuto {
+			return a
+		}
+	}
+	return AlignStretch
+}
+
+// placeInSlot sets the bounds of c inside slot, which includes its margins.
+// Stretching is still limited by the max size.
+func placeInSlot(c Component, hints Hints, slot Rect, hAlign, vAlign FlexAlign) {
+	inner := hints.Margin.Shrink(slot)
+	prefW, prefH := preferredSize(c, hints)
+
+	x, w := placeSpan(inner.X, inner.Width, prefW, hints.MinWidth, hints.MaxWidth, hAlign)
+	y, h := placeSpan(inner.Y, inner.Height, prefH, hints.MinHeight, hints.MaxHeight, vAlign)
+	c.SetBounds(x, y, w, h)
+}
+
+// placeSpan positions a length of size pref inside [start, start+avail).
+func placeSpan(start, avail, pref, min, max int32, align FlexAlign) (int32, int32) {
+	size := pref
+	if align == AlignStretch || align == AlignAuto || size > avail {
+		size = avail
+	}
+	size = clampSize(size, min, max)
+
+	switch align {
+	case AlignEnd:
+		return start + avail - size, size
+	case AlignCenter:
+		return start + (avail-size)/2, size
+	}
+	return start, size
+}
@@ -33,25 +33,21 @@ type VBoxLayout struct {
 }
 
 func (l *VBoxLayout) Arrange(container Container) {
-	bounds := container.GetBounds()
-	x := bounds.X + l.Padding
-	y := bounds.Y + l.Padding
-	width := bounds.Width - 2*l.Padding
+	content := contentRect(container, l.Padding)
+	y := content.Y
 
 	for _, child := range container.GetChildren() {
 		if !child.IsVisible() {
 			continue
 		}
 
-		// Use preferred height
-		_, prefH := child.GetPreferredSize()
-		// If preferred height is 0, use current height (simplistic)
-		if prefH <= 0 {
-			prefH = child.GetBounds().Height
-		}
+		// Use preferred height (falls back to the current height)
+		hints := HintsOf(child)
+		_, outerH := outerSize(child, hints)
 
-		child.SetBounds(x, y, width, prefH)
-		y += prefH + int32(l.Spacing)
+		slot := Rect{X: content.X, Y: y, Width: content.Width, Height: outerH}
+		placeInSlot(child, hints, slot, pickAlign(hints.HAlign, AlignStretch), AlignStretch)
+		y += outerH + int32(l.Spacing)
 	}
 }
 
@@ -62,22 +58,19 @@ type HBoxLayout struct {
 }
 
 func (l *HBoxLayout) Arrange(container Container) {
-	bounds := container.GetBounds()
-	x := bounds.X + l.Padding
-	y := bounds.Y + l.Padding
-	height := bounds.Height - 2*l.Padding
+	content := contentRect(container, l.Padding)
+	x := content.X
 
 	for _, child := range container.GetChildren() {
 		if !child.IsVisible() {
 			continue
 		}
 
-		prefW, _ := child.GetPreferredSize()
-		if prefW <= 0 {
-			prefW = child.GetBounds().Width
-		}
+		hints := HintsOf(child)
+		outerW, _ := outerSize(child, hints)
 
-		child.SetBounds(x, y, prefW, height)
-		x += prefW + int32(l.Spacing)
+		slot := Rect{X: x, Y: content.Y, Width: outerW, Height: content.Height}
+		placeInSlot(child, hints, slot, AlignStretch, pickAlign(hints.VAlign, AlignStretch))
+		x += outerW + int32(l.Spacing)
 	}
 }
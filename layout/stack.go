@@ -0,0 +1,54 @@
+package layout
+
+// StackLayout places all children on top of each other inside the
+// container, e.g. for overlays and badges. Later children are drawn above
+// earlier ones.
+type StackLayout struct {
+	HAlign  FlexAlign
+	VAlign  FlexAlign
+	Padding int32
+
+	props map[Component]stackProps
+}
+
+type stackProps struct {
+	hAlign, vAlign FlexAlign
+}
+
+// NewStackLayout creates a stack layout with the default alignment of its
+// children. Use AlignStretch to make them fill the container.
+func NewStackLayout(hAlign, vAlign FlexAlign) *StackLayout {
+	return &StackLayout{
+		HAlign: hAlign,
+		VAlign: vAlign,
+		props:  make(map[Component]stackProps),
+	}
+}
+
+// SetAlign overrides the alignment of one child. AlignAuto falls back to
+// the child's own alignment, then the layout's default.
+func (l *StackLayout) SetAlign(c Component, hAlign, vAlign FlexAlign) {
+	if l.props == nil {
+		l.props = make(map[Component]stackProps)
+	}
+	l.props[c] = stackProps{hAlign: hAlign, vAlign: vAlign}
+}
+
+func (l *StackLayout) Arrange(container Container) {
+	content := contentRect(container, l.Padding)
+
+	for _, child := range container.GetChildren() {
+		if !child.IsVisible() {
+			continue
+		}
+
+		hints := HintsOf(child)
+		p, ok := l.props[child]
+		if !ok {
+			p = stackProps{hAlign: AlignAuto, vAlign: AlignAuto}
+		}
+		placeInSlot(child, hints, content,
+			pickAlign(p.hAlign, hints.HAlign, l.HAlign),
+			pickAlign(p.vAlign, hints.VAlign, l.VAlign))
+	}
+}
@@ -1,6 +1,9 @@
 package render
 
 import (
+	"io"
+	"io/fs"
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 
@@ -9,9 +12,11 @@ import (
 
 var (
 	procAddFontResourceExW    = modgdi32.NewProc("AddFontResourceExW")
+	procAddFontMemResourceEx  = modgdi32.NewProc("AddFontMemResourceEx")
 	procRemoveFontResourceExW = modgdi32.NewProc("RemoveFontResourceExW")
 	procCreateFontW           = modgdi32.NewProc("CreateFontW")
 	procGetDeviceCaps         = modgdi32.NewProc("GetDeviceCaps")
+	procGetStockObject        = modgdi32.NewProc("GetStockObject")
 )
 
 const (
@@ -26,6 +31,7 @@ const (
 	DEFAULT_QUALITY     = 0
 	DEFAULT_PITCH       = 0
 	FF_DONTCARE         = 0
+	SYSTEM_FONT         = 13
 )
 
 type Font struct {
@@ -60,6 +66,48 @@ func LoadFontFile(path string) error {
 	return nil
 }
 
+// LoadFontData loads a font from memory, e.g. embedded in the executable.
+// Like LoadFontFile the font is private to the application. It returns
+// the family name to pass to NewFont, or "" if it cannot be read.
+func LoadFontData(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", syscall.EINVAL
+	}
+	var count uint32
+	handle, _, err := procAddFontMemResourceEx.Call(
+		uintptr(unsafe.Pointer(&data[0])),
+		uintptr(len(data)),
+		0,
+		uintptr(unsafe.Pointer(&count)),
+	)
+	if handle == 0 {
+		if err != nil && err != syscall.Errno(0) {
+			return "", err
+		}
+		return "", syscall.EINVAL
+	}
+	return FontFamily(data), nil
+}
+
+// LoadFontReader loads a font read from r, as LoadFontData.
+func LoadFontReader(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return LoadFontData(data)
+}
+
+// LoadFontFS loads a font from a file system, e.g. an embed.FS, as
+// LoadFontData.
+func LoadFontFS(fsys fs.FS, path string) (string, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return "", err
+	}
+	return LoadFontData(data)
+}
+
 // NewFont creates a new font with the given family name and size (in points).
 // If the font is not found, Windows will substitute it.
 func NewFont(name string, size int) *Font {
@@ -104,6 +152,41 @@ func (f *Font) Close() {
 	}
 }
 
+// The font of text drawn or measured without a font of its own; nil for
+// the system font.
+var defaultFont atomic.Pointer[Font]
+
+// SetDefaultFont sets the font used for text drawn or measured without a
+// font, e.g. the body font of a theme; nil restores the system font. It
+// is safe to call from any goroutine and takes effect from the next frame.
+func SetDefaultFont(font *Font) {
+	defaultFont.Store(font)
+}
+
+// DefaultFont returns the font set by SetDefaultFont, or nil.
+func DefaultFont() *Font {
+	return defaultFont.Load()
+}
+
+// selectDefaultFont selects the font of the renderer if it has one, else
+// the default font, e.g. at the start of a frame whatever the previous
+// frame left selected.
+func (c *Canvas) selectDefaultFont() {
+	if c.hDC == 0 {
+		return
+	}
+	font := c.baseFont
+	if font == nil {
+		font = defaultFont.Load()
+	}
+	if font != nil && font.hFont != 0 {
+		procSelectObject.Call(uintptr(c.hDC), uintptr(font.hFont))
+		return
+	}
+	stock, _, _ := procGetStockObject.Call(SYSTEM_FONT)
+	procSelectObject.Call(uintptr(c.hDC), stock)
+}
+
 // SetFont sets the current font for the renderer.
 // This font will be used for all subsequent text drawing operations.
 func (r *Renderer) SetFont(font *Font) {
@@ -116,22 +199,31 @@ func (r *Renderer) SetFont(font *Font) {
 	}
 }
 
-// SetFont sets the current font for the canvas.
+// SetFont sets the current font for the canvas, or the default font if
+// font is nil.
 // Note: This only sets it for the current frame/DC.
 // Use Renderer.SetFont to persist across resize.
 func (c *Canvas) SetFont(font *Font) {
-	if c.hDC == 0 || font == nil || font.hFont == 0 {
+	if font == nil {
+		c.selectDefaultFont()
+		return
+	}
+	if c.hDC == 0 || font.hFont == 0 {
 		return
 	}
 	procSelectObject.Call(uintptr(c.hDC), uintptr(font.hFont))
 }
 
 // MeasureText calculates the width and height of the given text with the specified font.
-// If font is nil, it uses the system default font.
+// If font is nil, it uses the default font (see SetDefaultFont).
 func MeasureText(text string, font *Font) (int32, int32) {
 	hdc, _, _ := procGetDC.Call(0)
 	defer procReleaseDC.Call(0, hdc)
 
+	if font == nil {
+		font = defaultFont.Load()
+	}
+
 	oldFont := uintptr(0)
 	if font != nil && font.hFont != 0 {
 		oldFont, _, _ = procSelectObject.Call(hdc, uintptr(font.hFont))
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// FontFamily returns the family name of the first face in TrueType or
+// OpenType data (or a collection), as NewFont expects it, or "" if it
+// cannot be read.
+func FontFamily(data []byte) string {
+	u16 := func(off int) int {
+		if off < 0 || off+2 > len(data) {
+			return -1
+		}
+		return int(binary.BigEndian.Uint16(data[off:]))
+	}
+	u32 := func(off int) int {
+		if off < 0 || off+4 > len(data) {
+			return -1
+		}
+		return int(binary.BigEndian.Uint32(data[off:]))
+	}
+
+	font := 0
+	if len(data) >= 16 && string(data[:4]) == "ttcf" {
+		font = u32(12) // First face of the collection
+	}
+	numTables := u16(font + 4)
+	name := -1
+	for i := 0; i < numTables; i++ {
+		rec := font + 12 + 16*i
+		if rec+16 > len(data) {
+			return ""
+		}
+		if string(data[rec:rec+4]) == "name" {
+			name = u32(rec + 8)
+			break
+		}
+	}
+	if name < 0 {
+		return ""
+	}
+
+	count, storage := u16(name+2), name+u16(name+4)
+	var mac string
+	for i := 0; i < count; i++ {
+		rec := name + 6 + 12*i
+		platform, encoding, language, id := u16(rec), u16(rec+2), u16(rec+4), u16(rec+6)
+		length, offset := u16(rec+8), u16(rec+10)
+		start := storage + offset
+		if id != 1 || length < 0 || start < 0 || start+length > len(data) {
+			continue
+		}
+		raw := data[start : start+length]
+		switch {
+		case platform == 3 && (encoding == 1 || encoding == 10 || encoding == 0):
+			chars := make([]uint16, len(raw)/2)
+			for j := range chars {
+				chars[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			family := string(utf16.Decode(chars))
+			if language == 0x0409 { // English (United States)
+				return family
+			}
+			if mac == "" {
+				mac = family
+			}
+		case platform == 1 && encoding == 0 && mac == "":
+			mac = string(raw)
+		}
+	}
+	return mac
+}
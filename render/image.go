@@ -0,0 +1,309 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Image is a bitmap in the pixel format of the canvas, ready to be drawn
+// with DrawImage.
+type Image struct {
+	Width, Height int32
+	Pix           []uint32 // ARGB, not premultiplied, row by row
+	// Opaque is set when no pixel is translucent, so that rows can be
+	// copied without blending. Clear it after making pixels translucent.
+	Opaque bool
+}
+
+func NewImage(width, height int32) *Image {
+	return &Image{Width: width, Height: height, Pix: make([]uint32, int(width)*int(height))}
+}
+
+// ImageFrom converts a decoded image.
+func ImageFrom(m image.Image) *Image {
+	b := m.Bounds()
+	nrgba, ok := m.(*image.NRGBA)
+	if !ok {
+		nrgba = image.NewNRGBA(b)
+		draw.Draw(nrgba, b, m, b.Min, draw.Src)
+	}
+	img := NewImage(int32(b.Dx()), int32(b.Dy()))
+	img.Opaque = true
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := nrgba.Pix[nrgba.PixOffset(b.Min.X, y):]
+		for x := 0; x < b.Dx(); x++ {
+			p := row[x*4 : x*4+4]
+			img.Pix[i] = uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+			if p[3] != 0xFF {
+				img.Opaque = false
+			}
+			i++
+		}
+	}
+	return img
+}
+
+// Bounds returns the rectangle of the whole image.
+func (img *Image) Bounds() image.Rectangle {
+	return image.Rect(0, 0, int(img.Width), int(img.Height))
+}
+
+// DrawImage draws the src rectangle of img into the dst rectangle of the
+// canvas, scaling with the nearest pixel if the sizes differ (use Resize
+// first for smooth scaling). Translucent pixels are blended; opaque rows
+// drawn at their size are copied directly.
+func (c *Canvas) DrawImage(img *Image, src, dst image.Rectangle) {
+	src = src.Intersect(img.Bounds())
+	if src.Empty() || dst.Empty() {
+		return
+	}
+	cl := c.clip()
+	x0, y0 := max(int32(dst.Min.X), cl.x0), max(int32(dst.Min.Y), cl.y0)
+	x1, y1 := min(int32(dst.Max.X), cl.x1), min(int32(dst.Max.Y), cl.y1)
+	if x0 >= x1 || y0 >= y1 {
+		return
+	}
+	sw, sh := src.Dx(), src.Dy()
+	dw, dh := dst.Dx(), dst.Dy()
+	scaled := sw != dw || sh != dh
+
+	// Source column of each destination column, sampling pixel centres
+	var cols []int
+	if scaled {
+		cols = make([]int, x1-x0)
+		for i := range cols {
+			dx := int(x0) + i - dst.Min.X
+			cols[i] = src.Min.X + (2*dx+1)*sw/(2*dw)
+		}
+	}
+
+	for y := y0; y < y1; y++ {
+		dy := int(y) - dst.Min.Y
+		sy := src.Min.Y + dy
+		if scaled {
+			sy = src.Min.Y + (2*dy+1)*sh/(2*dh)
+		}
+		srow := img.Pix[sy*int(img.Width) : (sy+1)*int(img.Width)]
+		drow := c.Buffer[y*c.Width+x0 : y*c.Width+x1]
+		if !scaled {
+			s := srow[src.Min.X+int(x0)-dst.Min.X:]
+			if img.Opaque {
+				copy(drow, s)
+				continue
+			}
+			for i, p := range s[:len(drow)] {
+				drow[i] = blendPixel(drow[i], p)
+			}
+			continue
+		}
+		for i, sx := range cols {
+			drow[i] = blendPixel(drow[i], srow[sx])
+		}
+	}
+}
+
+// blendPixel draws the ARGB pixel p over dst.
+func blendPixel(dst, p uint32) uint32 {
+	switch a := p >> 24; a {
+	case 0xFF:
+		return p
+	case 0:
+		return dst
+	default:
+		return blend(dst, p, a)
+	}
+}
+
+// Filter is a resampling method for Resize.
+type Filter int
+
+const (
+	Nearest  Filter = iota // Blocky, for pixel art
+	Bilinear               // Smooth and fast
+	Bicubic                // Sharper than bilinear
+	Lanczos                // Sharpest, and slowest
+)
+
+// support returns the radius of the filter kernel, in source pixels when
+// enlarging.
+func (f Filter) support() float64 {
+	switch f {
+	case Bicubic:
+		return 2
+	case Lanczos:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func (f Filter) kernel(x float64) float64 {
+	x = math.Abs(x)
+	switch f {
+	case Bicubic: // Catmull-Rom
+		switch {
+		case x < 1:
+			return 1.5*x*x*x - 2.5*x*x + 1
+		case x < 2:
+			return -0.5*x*x*x + 2.5*x*x - 4*x + 2
+		}
+		return 0
+	case Lanczos:
+		switch {
+		case x == 0:
+			return 1
+		case x < 3:
+			px := math.Pi * x
+			return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+		}
+		return 0
+	default: // Bilinear
+		return math.Max(0, 1-x)
+	}
+}
+
+// tap is the weight of a source pixel in a destination pixel.
+type tap struct {
+	index  int
+	weight float32
+}
+
+// resampleTaps returns the source pixels contributing to each of n
+// destination pixels, resampling a line of srcLen pixels. When shrinking,
+// the kernel widens so that every source pixel counts.
+func resampleTaps(srcLen, n int, f Filter) [][]tap {
+	scale := float64(srcLen) / float64(n)
+	widen := math.Max(1, scale)
+	radius := f.support() * widen
+	taps := make([][]tap, n)
+	for i := range taps {
+		center := (float64(i) + 0.5) * scale
+		lo := int(math.Floor(center - radius))
+		hi := int(math.Ceil(center + radius))
+		var sum float64
+		var ts []tap
+		for j := lo; j <= hi; j++ {
+			w := f.kernel((float64(j) + 0.5 - center) / widen)
+			if w == 0 {
+				continue
+			}
+			ts = append(ts, tap{min(max(j, 0), srcLen-1), float32(w)})
+			sum += w
+		}
+		for k := range ts {
+			ts[k].weight /= float32(sum)
+		}
+		taps[i] = ts
+	}
+	return taps
+}
+
+// Resize returns a copy of the image scaled to width x height.
+func (img *Image) Resize(width, height int32, f Filter) *Image {
+	out := NewImage(width, height)
+	out.Opaque = img.Opaque
+	if width <= 0 || height <= 0 || img.Width <= 0 || img.Height <= 0 {
+		return out
+	}
+	sw, sh := int(img.Width), int(img.Height)
+	dw, dh := int(width), int(height)
+	if f == Nearest {
+		for y := 0; y < dh; y++ {
+			row := img.Pix[(2*y+1)*sh/(2*dh)*sw:]
+			for x := 0; x < dw; x++ {
+				out.Pix[y*dw+x] = row[(2*x+1)*sw/(2*dw)]
+			}
+		}
+		return out
+	}
+
+	// Horizontal pass into premultiplied channels, then vertical
+	xtaps := resampleTaps(sw, dw, f)
+	ytaps := resampleTaps(sh, dh, f)
+	tmp := make([]float32, sh*dw*4)
+	for y := 0; y < sh; y++ {
+		row := img.Pix[y*sw : (y+1)*sw]
+		for x, ts := range xtaps {
+			var a, r, g, b float32
+			for _, t := range ts {
+				p := row[t.index]
+				pa := float32(p>>24) * t.weight
+				a += pa
+				r += float32(p>>16&0xFF) * pa
+				g += float32(p>>8&0xFF) * pa
+				b += float32(p&0xFF) * pa
+			}
+			o := (y*dw + x) * 4
+			tmp[o], tmp[o+1], tmp[o+2], tmp[o+3] = a, r, g, b
+		}
+	}
+	for y, ts := range ytaps {
+		for x := 0; x < dw; x++ {
+			var a, r, g, b float32
+			for _, t := range ts {
+				o := (t.index*dw + x) * 4
+				a += tmp[o] * t.weight
+				r += tmp[o+1] * t.weight
+				g += tmp[o+2] * t.weight
+				b += tmp[o+3] * t.weight
+			}
+			var p uint32
+			if a > 0.5 {
+				p = clampByte(a)<<24 | clampByte(r/a)<<16 | clampByte(g/a)<<8 | clampByte(b/a)
+			}
+			out.Pix[y*dw+x] = p
+		}
+	}
+	return out
+}
+
+func clampByte(v float32) uint32 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint32(v + 0.5)
+}
+
+// NinePatch cuts an image into a 3x3 grid by insets from its edges, to
+// draw frames of any size: the corners keep their size, the edges stretch
+// along their length and the centre in both directions.
+type NinePatch struct {
+	Image                    *Image
+	Left, Top, Right, Bottom int32
+}
+
+// DrawNinePatch draws n to fill dst. Corners shrink if dst is smaller than
+// they are.
+func (c *Canvas) DrawNinePatch(n *NinePatch, dst image.Rectangle) {
+	img := n.Image
+	if img == nil || dst.Empty() {
+		return
+	}
+	sx := [4]int{0, int(n.Left), int(img.Width - n.Right), int(img.Width)}
+	sy := [4]int{0, int(n.Top), int(img.Height - n.Bottom), int(img.Height)}
+	left, right := fitCorners(int(n.Left), int(n.Right), dst.Dx())
+	top, bottom := fitCorners(int(n.Top), int(n.Bottom), dst.Dy())
+	dx := [4]int{dst.Min.X, dst.Min.X + left, dst.Max.X - right, dst.Max.X}
+	dy := [4]int{dst.Min.Y, dst.Min.Y + top, dst.Max.Y - bottom, dst.Max.Y}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			c.DrawImage(img,
+				image.Rect(sx[col], sy[row], sx[col+1], sy[row+1]),
+				image.Rect(dx[col], dy[row], dx[col+1], dy[row+1]))
+		}
+	}
+}
+
+// fitCorners shrinks two corner sizes in proportion to fit in size.
+func fitCorners(a, b, size int) (int, int) {
+	if a+b <= size || a+b == 0 {
+		return a, b
+	}
+	a2 := a * size / (a + b)
+	return a2, size - a2
+}
@@ -0,0 +1,224 @@
+package render
+
+import (
+	"math"
+	"sort"
+)
+
+// Point is a position in canvas coordinates, with sub-pixel precision.
+type Point struct {
+	X, Y float64
+}
+
+// FillRule decides which parts of a self-intersecting polygon are inside.
+type FillRule int
+
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// Sub-scanlines per pixel row for anti-aliasing
+const polygonSubsamples = 4
+
+type polyEdge struct {
+	x0, y0, x1, y1 float64
+	dir            int // +1 downwards, -1 upwards
+}
+
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// FillPolygon fills one or more closed polygons (e.g. a shape and its
+// holes) with anti-aliased edges, blending with the alpha of color.
+func (c *Canvas) FillPolygon(rule FillRule, color uint32, polygons ...[]Point) {
+	var edges []polyEdge
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, poly := range polygons {
+		for i := range poly {
+			p, q := poly[i], poly[(i+1)%len(poly)]
+			if p.Y == q.Y || math.IsNaN(p.X+p.Y+q.X+q.Y) {
+				continue
+			}
+			e := polyEdge{p.X, p.Y, q.X, q.Y, 1}
+			if p.Y > q.Y {
+				e = polyEdge{q.X, q.Y, p.X, p.Y, -1}
+			}
+			edges = append(edges, e)
+			minY, maxY = math.Min(minY, e.y0), math.Max(maxY, e.y1)
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+
+	cl := c.clip()
+	y0 := max(cl.y0, int32(math.Floor(minY)))
+	y1 := min(cl.y1, int32(math.Ceil(maxY)))
+	width := cl.x1 - cl.x0
+	if width <= 0 {
+		return
+	}
+	coverage := make([]float64, width+1)
+	var xs []crossing
+	alpha := float64(color >> 24)
+
+	for y := y0; y < y1; y++ {
+		for i := range coverage {
+			coverage[i] = 0
+		}
+		touched := false
+		for s := 0; s < polygonSubsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/polygonSubsamples
+			xs = xs[:0]
+			for _, e := range edges {
+				if sy < e.y0 || sy >= e.y1 {
+					continue
+				}
+				x := e.x0 + (sy-e.y0)*(e.x1-e.x0)/(e.y1-e.y0)
+				xs = append(xs, crossing{x, e.dir})
+			}
+			if len(xs) < 2 {
+				continue
+			}
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+			winding := 0
+			for i := 0; i < len(xs)-1; i++ {
+				winding += xs[i].dir
+				inside := winding != 0
+				if rule == EvenOdd {
+					inside = (i+1)%2 == 1
+				}
+				if inside {
+					c.addSpan(coverage, xs[i].x-float64(cl.x0), xs[i+1].x-float64(cl.x0), 1.0/polygonSubsamples)
+					touched = true
+				}
+			}
+		}
+		if !touched {
+			continue
+		}
+		row := y * c.Width
+		for i := int32(0); i < width; i++ {
+			cov := coverage[i]
+			if cov <= 0 {
+				continue
+			}
+			a := uint32(math.Min(1, cov)*alpha + 0.5)
+			idx := row + cl.x0 + i
+			if c.Transparent {
+				c.Buffer[idx] = over(c.Buffer[idx], color, a)
+			} else {
+				c.Buffer[idx] = blend(c.Buffer[idx], color, a)
+			}
+		}
+	}
+}
+
+// addSpan adds coverage w to the pixels between a and b, with partial
+// coverage for the pixels they cut.
+func (c *Canvas) addSpan(coverage []float64, a, b, w float64) {
+	n := float64(len(coverage) - 1)
+	a, b = math.Max(0, a), math.Min(n, b)
+	if b <= a {
+		return
+	}
+	ia, ib := int(a), int(b)
+	if ia == ib {
+		coverage[ia] += (b - a) * w
+		return
+	}
+	coverage[ia] += (float64(ia+1) - a) * w
+	for i := ia + 1; i < ib; i++ {
+		coverage[i] += w
+	}
+	if ib < len(coverage) {
+		coverage[ib] += (b - float64(ib)) * w
+	}
+}
+
+// StrokeLine draws an anti-aliased line of any width.
+func (c *Canvas) StrokeLine(x0, y0, x1, y1, width float64, color uint32) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	// Half width along the normal
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	c.FillPolygon(NonZero, color, []Point{
+		{x0 + nx, y0 + ny}, {x1 + nx, y1 + ny}, {x1 - nx, y1 - ny}, {x0 - nx, y0 - ny},
+	})
+}
+
+// StrokePolyline draws connected anti-aliased lines through points, with
+// round joins.
+func (c *Canvas) StrokePolyline(points []Point, width float64, color uint32) {
+	for i := 0; i+1 < len(points); i++ {
+		c.StrokeLine(points[i].X, points[i].Y, points[i+1].X, points[i+1].Y, width, color)
+		if i > 0 && width > 1.5 {
+			c.FillEllipse(points[i].X, points[i].Y, width/2, width/2, color)
+		}
+	}
+}
+
+// FillEllipse fills an anti-aliased ellipse.
+func (c *Canvas) FillEllipse(cx, cy, rx, ry float64, color uint32) {
+	c.FillPolygon(NonZero, color, EllipsePoints(cx, cy, rx, ry, 0, 2*math.Pi))
+}
+
+// FillRoundRect fills a rectangle with its corners rounded to radius r,
+// with anti-aliased arcs. A radius of 0 fills a plain rectangle.
+func (c *Canvas) FillRoundRect(x, y, w, h, r int32, color uint32) {
+	if min(r, w/2, h/2) <= 0 {
+		c.FillRect(x, y, w, h, color)
+		return
+	}
+	c.FillPolygon(NonZero, color, roundRectPoints(float64(x), float64(y), float64(w), float64(h), float64(r)))
+}
+
+// DrawRoundRect draws the 1 pixel outline of the rectangle FillRoundRect
+// fills.
+func (c *Canvas) DrawRoundRect(x, y, w, h, r int32, color uint32) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if min(r, w/2, h/2) <= 0 {
+		c.FillRect(x, y, w, 1, color)
+		c.FillRect(x, y+h-1, w, 1, color)
+		c.FillRect(x, y+1, 1, h-2, color)
+		c.FillRect(x+w-1, y+1, 1, h-2, color)
+		return
+	}
+	fx, fy, fw, fh, fr := float64(x), float64(y), float64(w), float64(h), float64(r)
+	c.FillPolygon(EvenOdd, color,
+		roundRectPoints(fx, fy, fw, fh, fr),
+		roundRectPoints(fx+1, fy+1, fw-2, fh-2, fr-1))
+}
+
+// roundRectPoints returns the outline of a rounded rectangle, clockwise.
+func roundRectPoints(x, y, w, h, r float64) []Point {
+	r = math.Max(0, math.Min(r, math.Min(w, h)/2))
+	x1, y1 := x+w, y+h
+	var points []Point
+	points = append(points, EllipsePoints(x1-r, y+r, r, r, -math.Pi/2, 0)...)
+	points = append(points, EllipsePoints(x1-r, y1-r, r, r, 0, math.Pi/2)...)
+	points = append(points, EllipsePoints(x+r, y1-r, r, r, math.Pi/2, math.Pi)...)
+	points = append(points, EllipsePoints(x+r, y+r, r, r, math.Pi, 3*math.Pi/2)...)
+	return points
+}
+
+// EllipsePoints approximates the arc of an ellipse from angle start to
+// end (radians, clockwise on screen from the positive x axis) with points.
+func EllipsePoints(cx, cy, rx, ry, start, end float64) []Point {
+	n := int(math.Ceil(math.Abs(end-start) / (2 * math.Pi) * math.Max(16, math.Sqrt(math.Max(rx, ry))*12)))
+	n = max(n, 2)
+	points := make([]Point, n+1)
+	for i := 0; i <= n; i++ {
+		a := start + (end-start)*float64(i)/float64(n)
+		points[i] = Point{cx + rx*math.Cos(a), cy + ry*math.Sin(a)}
+	}
+	return points
+}
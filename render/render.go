@@ -21,6 +21,8 @@ var (
 	procSetTextColor          = modgdi32.NewProc("SetTextColor")
 	procSetBkMode             = modgdi32.NewProc("SetBkMode")
 	procGetTextExtentPoint32W = modgdi32.NewProc("GetTextExtentPoint32W")
+	procCreateRectRgn         = modgdi32.NewProc("CreateRectRgn")
+	procSelectClipRgn         = modgdi32.NewProc("SelectClipRgn")
 
 	procGetDC     = moduser32.NewProc("GetDC")
 	procReleaseDC = moduser32.NewProc("ReleaseDC")
@@ -36,6 +38,19 @@ type Canvas struct {
 	Width, Height int32
 	Buffer        []uint32 // ARGB
 	hDC           windows.Handle
+	// Transparent canvases keep the alpha of their pixels when shapes are
+	// blended on them, for drawing offscreen images such as SVG. The window
+	// canvas is opaque.
+	Transparent bool
+
+	// Clip rectangles, innermost last
+	clips []clipRect
+
+	baseFont *Font // Font of the renderer, selected instead of the default
+}
+
+type clipRect struct {
+	x0, y0, x1, y1 int32
 }
 
 func NewCanvas(width, height int32) *Canvas {
@@ -46,6 +61,62 @@ func NewCanvas(width, height int32) *Canvas {
 	}
 }
 
+// clip returns the current clip rectangle (the whole canvas if none).
+func (c *Canvas) clip() clipRect {
+	if len(c.clips) == 0 {
+		return clipRect{0, 0, c.Width, c.Height}
+	}
+	return c.clips[len(c.clips)-1]
+}
+
+// PushClip restricts drawing to the intersection of the current clip and
+// the given rectangle until the matching PopClip.
+func (c *Canvas) PushClip(x, y, w, h int32) {
+	cur := c.clip()
+	r := clipRect{max(x, cur.x0), max(y, cur.y0), min(x+w, cur.x1), min(y+h, cur.y1)}
+	if r.x1 < r.x0 {
+		r.x1 = r.x0
+	}
+	if r.y1 < r.y0 {
+		r.y1 = r.y0
+	}
+	c.clips = append(c.clips, r)
+	c.applyTextClip()
+}
+
+// PopClip restores the clip rectangle active before the last PushClip.
+func (c *Canvas) PopClip() {
+	if len(c.clips) == 0 {
+		return
+	}
+	c.clips = c.clips[:len(c.clips)-1]
+	c.applyTextClip()
+}
+
+// ClipBounds returns the current clip rectangle.
+func (c *Canvas) ClipBounds() (x, y, w, h int32) {
+	r := c.clip()
+	return r.x0, r.y0, r.x1 - r.x0, r.y1 - r.y0
+}
+
+// applyTextClip mirrors the clip rectangle into the DC, as text is drawn by GDI.
+func (c *Canvas) applyTextClip() {
+	if c.hDC == 0 {
+		return
+	}
+	if len(c.clips) == 0 {
+		procSelectClipRgn.Call(uintptr(c.hDC), 0)
+		return
+	}
+	r := c.clip()
+	rgn, _, _ := procCreateRectRgn.Call(uintptr(r.x0), uintptr(r.y0), uintptr(r.x1), uintptr(r.y1))
+	if rgn == 0 {
+		return
+	}
+	procSelectClipRgn.Call(uintptr(c.hDC), rgn)
+	procDeleteObject.Call(rgn)
+}
+
 func (c *Canvas) Clear(color uint32) {
 	for i := range c.Buffer {
 		c.Buffer[i] = color
@@ -146,27 +217,28 @@ func (c *Canvas) DrawLine(x0, y0, x1, y1 int32, color uint32) {
 // FillRect fills a rectangle with a solid color
 // color is 0xAARRGGBB
 func (c *Canvas) FillRect(x, y, w, h int32, color uint32) {
-	if x >= c.Width || y >= c.Height {
+	cl := c.clip()
+	if x >= cl.x1 || y >= cl.y1 {
 		return
 	}
-	if x+w < 0 || y+h < 0 {
+	if x+w < cl.x0 || y+h < cl.y0 {
 		return
 	}
 
 	// Clipping
-	if x < 0 {
-		w += x
-		x = 0
+	if x < cl.x0 {
+		w -= cl.x0 - x
+		x = cl.x0
 	}
-	if y < 0 {
-		h += y
-		y = 0
+	if y < cl.y0 {
+		h -= cl.y0 - y
+		y = cl.y0
 	}
-	if x+w > c.Width {
-		w = c.Width - x
+	if x+w > cl.x1 {
+		w = cl.x1 - x
 	}
-	if y+h > c.Height {
-		h = c.Height - y
+	if y+h > cl.y1 {
+		h = cl.y1 - y
 	}
 
 	for row := int32(0); row < h; row++ {
@@ -177,10 +249,52 @@ func (c *Canvas) FillRect(x, y, w, h int32, color uint32) {
 	}
 }
 
+// BlendRect draws a translucent rectangle over the existing pixels,
+// using the alpha of color (0xAARRGGBB).
+func (c *Canvas) BlendRect(x, y, w, h int32, color uint32) {
+	cl := c.clip()
+	x0, y0 := max(x, cl.x0), max(y, cl.y0)
+	x1, y1 := min(x+w, cl.x1), min(y+h, cl.y1)
+	a := color >> 24
+	for row := y0; row < y1; row++ {
+		start := row * c.Width
+		for col := x0; col < x1; col++ {
+			c.Buffer[start+col] = blend(c.Buffer[start+col], color, a)
+		}
+	}
+}
+
+// blend mixes src over dst with alpha a (0-255), keeping dst opaque.
+func blend(dst, src, a uint32) uint32 {
+	inv := 255 - a
+	r := ((src>>16&0xFF)*a + (dst>>16&0xFF)*inv) / 255
+	g := ((src>>8&0xFF)*a + (dst>>8&0xFF)*inv) / 255
+	b := ((src&0xFF)*a + (dst&0xFF)*inv) / 255
+	return 0xFF000000 | r<<16 | g<<8 | b
+}
+
+// over composites src with alpha a over dst, keeping the alpha of both,
+// for transparent canvases.
+func over(dst, src, a uint32) uint32 {
+	da := dst >> 24
+	if da == 0xFF {
+		return blend(dst, src, a)
+	}
+	outA := a + da*(255-a)/255
+	if outA == 0 {
+		return 0
+	}
+	mix := func(s, d uint32) uint32 {
+		return (s*a + d*da*(255-a)/255) / outA
+	}
+	return outA<<24 | mix(src>>16&0xFF, dst>>16&0xFF)<<16 | mix(src>>8&0xFF, dst>>8&0xFF)<<8 | mix(src&0xFF, dst&0xFF)
+}
+
 // SetPixel sets a pixel color at (x, y)
 // color is 0xAARRGGBB
 func (c *Canvas) SetPixel(x, y int32, color uint32) {
-	if x < 0 || x >= c.Width || y < 0 || y >= c.Height {
+	cl := c.clip()
+	if x < cl.x0 || x >= cl.x1 || y < cl.y0 || y >= cl.y1 {
 		return
 	}
 	idx := int(y)*int(c.Width) + int(x)
@@ -306,6 +420,8 @@ func (r *Renderer) Resize(width, height int32) {
 
 func (r *Renderer) BeginFrame() *Canvas {
 	r.mu.Lock()
+	r.canvas.baseFont = r.font
+	r.canvas.selectDefaultFont()
 	return r.canvas
 }
 
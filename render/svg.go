@@ -0,0 +1,878 @@
+package render
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVG is a parsed SVG drawing. Paths, basic shapes, groups, transforms,
+// solid fills and strokes are supported; gradients are drawn in their
+// first stop colour, and text, filters, masks and CSS style sheets are
+// ignored.
+type SVG struct {
+	Width, Height float64 // Intrinsic size in pixels, at most MaxSVGSize
+	viewBox       [4]float64
+	shapes        []svgShape
+}
+
+// MaxSVGSize caps the intrinsic width and height of an SVG, which come
+// from the document: larger drawings are scaled down to fit, so that an
+// untrusted file cannot ask for a huge image.
+const MaxSVGSize = 4096
+
+// svgSize returns a width or height attribute, or 0 if it is missing or
+// not a positive finite number.
+func svgSize(v string) float64 {
+	f, _ := parseLength(v)
+	if !(f > 0) || math.IsInf(f, 1) {
+		return 0
+	}
+	return f
+}
+
+// svgShape is a filled and/or stroked path, in viewBox units.
+type svgShape struct {
+	path        []pathSeg
+	fill        uint32 // ARGB, 0 for none
+	stroke      uint32
+	strokeWidth float64
+	rule        FillRule
+}
+
+type pathOp int
+
+const (
+	opMove pathOp = iota
+	opLine
+	opCubic
+	opClose
+)
+
+// pathSeg is a path command with absolute points: one for moves and
+// lines, two control points and the end for cubics.
+type pathSeg struct {
+	op  pathOp
+	pts [3]Point
+}
+
+// affine is the matrix [a c e; b d f].
+type affine [6]float64
+
+var identity = affine{1, 0, 0, 1, 0, 0}
+
+func (m affine) apply(p Point) Point {
+	return Point{m[0]*p.X + m[2]*p.Y + m[4], m[1]*p.X + m[3]*p.Y + m[5]}
+}
+
+// mul returns the transform applying n, then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		m[0]*n[0] + m[2]*n[1], m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3], m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4], m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+// scale returns how much the transform scales lengths, on average.
+func (m affine) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// svgStyle is the inherited presentation state of an element.
+type svgStyle struct {
+	fill, stroke               uint32
+	fillOpacity, strokeOpacity float64
+	opacity                    float64
+	strokeWidth                float64
+	rule                       FillRule
+	transform                  affine
+}
+
+// ParseSVG reads an SVG document.
+func ParseSVG(r io.Reader) (*SVG, error) {
+	s := &SVG{}
+	p := &svgParser{svg: s, gradients: map[string]uint32{}}
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+	root := false
+	styles := []svgStyle{{fill: 0xFF000000, fillOpacity: 1, strokeOpacity: 1, opacity: 1, strokeWidth: 1, transform: identity}}
+	skip := 0 // Depth inside elements whose content is not drawn
+	gradient := ""
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			attrs := svgAttrs(t.Attr)
+			name := t.Name.Local
+			if !root {
+				if name != "svg" {
+					return nil, errors.New("svg: not an SVG document")
+				}
+				root = true
+				s.init(attrs)
+			}
+			st := p.style(styles[len(styles)-1], attrs)
+			styles = append(styles, st)
+			switch name {
+			case "linearGradient", "radialGradient":
+				gradient = attrs["id"]
+			case "stop":
+				if _, ok := p.gradients[gradient]; !ok && gradient != "" {
+					c, _ := parseColor(attrs["stop-color"], 0xFF000000)
+					p.gradients[gradient] = withOpacity(c, parseOpacity(attrs["stop-opacity"]))
+				}
+			}
+			if skip > 0 || svgHidden[name] || attrs["display"] == "none" {
+				skip++
+				continue
+			}
+			if path := shapePath(name, attrs); path != nil {
+				p.add(path, st)
+			}
+		case xml.EndElement:
+			styles = styles[:len(styles)-1]
+			if skip > 0 {
+				skip--
+			}
+		}
+	}
+	if !root {
+		return nil, errors.New("svg: not an SVG document")
+	}
+	return s, nil
+}
+
+// Elements whose content is only referenced, never drawn directly
+var svgHidden = map[string]bool{
+	"defs": true, "clipPath": true, "mask": true, "symbol": true, "marker": true,
+	"pattern": true, "linearGradient": true, "radialGradient": true, "title": true,
+	"desc": true, "metadata": true, "style": true, "text": true, "filter": true,
+}
+
+type svgParser struct {
+	svg       *SVG
+	gradients map[string]uint32 // Colour of the first stop, by id
+}
+
+// svgAttrs returns the attributes of an element, with the declarations of
+// its style attribute as if they were attributes.
+func svgAttrs(list []xml.Attr) map[string]string {
+	attrs := make(map[string]string, len(list))
+	for _, a := range list {
+		attrs[a.Name.Local] = strings.TrimSpace(a.Value)
+	}
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		if k, v, ok := strings.Cut(decl, ":"); ok {
+			attrs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return attrs
+}
+
+func (s *SVG) init(attrs map[string]string) {
+	s.Width = svgSize(attrs["width"])
+	s.Height = svgSize(attrs["height"])
+	if vb := parseNumbers(attrs["viewBox"]); len(vb) == 4 && vb[2] > 0 && vb[3] > 0 &&
+		!math.IsInf(vb[2], 1) && !math.IsInf(vb[3], 1) {
+		copy(s.viewBox[:], vb)
+		switch {
+		case s.Width == 0 && s.Height == 0:
+			s.Width, s.Height = vb[2], vb[3]
+		case s.Width == 0:
+			s.Width = s.Height * vb[2] / vb[3]
+		case s.Height == 0:
+			s.Height = s.Width * vb[3] / vb[2]
+		}
+	}
+	if s.Width == 0 {
+		s.Width = 300
+	}
+	if s.Height == 0 {
+		s.Height = 150
+	}
+	if s.viewBox[2] == 0 {
+		s.viewBox = [4]float64{0, 0, s.Width, s.Height}
+	}
+	if math.IsInf(s.Width, 1) || math.IsInf(s.Height, 1) { // From an extreme viewBox
+		s.Width, s.Height = 300, 150
+	}
+	if scale := MaxSVGSize / max(s.Width, s.Height); scale < 1 {
+		s.Width = max(1, s.Width*scale)
+		s.Height = max(1, s.Height*scale)
+	}
+}
+
+// style applies the presentation attributes of an element to the style
+// inherited from its parent.
+func (p *svgParser) style(st svgStyle, attrs map[string]string) svgStyle {
+	if v, ok := attrs["fill"]; ok {
+		st.fill = p.paint(v, st.fill)
+	}
+	if v, ok := attrs["stroke"]; ok {
+		st.stroke = p.paint(v, st.stroke)
+	}
+	if v, ok := attrs["stroke-width"]; ok {
+		st.strokeWidth, _ = parseLength(v)
+	}
+	if v, ok := attrs["fill-opacity"]; ok {
+		st.fillOpacity = parseOpacity(v)
+	}
+	if v, ok := attrs["stroke-opacity"]; ok {
+		st.strokeOpacity = parseOpacity(v)
+	}
+	if v, ok := attrs["opacity"]; ok {
+		st.opacity *= parseOpacity(v)
+	}
+	switch attrs["fill-rule"] {
+	case "evenodd":
+		st.rule = EvenOdd
+	case "nonzero":
+		st.rule = NonZero
+	}
+	if v, ok := attrs["transform"]; ok {
+		st.transform = st.transform.mul(parseTransform(v))
+	}
+	return st
+}
+
+// paint parses a fill or stroke value; a gradient is drawn in its first
+// stop colour.
+func (p *svgParser) paint(v string, inherited uint32) uint32 {
+	if strings.HasPrefix(v, "url(") {
+		id := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(v, "url("), "#"), ")")
+		if c, ok := p.gradients[strings.Trim(id, " '\"")]; ok {
+			return c
+		}
+		return inherited
+	}
+	c, ok := parseColor(v, inherited)
+	if !ok {
+		return inherited
+	}
+	return c
+}
+
+func (p *svgParser) add(path []pathSeg, st svgStyle) {
+	for i := range path {
+		for j := range path[i].pts {
+			path[i].pts[j] = st.transform.apply(path[i].pts[j])
+		}
+	}
+	shape := svgShape{path: path, rule: st.rule, strokeWidth: st.strokeWidth * st.transform.scale()}
+	if st.fill != 0 {
+		shape.fill = withOpacity(st.fill, st.fillOpacity*st.opacity)
+	}
+	if st.stroke != 0 && st.strokeWidth > 0 {
+		shape.stroke = withOpacity(st.stroke, st.strokeOpacity*st.opacity)
+	}
+	if shape.fill>>24 != 0 || shape.stroke>>24 != 0 {
+		p.svg.shapes = append(p.svg.shapes, shape)
+	}
+}
+
+func withOpacity(c uint32, opacity float64) uint32 {
+	a := math.Round(float64(c>>24) * math.Max(0, math.Min(1, opacity)))
+	return uint32(a)<<24 | c&0x00FFFFFF
+}
+
+func parseOpacity(v string) float64 {
+	if strings.HasSuffix(v, "%") {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil {
+			return 1
+		}
+		return f / 100
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 1
+	}
+	return f
+}
+
+// parseLength parses a length in pixels; units other than px are taken
+// as pixels too, and percentages are ignored.
+func parseLength(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" || strings.HasSuffix(v, "%") {
+		return 0, false
+	}
+	end := len(v)
+	for end > 0 && (v[end-1] >= 'a' && v[end-1] <= 'z') {
+		end--
+	}
+	f, err := strconv.ParseFloat(v[:end], 64)
+	return f, err == nil
+}
+
+var svgColors = map[string]uint32{
+	"black": 0x000000, "white": 0xFFFFFF, "red": 0xFF0000, "green": 0x008000,
+	"blue": 0x0000FF, "yellow": 0xFFFF00, "gray": 0x808080, "grey": 0x808080,
+	"silver": 0xC0C0C0, "maroon": 0x800000, "purple": 0x800080, "fuchsia": 0xFF00FF,
+	"magenta": 0xFF00FF, "lime": 0x00FF00, "olive": 0x808000, "navy": 0x000080,
+	"teal": 0x008080, "aqua": 0x00FFFF, "cyan": 0x00FFFF, "orange": 0xFFA500,
+	"darkgray": 0xA9A9A9, "darkgrey": 0xA9A9A9, "lightgray": 0xD3D3D3,
+	"lightgrey": 0xD3D3D3, "brown": 0xA52A2A, "pink": 0xFFC0CB, "gold": 0xFFD700,
+}
+
+// parseColor parses a colour as ARGB: 0 for none, or ok false if not
+// understood. currentColor is black.
+func parseColor(v string, inherited uint32) (uint32, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch {
+	case v == "none" || v == "transparent":
+		return 0, true
+	case v == "currentcolor":
+		return 0xFF000000, true
+	case v == "inherit":
+		return inherited, true
+	case strings.HasPrefix(v, "#"):
+		hex := v[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return 0, false
+		}
+		return 0xFF000000 | uint32(n), true
+	case strings.HasPrefix(v, "rgb"):
+		open, end := strings.IndexByte(v, '('), strings.IndexByte(v, ')')
+		if open < 0 || end < open {
+			return 0, false
+		}
+		parts := strings.FieldsFunc(v[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+		if len(parts) < 3 {
+			return 0, false
+		}
+		var c [3]uint32
+		for i := range c {
+			f, _ := strconv.ParseFloat(strings.TrimSuffix(parts[i], "%"), 64)
+			if strings.HasSuffix(parts[i], "%") {
+				f *= 2.55
+			}
+			c[i] = uint32(math.Max(0, math.Min(255, math.Round(f))))
+		}
+		alpha := 1.0
+		if len(parts) > 3 {
+			alpha = parseOpacity(parts[3])
+		}
+		return withOpacity(0xFF000000|c[0]<<16|c[1]<<8|c[2], alpha), true
+	}
+	if c, ok := svgColors[v]; ok {
+		return 0xFF000000 | c, true
+	}
+	return 0, false
+}
+
+// parseNumbers parses a list of numbers separated by spaces or commas.
+func parseNumbers(v string) []float64 {
+	sc := pathScanner{s: v}
+	var nums []float64
+	for {
+		f, ok := sc.number()
+		if !ok {
+			return nums
+		}
+		nums = append(nums, f)
+	}
+}
+
+func parseTransform(v string) affine {
+	m := identity
+	for v = strings.TrimSpace(v); v != ""; {
+		open, end := strings.IndexByte(v, '('), strings.IndexByte(v, ')')
+		if open < 0 || end < open {
+			break
+		}
+		name := strings.Trim(v[:open], " ,\t\n")
+		a := parseNumbers(v[open+1 : end])
+		v = strings.TrimSpace(v[end+1:])
+		arg := func(i int, def float64) float64 {
+			if i < len(a) {
+				return a[i]
+			}
+			return def
+		}
+		var t affine
+		switch name {
+		case "matrix":
+			if len(a) != 6 {
+				continue
+			}
+			copy(t[:], a)
+		case "translate":
+			t = affine{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			sx := arg(0, 1)
+			t = affine{sx, 0, 0, arg(1, sx), 0, 0}
+		case "rotate":
+			r := arg(0, 0) * math.Pi / 180
+			cx, cy := arg(1, 0), arg(2, 0)
+			sin, cos := math.Sincos(r)
+			t = affine{1, 0, 0, 1, cx, cy}.mul(affine{cos, sin, -sin, cos, 0, 0}).mul(affine{1, 0, 0, 1, -cx, -cy})
+		case "skewX":
+			t = affine{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			t = affine{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			continue
+		}
+		m = m.mul(t)
+	}
+	return m
+}
+
+// pathScanner reads the numbers and commands of path data.
+type pathScanner struct {
+	s string
+	i int
+}
+
+func (sc *pathScanner) skipSpace() {
+	for sc.i < len(sc.s) && strings.IndexByte(" \t\r\n,", sc.s[sc.i]) >= 0 {
+		sc.i++
+	}
+}
+
+// number reads a number, which may follow the previous one without a
+// separator as in "1.5.5" or "1-2".
+func (sc *pathScanner) number() (float64, bool) {
+	sc.skipSpace()
+	start := sc.i
+	if sc.i < len(sc.s) && (sc.s[sc.i] == '-' || sc.s[sc.i] == '+') {
+		sc.i++
+	}
+	dot, digits := false, false
+	for ; sc.i < len(sc.s); sc.i++ {
+		ch := sc.s[sc.i]
+		if ch >= '0' && ch <= '9' {
+			digits = true
+		} else if ch == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+	}
+	if digits && sc.i < len(sc.s) && (sc.s[sc.i] == 'e' || sc.s[sc.i] == 'E') {
+		j := sc.i + 1
+		if j < len(sc.s) && (sc.s[j] == '-' || sc.s[j] == '+') {
+			j++
+		}
+		if j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+			for sc.i = j; sc.i < len(sc.s) && sc.s[sc.i] >= '0' && sc.s[sc.i] <= '9'; sc.i++ {
+			}
+		}
+	}
+	if !digits {
+		sc.i = start
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(sc.s[start:sc.i], 64)
+	return f, err == nil
+}
+
+// flag reads an arc flag, a single 0 or 1 that needs no separator.
+func (sc *pathScanner) flag() (bool, bool) {
+	sc.skipSpace()
+	if sc.i < len(sc.s) && (sc.s[sc.i] == '0' || sc.s[sc.i] == '1') {
+		sc.i++
+		return sc.s[sc.i-1] == '1', true
+	}
+	return false, false
+}
+
+// pathBuilder collects segments in absolute coordinates.
+type pathBuilder struct {
+	segs        []pathSeg
+	cur, start  Point
+	lastCtrl    Point // Second control point of the last cubic, for S
+	lastQuad    Point // Control point of the last quadratic, for T
+	prevCommand byte
+}
+
+func (b *pathBuilder) moveTo(p Point) {
+	b.segs = append(b.segs, pathSeg{op: opMove, pts: [3]Point{p}})
+	b.cur, b.start = p, p
+}
+
+func (b *pathBuilder) lineTo(p Point) {
+	b.segs = append(b.segs, pathSeg{op: opLine, pts: [3]Point{p}})
+	b.cur = p
+}
+
+func (b *pathBuilder) cubicTo(c1, c2, p Point) {
+	b.segs = append(b.segs, pathSeg{op: opCubic, pts: [3]Point{c1, c2, p}})
+	b.lastCtrl = c2
+	b.cur = p
+}
+
+func (b *pathBuilder) quadTo(c, p Point) {
+	cur := b.cur
+	b.cubicTo(Point{cur.X + 2.0/3*(c.X-cur.X), cur.Y + 2.0/3*(c.Y-cur.Y)},
+		Point{p.X + 2.0/3*(c.X-p.X), p.Y + 2.0/3*(c.Y-p.Y)}, p)
+	b.lastQuad = c
+}
+
+func (b *pathBuilder) close() {
+	b.segs = append(b.segs, pathSeg{op: opClose})
+	b.cur = b.start
+}
+
+// arcTo adds an elliptical arc as cubics, from the endpoint
+// parameterization of SVG.
+func (b *pathBuilder) arcTo(rx, ry, rotation float64, large, sweep bool, p Point) {
+	p0 := b.cur
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || p0 == p {
+		b.lineTo(p)
+		return
+	}
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+	// Midpoint in the rotated frame
+	dx, dy := (p0.X-p.X)/2, (p0.Y-p.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+	// Radii too small to reach are scaled up
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	k := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		k = -k
+	}
+	cx1, cy1 := k*rx*y1/ry, -k*ry*x1/rx
+	cx := cos*cx1 - sin*cy1 + (p0.X+p.X)/2
+	cy := sin*cx1 + cos*cy1 + (p0.Y+p.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	// At most a quarter turn per cubic
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	step := delta / float64(n)
+	t := 4.0 / 3 * math.Tan(step/4)
+	point := func(a float64) (Point, Point) { // Point and derivative
+		sa, ca := math.Sincos(a)
+		px, py := rx*ca, ry*sa
+		tx, ty := -rx*sa, ry*ca
+		return Point{cos*px - sin*py + cx, sin*px + cos*py + cy}, Point{cos*tx - sin*ty, sin*tx + cos*ty}
+	}
+	for i := 0; i < n; i++ {
+		a0, a1 := theta+step*float64(i), theta+step*float64(i+1)
+		q0, d0 := point(a0)
+		q1, d1 := point(a1)
+		if i == n-1 {
+			q1 = p
+		}
+		b.cubicTo(Point{q0.X + t*d0.X, q0.Y + t*d0.Y}, Point{q1.X - t*d1.X, q1.Y - t*d1.Y}, q1)
+	}
+}
+
+// parsePath parses path data. Parsing stops at the first error, keeping
+// what came before it, as browsers do.
+func parsePath(d string) []pathSeg {
+	sc := pathScanner{s: d}
+	b := &pathBuilder{}
+	var cmd byte
+	for {
+		sc.skipSpace()
+		if sc.i >= len(sc.s) {
+			break
+		}
+		if ch := sc.s[sc.i]; (ch|0x20) >= 'a' && (ch|0x20) <= 'z' && ch|0x20 != 'e' {
+			cmd = ch
+			sc.i++
+		} else if cmd == 0 {
+			break
+		}
+		rel := cmd >= 'a'
+		off := func(p Point) Point {
+			if rel {
+				return Point{b.cur.X + p.X, b.cur.Y + p.Y}
+			}
+			return p
+		}
+		num := func(n int) ([]float64, bool) {
+			v := make([]float64, n)
+			for i := range v {
+				f, ok := sc.number()
+				if !ok {
+					return nil, false
+				}
+				v[i] = f
+			}
+			return v, true
+		}
+
+		ok := true
+		var v []float64
+		switch cmd | 0x20 {
+		case 'z':
+			b.close()
+			b.prevCommand = 'z'
+			cmd = 0 // No arguments; the next must be a command
+			continue
+		case 'm':
+			if v, ok = num(2); ok {
+				b.moveTo(off(Point{v[0], v[1]}))
+				// Further pairs are lines
+				if rel {
+					cmd = 'l'
+				} else {
+					cmd = 'L'
+				}
+			}
+		case 'l':
+			if v, ok = num(2); ok {
+				b.lineTo(off(Point{v[0], v[1]}))
+			}
+		case 'h':
+			if v, ok = num(1); ok {
+				x := v[0]
+				if rel {
+					x += b.cur.X
+				}
+				b.lineTo(Point{x, b.cur.Y})
+			}
+		case 'v':
+			if v, ok = num(1); ok {
+				y := v[0]
+				if rel {
+					y += b.cur.Y
+				}
+				b.lineTo(Point{b.cur.X, y})
+			}
+		case 'c':
+			if v, ok = num(6); ok {
+				b.cubicTo(off(Point{v[0], v[1]}), off(Point{v[2], v[3]}), off(Point{v[4], v[5]}))
+			}
+		case 's':
+			if v, ok = num(4); ok {
+				c1 := b.cur
+				if p := b.prevCommand | 0x20; p == 'c' || p == 's' {
+					c1 = Point{2*b.cur.X - b.lastCtrl.X, 2*b.cur.Y - b.lastCtrl.Y}
+				}
+				b.cubicTo(c1, off(Point{v[0], v[1]}), off(Point{v[2], v[3]}))
+			}
+		case 'q':
+			if v, ok = num(4); ok {
+				b.quadTo(off(Point{v[0], v[1]}), off(Point{v[2], v[3]}))
+			}
+		case 't':
+			if v, ok = num(2); ok {
+				c := b.cur
+				if p := b.prevCommand | 0x20; p == 'q' || p == 't' {
+					c = Point{2*b.cur.X - b.lastQuad.X, 2*b.cur.Y - b.lastQuad.Y}
+				}
+				b.quadTo(c, off(Point{v[0], v[1]}))
+			}
+		case 'a':
+			var large, sweep bool
+			if v, ok = num(3); ok {
+				if large, ok = sc.flag(); ok {
+					if sweep, ok = sc.flag(); ok {
+						var e []float64
+						if e, ok = num(2); ok {
+							b.arcTo(v[0], v[1], v[2], large, sweep, off(Point{e[0], e[1]}))
+						}
+					}
+				}
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			break
+		}
+		b.prevCommand = cmd
+	}
+	return b.segs
+}
+
+// shapePath returns the path of a shape element, or nil for other
+// elements.
+func shapePath(name string, attrs map[string]string) []pathSeg {
+	num := func(key string) float64 {
+		f, _ := parseLength(attrs[key])
+		return f
+	}
+	b := &pathBuilder{}
+	switch name {
+	case "path":
+		return parsePath(attrs["d"])
+	case "rect":
+		x, y, w, h := num("x"), num("y"), num("width"), num("height")
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		rx, okx := parseLength(attrs["rx"])
+		ry, oky := parseLength(attrs["ry"])
+		if !okx {
+			rx = ry
+		}
+		if !oky {
+			ry = rx
+		}
+		rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+		if rx <= 0 || ry <= 0 {
+			b.moveTo(Point{x, y})
+			b.lineTo(Point{x + w, y})
+			b.lineTo(Point{x + w, y + h})
+			b.lineTo(Point{x, y + h})
+			b.close()
+			return b.segs
+		}
+		b.moveTo(Point{x + rx, y})
+		b.lineTo(Point{x + w - rx, y})
+		b.arcTo(rx, ry, 0, false, true, Point{x + w, y + ry})
+		b.lineTo(Point{x + w, y + h - ry})
+		b.arcTo(rx, ry, 0, false, true, Point{x + w - rx, y + h})
+		b.lineTo(Point{x + rx, y + h})
+		b.arcTo(rx, ry, 0, false, true, Point{x, y + h - ry})
+		b.lineTo(Point{x, y + ry})
+		b.arcTo(rx, ry, 0, false, true, Point{x + rx, y})
+		b.close()
+		return b.segs
+	case "circle", "ellipse":
+		cx, cy := num("cx"), num("cy")
+		rx, ry := num("rx"), num("ry")
+		if name == "circle" {
+			rx, ry = num("r"), num("r")
+		}
+		if rx <= 0 || ry <= 0 {
+			return nil
+		}
+		b.moveTo(Point{cx + rx, cy})
+		b.arcTo(rx, ry, 0, false, true, Point{cx - rx, cy})
+		b.arcTo(rx, ry, 0, false, true, Point{cx + rx, cy})
+		b.close()
+		return b.segs
+	case "line":
+		b.moveTo(Point{num("x1"), num("y1")})
+		b.lineTo(Point{num("x2"), num("y2")})
+		return b.segs
+	case "polyline", "polygon":
+		pts := parseNumbers(attrs["points"])
+		if len(pts) < 4 {
+			return nil
+		}
+		b.moveTo(Point{pts[0], pts[1]})
+		for i := 2; i+1 < len(pts); i += 2 {
+			b.lineTo(Point{pts[i], pts[i+1]})
+		}
+		if name == "polygon" {
+			b.close()
+		}
+		return b.segs
+	}
+	return nil
+}
+
+// flatten turns a path into polylines with the transform m, splitting
+// cubics into segments of a few pixels. It reports for each polyline
+// whether it was closed.
+func flatten(path []pathSeg, m affine) ([][]Point, []bool) {
+	var polys [][]Point
+	var closed []bool
+	var cur []Point
+	flush := func(close bool) {
+		if len(cur) > 1 {
+			polys = append(polys, cur)
+			closed = append(closed, close)
+		}
+		cur = nil
+	}
+	for _, seg := range path {
+		switch seg.op {
+		case opMove:
+			flush(false)
+			cur = []Point{m.apply(seg.pts[0])}
+		case opLine:
+			if cur == nil {
+				continue
+			}
+			cur = append(cur, m.apply(seg.pts[0]))
+		case opCubic:
+			if cur == nil {
+				continue
+			}
+			p0 := cur[len(cur)-1]
+			p1, p2, p3 := m.apply(seg.pts[0]), m.apply(seg.pts[1]), m.apply(seg.pts[2])
+			length := math.Hypot(p1.X-p0.X, p1.Y-p0.Y) + math.Hypot(p2.X-p1.X, p2.Y-p1.Y) + math.Hypot(p3.X-p2.X, p3.Y-p2.Y)
+			n := max(1, min(100, int(length/3)))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				cur = append(cur, Point{
+					u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+					u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+				})
+			}
+		case opClose:
+			if cur != nil {
+				start := cur[0]
+				flush(true)
+				cur = []Point{start} // Drawing may go on from the start
+			}
+		}
+	}
+	flush(false)
+	return polys, closed
+}
+
+// DrawSVG draws the drawing into a rectangle of the canvas, scaled to fit
+// and centred, keeping its aspect ratio.
+func (c *Canvas) DrawSVG(s *SVG, x, y, w, h float64) {
+	vb := s.viewBox
+	scale := math.Min(w/vb[2], h/vb[3])
+	ox := x + (w-vb[2]*scale)/2 - vb[0]*scale
+	oy := y + (h-vb[3]*scale)/2 - vb[1]*scale
+	m := affine{scale, 0, 0, scale, ox, oy}
+	for _, shape := range s.shapes {
+		polys, closed := flatten(shape.path, m)
+		if shape.fill>>24 != 0 && len(polys) > 0 {
+			c.FillPolygon(shape.rule, shape.fill, polys...)
+		}
+		if shape.stroke>>24 != 0 {
+			width := math.Max(shape.strokeWidth*scale, 0.5)
+			for i, poly := range polys {
+				if closed[i] {
+					poly = append(poly, poly[0])
+				}
+				c.StrokePolyline(poly, width, shape.stroke)
+			}
+		}
+	}
+}
+
+// Render draws the drawing on a transparent image of the given size.
+func (s *SVG) Render(width, height int32) *Image {
+	width, height = max(width, 0), max(height, 0)
+	img := NewImage(width, height)
+	c := &Canvas{Width: width, Height: height, Buffer: img.Pix, Transparent: true}
+	c.DrawSVG(s, 0, 0, float64(width), float64(height))
+	return img
+}
@@ -0,0 +1,314 @@
+// Package theme holds the colours, fonts and metrics shared by all
+// built-in components, so that an application can restyle them at once,
+// e.g. switching to a dark theme at runtime.
+//
+// Components read Current every time they render, so a theme change
+// shows on the next frame; Set notifies the windows, which repaint.
+package theme
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jacksalad/goui_v0/render"
+)
+
+// Palette holds the colour tokens, as 0xAARRGGBB.
+type Palette struct {
+	Background     uint32 // Windows, panels, and the inside of fields and lists
+	Surface        uint32 // Chrome around content: menu bar, headers, tab strip, scroll bar track
+	Control        uint32 // Button faces
+	ControlHover   uint32
+	ControlPressed uint32
+	Primary        uint32 // Accent: checked marks, slider and progress fills, active tab
+	PrimaryHover   uint32
+	OnPrimary      uint32 // Drawn on Primary and the status colours, e.g. the knob of a switch
+	Text           uint32
+	TextSecondary  uint32 // Inactive tabs, headers, secondary lines
+	TextDisabled   uint32 // Disabled controls and placeholders
+	Border         uint32
+	Divider        uint32 // Separators, lighter than Border
+	Focus          uint32 // Outline of the focused component
+	Error          uint32 // Invalid input and errors
+	Success        uint32 // Completed operations, e.g. success toasts
+	Warning        uint32 // Warnings; also folder icons
+	Danger         uint32 // Destructive actions, e.g. a hovered close button
+	Hover          uint32 // Item under the pointer
+	Selection      uint32 // Selected items and text
+	Icon           uint32 // Arrows and other glyphs
+	Tooltip        uint32 // Background of tooltips
+	TooltipText    uint32
+	Shadow         uint32 // Below popups and toasts
+}
+
+// TextStyle is a level of the typography scale.
+type TextStyle int
+
+const (
+	Caption TextStyle = iota // Tooltips and small print
+	Body                     // Default for all text
+	Title                    // Titles of cards, dialogs and charts
+	Heading
+)
+
+// Typography is the font family and the size of each text style, in
+// points.
+type Typography struct {
+	Family  string // "" for the system font, which has a single size
+	Caption int
+	Body    int
+	Title   int
+	Heading int
+}
+
+// Theme is a palette with typography and metrics. Built-in themes must
+// not be changed; copy one to derive a theme:
+//
+//	t := *theme.Dark
+//	t.Primary = 0xFF8764B8
+//	theme.Set(&t)
+type Theme struct {
+	Name string
+	Palette
+	Typography Typography
+
+	// CornerRadius rounds buttons, fields, cards, toasts and tooltips;
+	// 0 keeps them square.
+	CornerRadius int32
+	// Spacing is the unit of padding and gaps, see Space.
+	Spacing int32
+}
+
+// Space returns n spacing units, e.g. Space(2) between a check box and
+// its label.
+func (t *Theme) Space(n int32) int32 {
+	return n * t.Spacing
+}
+
+// Size returns the size in points of a text style.
+func (t *Theme) Size(style TextStyle) int {
+	switch style {
+	case Caption:
+		return t.Typography.Caption
+	case Title:
+		return t.Typography.Title
+	case Heading:
+		return t.Typography.Heading
+	}
+	return t.Typography.Body
+}
+
+type fontKey struct {
+	family string
+	size   int
+}
+
+// Fonts of all themes, created on first use and kept, since components
+// may still hold them.
+var (
+	fontsMu sync.Mutex
+	fonts   = map[fontKey]*render.Font{}
+)
+
+// Font returns the font of a text style, or nil for the system font. The
+// font is shared: do not Close it.
+func (t *Theme) Font(style TextStyle) *render.Font {
+	size := t.Size(style)
+	if t.Typography.Family == "" || size <= 0 {
+		return nil
+	}
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+	key := fontKey{t.Typography.Family, size}
+	font, ok := fonts[key]
+	if !ok {
+		font = render.NewFont(key.family, key.size)
+		fonts[key] = font
+	}
+	return font
+}
+
+// Or returns color, or token if color is 0, for the colour fields that
+// follow the theme unless set.
+func Or(color, token uint32) uint32 {
+	if color != 0 {
+		return color
+	}
+	return token
+}
+
+// Mix blends colour a towards b by t, from 0 (a) to 1 (b), e.g. to derive
+// a hover shade from a token.
+func Mix(a, b uint32, t float64) uint32 {
+	t = math.Max(0, math.Min(1, t))
+	var out uint32
+	for shift := 0; shift < 32; shift += 8 {
+		ca, cb := float64(a>>shift&0xFF), float64(b>>shift&0xFF)
+		out |= uint32(math.Round(ca+(cb-ca)*t)) << shift
+	}
+	return out
+}
+
+// The system font, which applications had before themes; the sizes
+// apply to a copy given a Family.
+var typography = Typography{Caption: 8, Body: 9, Title: 11, Heading: 15}
+
+// Light is the default theme.
+var Light = &Theme{
+	Name: "Light",
+	Palette: Palette{
+		Background:     0xFFFFFFFF,
+		Surface:        0xFFF0F0F0,
+		Control:        0xFFDDDDDD,
+		ControlHover:   0xFFEEEEEE,
+		ControlPressed: 0xFFAAAAAA,
+		Primary:        0xFF0078D7,
+		PrimaryHover:   0xFF1A86DB,
+		OnPrimary:      0xFFFFFFFF,
+		Text:           0xFF000000,
+		TextSecondary:  0xFF555555,
+		TextDisabled:   0xFF999999,
+		Border:         0xFFAAAAAA,
+		Divider:        0xFFDDDDDD,
+		Focus:          0xFF0078D7,
+		Error:          0xFFD13438,
+		Success:        0xFF107C10,
+		Warning:        0xFFE0A000,
+		Danger:         0xFFE81123,
+		Hover:          0xFFE5F3FF,
+		Selection:      0xFFCCE8FF,
+		Icon:           0xFF606060,
+		Tooltip:        0xFF404040,
+		TooltipText:    0xFFFFFFFF,
+		Shadow:         0xFFC8C8C8,
+	},
+	Typography:   typography,
+	CornerRadius: 4,
+	Spacing:      4,
+}
+
+// Dark is a dark grey theme.
+var Dark = &Theme{
+	Name: "Dark",
+	Palette: Palette{
+		Background:     0xFF202020,
+		Surface:        0xFF2B2B2B,
+		Control:        0xFF3A3A3A,
+		ControlHover:   0xFF464646,
+		ControlPressed: 0xFF2F2F2F,
+		Primary:        0xFF3A96DD,
+		PrimaryHover:   0xFF5AA9E6,
+		OnPrimary:      0xFFFFFFFF,
+		Text:           0xFFF0F0F0,
+		TextSecondary:  0xFFB4B4B4,
+		TextDisabled:   0xFF6E6E6E,
+		Border:         0xFF5A5A5A,
+		Divider:        0xFF3C3C3C,
+		Focus:          0xFF60CDFF,
+		Error:          0xFFF1707B,
+		Success:        0xFF2D9D2D,
+		Warning:        0xFFC88A00,
+		Danger:         0xFFC42B1C,
+		Hover:          0xFF2A3A4A,
+		Selection:      0xFF264F78,
+		Icon:           0xFFB4B4B4,
+		Tooltip:        0xFF404040,
+		TooltipText:    0xFFFFFFFF,
+		Shadow:         0xFF101010,
+	},
+	Typography:   typography,
+	CornerRadius: 4,
+	Spacing:      4,
+}
+
+// HighContrast is white and bright colours on black, with square corners
+// and a larger font.
+var HighContrast = &Theme{
+	Name: "High Contrast",
+	Palette: Palette{
+		Background:     0xFF000000,
+		Surface:        0xFF000000,
+		Control:        0xFF000000,
+		ControlHover:   0xFF00304A,
+		ControlPressed: 0xFF005A80,
+		Primary:        0xFF1AEBFF,
+		PrimaryHover:   0xFF8AF5FF,
+		OnPrimary:      0xFF000000,
+		Text:           0xFFFFFFFF,
+		TextSecondary:  0xFFFFFFFF,
+		TextDisabled:   0xFF3FF23F,
+		Border:         0xFFFFFFFF,
+		Divider:        0xFFFFFFFF,
+		Focus:          0xFFFFFF00,
+		Error:          0xFFFF4D4D,
+		Success:        0xFF3FF23F,
+		Warning:        0xFFFFFF00,
+		Danger:         0xFFFF4D4D,
+		Hover:          0xFF00304A,
+		Selection:      0xFF005A80,
+		Icon:           0xFFFFFFFF,
+		Tooltip:        0xFFFFFFFF,
+		TooltipText:    0xFF000000,
+		Shadow:         0xFF000000,
+	},
+	Typography:   Typography{Family: "Segoe UI", Caption: 10, Body: 11, Title: 13, Heading: 17},
+	CornerRadius: 0,
+	Spacing:      4,
+}
+
+var (
+	current   atomic.Pointer[Theme]
+	mu        sync.Mutex
+	listeners map[int]func()
+	nextID    int
+)
+
+func init() {
+	current.Store(Light)
+}
+
+// Current returns the theme in use.
+func Current() *Theme {
+	return current.Load()
+}
+
+// Set switches to theme t and notifies the OnChange listeners, so that
+// every window repaints with it. Since it swaps the default font, call it
+// on the UI thread; other goroutines go through component.Post.
+func Set(t *Theme) {
+	if t == nil {
+		t = Light
+	}
+	current.Store(t)
+	render.SetDefaultFont(t.Font(Body))
+
+	mu.Lock()
+	notify := make([]func(), 0, len(listeners))
+	for _, f := range listeners {
+		notify = append(notify, f)
+	}
+	mu.Unlock()
+	for _, f := range notify {
+		f()
+	}
+}
+
+// OnChange registers f to be called after each Set, on the goroutine
+// that called Set, and returns a function that unregisters it. Windows
+// use it to repaint.
+func OnChange(f func()) (remove func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	if listeners == nil {
+		listeners = map[int]func(){}
+	}
+	id := nextID
+	nextID++
+	listeners[id] = f
+	return func() {
+		mu.Lock()
+		delete(listeners, id)
+		mu.Unlock()
+	}
+}
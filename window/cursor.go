@@ -0,0 +1,67 @@
+package window
+
+import (
+	"unsafe"
+
+	"github.com/jacksalad/goui_v0/component"
+)
+
+const (
+	WM_SETCURSOR = 0x0020
+	HTCLIENT     = 1
+
+	IDC_IBEAM   = 32513
+	IDC_SIZEALL = 32646
+	IDC_SIZEWE  = 32644
+	IDC_SIZENS  = 32645
+	IDC_HAND    = 32649
+)
+
+var (
+	procSetCursor    = moduser32.NewProc("SetCursor")
+	procGetCursorPos = moduser32.NewProc("GetCursorPos")
+)
+
+var (
+	cursorIDs = map[component.Cursor]uintptr{
+		component.CursorArrow:   IDC_ARROW,
+		component.CursorIBeam:   IDC_IBEAM,
+		component.CursorHand:    IDC_HAND,
+		component.CursorSizeWE:  IDC_SIZEWE,
+		component.CursorSizeNS:  IDC_SIZENS,
+		component.CursorSizeAll: IDC_SIZEALL,
+	}
+	cursorHandles = make(map[component.Cursor]uintptr)
+)
+
+// cursorAt returns the pointer shape wanted at a window position.
+func (w *Window) cursorAt(x, y int32) component.Cursor {
+	target := component.Captured()
+	if target == nil {
+		switch {
+		case w.Overlay.Contains(x, y):
+			target = w.Overlay.FindComponentAt(x, y)
+		case w.MenuBar != nil && w.MenuBar.Bounds.Contains(x, y):
+		default:
+			target = w.Root.FindComponentAt(x, y)
+		}
+	}
+	if p, ok := target.(component.CursorProvider); ok {
+		return p.CursorAt(x, y)
+	}
+	return component.CursorArrow
+}
+
+// updateCursor sets the pointer shape for the current pointer position.
+func (w *Window) updateCursor() {
+	var pt point
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	procScreenToClient.Call(uintptr(w.hwnd), uintptr(unsafe.Pointer(&pt)))
+	c := w.cursorAt(pt.X, pt.Y)
+	h, ok := cursorHandles[c]
+	if !ok {
+		h, _, _ = procLoadCursorW.Call(0, cursorIDs[c])
+		cursorHandles[c] = h
+	}
+	procSetCursor.Call(h)
+}
@@ -0,0 +1,40 @@
+package window
+
+import (
+	"sync"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/jacksalad/goui_v0/component"
+)
+
+const (
+	LOCALE_SDECIMAL  = 0x0E
+	LOCALE_STHOUSAND = 0x0F
+)
+
+var procGetLocaleInfoEx = modkernel32.NewProc("GetLocaleInfoEx")
+
+// localeChar returns the first character of a user locale setting, or 0.
+func localeChar(lcType uint32) rune {
+	var buf [8]uint16
+	n, _, _ := procGetLocaleInfoEx.Call(0, uintptr(lcType), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if n < 2 { // Count includes the terminating null
+		return 0
+	}
+	return utf16.Decode(buf[:n-1])[0]
+}
+
+var numberFormatOnce sync.Once
+
+// loadNumberFormat sets the default number format from the user locale,
+// unless the application changed it.
+func loadNumberFormat() {
+	if component.DefaultNumberFormat != component.InvariantNumberFormat {
+		return
+	}
+	if d := localeChar(LOCALE_SDECIMAL); d != 0 {
+		component.DefaultNumberFormat.Decimal = d
+		component.DefaultNumberFormat.Group = localeChar(LOCALE_STHOUSAND)
+	}
+}
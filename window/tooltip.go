@@ -0,0 +1,52 @@
+package window
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/jacksalad/goui_v0/component"
+)
+
+const (
+	WM_MOUSELEAVE = 0x02A3
+	TME_LEAVE     = 0x00000002
+)
+
+var procTrackMouseEvent = moduser32.NewProc("TrackMouseEvent")
+
+// TRACKMOUSEEVENT
+type trackMouseEvent struct {
+	CbSize      uint32
+	DwFlags     uint32
+	HwndTrack   uintptr
+	DwHoverTime uint32
+}
+
+// trackMouse asks for a WM_MOUSELEAVE when the pointer leaves the window.
+func (w *Window) trackMouse() {
+	if w.trackingMouse {
+		return
+	}
+	tme := trackMouseEvent{DwFlags: TME_LEAVE, HwndTrack: uintptr(w.hwnd)}
+	tme.CbSize = uint32(unsafe.Sizeof(tme))
+	procTrackMouseEvent.Call(uintptr(unsafe.Pointer(&tme)))
+	w.trackingMouse = true
+}
+
+// updateTooltip tells the tooltips which component the pointer is on:
+// none while dragging, over the menu bar or below a modal popup.
+func (w *Window) updateTooltip(x, y int32) {
+	var target component.Component
+	var ancestors []component.Component
+	switch {
+	case component.Captured() != nil || w.Tooltips.Contains(x, y):
+	case w.Overlay.Contains(x, y):
+		target = w.Overlay.FindComponentAt(x, y)
+		ancestors = w.Overlay.Ancestors(target)
+	case w.Overlay.HasModal() || (w.MenuBar != nil && w.MenuBar.Bounds.Contains(x, y)):
+	default:
+		target = w.Root.FindComponentAt(x, y)
+		ancestors = component.Ancestors(w.Root, target)
+	}
+	w.Tooltips.PointerMoved(target, ancestors, x, y, time.Now())
+}
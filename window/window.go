@@ -4,9 +4,11 @@ import (
 	"github.com/jacksalad/goui_v0/component"
 	"github.com/jacksalad/goui_v0/event"
 	"github.com/jacksalad/goui_v0/render"
+	"github.com/jacksalad/goui_v0/theme"
 	"runtime"
 	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
@@ -31,6 +33,9 @@ var (
 	procDestroyWindow    = moduser32.NewProc("DestroyWindow")
 	procSetTimer         = moduser32.NewProc("SetTimer")
 	procGetKeyState      = moduser32.NewProc("GetKeyState")
+	procScreenToClient   = moduser32.NewProc("ScreenToClient")
+	procSetCapture       = moduser32.NewProc("SetCapture")
+	procReleaseCapture   = moduser32.NewProc("ReleaseCapture")
 )
 
 var (
@@ -42,6 +47,7 @@ const (
 	IDC_ARROW           = 32512
 	CS_HREDRAW          = 0x0002
 	CS_VREDRAW          = 0x0001
+	CS_DBLCLKS          = 0x0008
 	COLOR_WINDOW        = 5
 	WS_OVERLAPPED       = 0x00000000
 	WS_CAPTION          = 0x00C00000
@@ -53,15 +59,24 @@ const (
 	CW_USEDEFAULT       = 0x80000000
 	WM_DESTROY          = 0x0002
 	WM_SIZE             = 0x0005
+	WM_ACTIVATE         = 0x0006
 	WM_PAINT            = 0x000F
 	WM_CLOSE            = 0x0010
 	WM_MOUSEMOVE        = 0x0200
 	WM_LBUTTONDOWN      = 0x0201
 	WM_LBUTTONUP        = 0x0202
+	WM_LBUTTONDBLCLK    = 0x0203
+	WM_RBUTTONDOWN      = 0x0204
+	WM_RBUTTONUP        = 0x0205
 	WM_MOUSEWHEEL       = 0x020A
+	WM_MOUSEHWHEEL      = 0x020E
+	WM_CAPTURECHANGED   = 0x0215
 	WM_KEYDOWN          = 0x0100
 	WM_KEYUP            = 0x0101
 	WM_CHAR             = 0x0102
+	WM_SYSKEYDOWN       = 0x0104
+	WM_SYSKEYUP         = 0x0105
+	WM_SYSCHAR          = 0x0106
 	WM_TIMER            = 0x0113
 	SW_SHOW             = 5
 	WM_USER             = 0x0400
@@ -83,7 +98,16 @@ type Window struct {
 	EventBus  event.EventBus
 	Renderer  *render.Renderer
 	Root      *component.Panel
+	Overlay   *component.Overlay // Popups above Root
+	Tooltips  *component.Tooltips
+	Toasts    *component.Toasts  // Notifications, safe to use from any goroutine
+	MenuBar   *component.MenuBar // Above Root, see SetMenuBar
 	FocusComp component.Component
+
+	sysKeyHandled bool // Swallow the WM_SYSCHAR of a handled Alt+key
+	lastMouse     event.MouseEvent
+	trackingMouse bool // A WM_MOUSELEAVE is requested
+	themeOff      func()
 }
 
 func init() {
@@ -93,6 +117,8 @@ func init() {
 
 // NewWindow creates a new window with the given configuration
 func NewWindow(config WindowConfig) (*Window, error) {
+	numberFormatOnce.Do(loadNumberFormat)
+
 	className, _ := syscall.UTF16PtrFromString("GouiWindowClass")
 	title, _ := syscall.UTF16PtrFromString(config.Title)
 
@@ -103,7 +129,7 @@ func NewWindow(config WindowConfig) (*Window, error) {
 	// Register Window Class
 	wc := wndClassEx{
 		CbSize:        uint32(unsafe.Sizeof(wndClassEx{})),
-		Style:         CS_HREDRAW | CS_VREDRAW,
+		Style:         CS_HREDRAW | CS_VREDRAW | CS_DBLCLKS,
 		LpfnWndProc:   syscall.NewCallback(wndProc),
 		HInstance:     windows.Handle(hInst),
 		LpszClassName: className,
@@ -157,7 +183,18 @@ func NewWindow(config WindowConfig) (*Window, error) {
 		EventBus: event.NewBus(),
 		Renderer: render.NewRenderer(windows.Handle(hwnd), config.Width, config.Height),
 		Root:     component.NewPanel(0, 0, config.Width, config.Height),
+		Overlay:  component.NewOverlay(),
 	}
+	w.Overlay.SetBounds(0, 0, config.Width, config.Height)
+	w.Tooltips = component.NewTooltips(w.Overlay)
+	w.Toasts = component.NewToasts(w.Overlay)
+	w.Overlay.Focus = w.SetFocus
+	w.Overlay.Focused = func() component.Component { return w.FocusComp }
+	component.SetActiveOverlay(w.Overlay)
+	w.themeOff = theme.OnChange(func() {
+		component.Post(w.themeChanged)
+		w.RequestRepaint()
+	})
 
 	mapMu.Lock()
 	windowsMap[w.hwnd] = w
@@ -171,6 +208,31 @@ func (w *Window) Add(c component.Component) {
 	w.Root.Add(c)
 }
 
+// SetMenuBar shows bar at the top of the window, above Root, or removes
+// the menu bar if bar is nil.
+func (w *Window) SetMenuBar(bar *component.MenuBar) {
+	r := w.Root.Bounds
+	w.MenuBar = bar
+	w.layoutClient(r.Width, r.Y+r.Height)
+}
+
+// layoutClient sizes the menu bar and Root to the client area.
+func (w *Window) layoutClient(width, height int32) {
+	top := int32(0)
+	if w.MenuBar != nil {
+		_, top = w.MenuBar.GetPreferredSize()
+		w.MenuBar.SetBounds(0, 0, width, top)
+	}
+	w.Root.SetBounds(0, top, width, height-top)
+}
+
+// themeChanged lays the window out again after a theme change, since
+// fonts and spacing change preferred sizes.
+func (w *Window) themeChanged() {
+	r := w.Root.Bounds
+	w.layoutClient(r.Width, r.Y+r.Height)
+}
+
 // SetFocus sets the focus to a component
 func (w *Window) SetFocus(c component.Component) {
 	if w.FocusComp == c {
@@ -210,6 +272,7 @@ func (w *Window) Close() {
 	delete(windowsMap, w.hwnd)
 	mapMu.Unlock()
 
+	w.themeOff()
 	w.EventBus.Close()
 	procDestroyWindow.Call(uintptr(w.hwnd))
 }
@@ -226,9 +289,13 @@ func (w *Window) Render() {
 
 	canvas := w.Renderer.BeginFrame()
 	// No need to clear if Root Panel fills everything, but safe to clear
-	canvas.Clear(0xFFFFFFFF)
+	canvas.Clear(theme.Current().Background)
 
 	w.Root.Render(canvas)
+	if w.MenuBar != nil {
+		w.MenuBar.Render(canvas)
+	}
+	w.Overlay.Render(canvas)
 
 	w.Renderer.EndFrame()
 	w.Renderer.Present()
@@ -240,40 +307,128 @@ func wndProc(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 	mapMu.RUnlock()
 
 	if ok {
-		// Handle Timer for cursor blinking
+		// Handle Timer for cursor blinking and animations
 		if msg == WM_TIMER {
-			if w.FocusComp != nil {
+			ran := component.RunPosted()
+			now := time.Now()
+			animating := component.TickAnimations(now)
+			tooltip := w.Tooltips.Tick(now)
+			if w.FocusComp != nil || animating || ran || tooltip {
 				w.Render()
 			}
 			return 0
 		}
 
+		if msg == WM_ACTIVATE && wParam&0xFFFF != 0 {
+			component.SetActiveOverlay(w.Overlay)
+		}
+
 		// Handle Repaint Request
 		if msg == WM_USER {
+			component.RunPosted()
 			w.Render()
 			return 0
 		}
 
-		if evt, ok := convertEvent(msg, wParam, lParam); ok {
+		// The character of an Alt+key that opened a menu
+		if msg == WM_SYSCHAR && w.sysKeyHandled {
+			w.sysKeyHandled = false
+			return 0
+		}
+
+		// Another window took the mouse: end the drag
+		if msg == WM_CAPTURECHANGED && windows.Handle(lParam) != hwnd {
+			if c := component.Captured(); c != nil {
+				component.ReleaseCapture()
+				c.OnEvent(event.Event{Type: event.EventMouseRelease, Data: w.lastMouse})
+				w.Render()
+			}
+		}
+
+		// The client area's pointer shape comes from the components
+		if msg == WM_SETCURSOR && lParam&0xFFFF == HTCLIENT {
+			w.updateCursor()
+			return 1
+		}
+
+		// Tooltips hide on presses and scrolling, and when the pointer
+		// leaves the window
+		switch msg {
+		case WM_LBUTTONDOWN, WM_LBUTTONDBLCLK, WM_RBUTTONDOWN, WM_MOUSEWHEEL, WM_MOUSEHWHEEL, WM_KEYDOWN, WM_SYSKEYDOWN:
+			w.Tooltips.Hide()
+		case WM_MOUSELEAVE:
+			w.trackingMouse = false
+			w.Toasts.Leave()
+			if w.Tooltips.Leave() {
+				w.Render()
+			}
+		}
+
+		// A right click focuses like a left click; the context menu follows
+		// on release
+		if msg == WM_RBUTTONDOWN {
+			w.focusAt(int32(lParam&0xFFFF), int32((lParam>>16)&0xFFFF))
+		}
+
+		if evt, ok := convertEvent(hwnd, msg, wParam, lParam); ok {
 			// Special handling for focus
-			if msg == WM_LBUTTONDOWN {
+			if msg == WM_LBUTTONDOWN || msg == WM_LBUTTONDBLCLK {
 				if mouseEvt, ok := evt.Data.(event.MouseEvent); ok {
-					target := w.Root.FindComponentAt(mouseEvt.X, mouseEvt.Y)
-					w.SetFocus(target)
+					w.focusAt(mouseEvt.X, mouseEvt.Y)
 				}
 			}
 
-			// Dispatch to focused component for keyboard/wheel events
-			if evt.Type == event.EventKeyPress || evt.Type == event.EventKeyRelease || evt.Type == event.EventChar || evt.Type == event.EventMouseWheel {
-				if w.FocusComp != nil {
-					w.FocusComp.OnEvent(evt)
-					w.Render() // Repaint after key event
+			handled := false
+			if evt.Type == event.EventKeyPress || evt.Type == event.EventKeyRelease || evt.Type == event.EventChar {
+				handled = w.dispatchKey(evt)
+			} else if evt.Type == event.EventMouseWheel {
+				// Focused component first, then whatever is under the cursor
+				if w.FocusComp == nil || !w.FocusComp.OnEvent(evt) {
+					if !w.Overlay.OnEvent(evt) {
+						w.Root.OnEvent(evt)
+					}
 				}
-			} else {
+			} else if evt.Type == event.EventContextMenu {
+				w.dispatchContextMenu(evt, false)
+			} else if c := component.Captured(); c != nil && (evt.Type == event.EventMouseMove || evt.Type == event.EventMouseRelease) {
+				// A drag in progress gets the pointer wherever it is
+				c.OnEvent(evt)
+			} else if !w.Overlay.OnEvent(evt) && (w.MenuBar == nil || !w.MenuBar.OnEvent(evt)) {
 				// Dispatch to UI components (Root) for mouse/other events
 				w.Root.OnEvent(evt)
 			}
 
+			if data, ok := evt.Data.(event.MouseEvent); ok {
+				w.lastMouse = data
+				// No WM_SETCURSOR is sent while the mouse is captured
+				if msg == WM_MOUSEMOVE && component.Captured() != nil {
+					w.updateCursor()
+				}
+				if msg == WM_MOUSEMOVE {
+					w.trackMouse()
+					w.updateTooltip(data.X, data.Y)
+				}
+			}
+			// Keep receiving the pointer outside the window while captured
+			if (msg == WM_LBUTTONDOWN || msg == WM_LBUTTONDBLCLK) && component.Captured() != nil {
+				procSetCapture.Call(uintptr(hwnd))
+			} else if msg == WM_LBUTTONUP {
+				component.ReleaseCapture()
+				procReleaseCapture.Call()
+			}
+
+			// Keys the GUI handled must not reach the system menu
+			if msg == WM_SYSKEYDOWN || msg == WM_SYSKEYUP {
+				if msg == WM_SYSKEYDOWN {
+					w.sysKeyHandled = handled
+				}
+				if handled {
+					w.EventBus.Publish(evt)
+					w.Render()
+					return 0
+				}
+			}
+
 			// Publish to EventBus (async)
 			w.EventBus.Publish(evt)
 
@@ -294,7 +449,8 @@ func wndProc(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 			width := int32(lParam & 0xFFFF)
 			height := int32((lParam >> 16) & 0xFFFF)
 			w.Renderer.Resize(width, height)
-			w.Root.SetBounds(0, 0, width, height)
+			w.layoutClient(width, height)
+			w.Overlay.SetBounds(0, 0, width, height)
 			w.Render()
 		}
 	}
@@ -309,41 +465,163 @@ func wndProc(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
 	return ret
 }
 
-func convertEvent(msg uint32, wParam, lParam uintptr) (event.Event, bool) {
+// focusAt moves the focus to the component at a window position. Clicks on
+// the menu bar, on popups that keep the focus where it is, and outside an
+// open modal popup leave the focus unchanged.
+func (w *Window) focusAt(x, y int32) {
+	if w.Overlay.Contains(x, y) {
+		if target := w.Overlay.FindComponentAt(x, y); target != nil {
+			w.SetFocus(target)
+		}
+		return
+	}
+	if w.Overlay.HasModal() || (w.MenuBar != nil && w.MenuBar.Bounds.Contains(x, y)) {
+		return
+	}
+	w.SetFocus(w.Root.FindComponentAt(x, y))
+}
+
+// dispatchKey passes a keyboard event to the popups, the menu bar's
+// mnemonics, the focused component and finally the menu accelerators, and
+// reports whether one of them handled it.
+func (w *Window) dispatchKey(evt event.Event) bool {
+	// A modal popup keeps the keys from the menu bar
+	modal := w.Overlay.HasModal()
+	if w.Overlay.OnEvent(evt) {
+		return true
+	}
+	if !modal && w.MenuBar != nil && w.MenuBar.OnEvent(evt) {
+		return true
+	}
+	if w.FocusComp != nil && w.FocusComp.OnEvent(evt) {
+		return true
+	}
+	data, ok := evt.Data.(event.KeyEvent)
+	if !ok || evt.Type != event.EventKeyPress {
+		return false
+	}
+	if w.FocusComp != nil {
+		ancestors := w.Overlay.Ancestors(w.FocusComp)
+		if ancestors == nil {
+			ancestors = component.Ancestors(w.Root, w.FocusComp)
+		}
+		for i := len(ancestors) - 1; i >= 0; i-- {
+			if h, ok := ancestors[i].(component.ShortcutHandler); ok && h.HandleShortcut(data) {
+				return true
+			}
+		}
+	}
+	// Tab and Shift+Tab move the focus through the window content
+	if !modal && data.VirtualKeyCode == 0x09 && data.Modifiers&(event.ModCtrl|event.ModAlt) == 0 {
+		if next := component.NextFocus(w.Root, w.FocusComp, data.Modifiers&event.ModShift != 0); next != nil {
+			w.SetFocus(next)
+		}
+		return true
+	}
+	if !modal && w.MenuBar != nil && w.MenuBar.HandleAccelerator(data) {
+		return true
+	}
+	// Context menu key, or Shift+F10
+	if data.VirtualKeyCode == 0x5D || (data.VirtualKeyCode == 0x79 && data.Modifiers&event.ModShift != 0) {
+		if w.FocusComp != nil {
+			b := w.FocusComp.GetBounds()
+			w.dispatchContextMenu(event.Event{
+				Type: event.EventContextMenu,
+				Data: event.MouseEvent{X: b.X + 4, Y: b.Y + 4, Modifiers: data.Modifiers},
+			}, true)
+		}
+		return true
+	}
+	return false
+}
+
+// dispatchContextMenu offers a context menu request to the components and
+// shows the ContextMenu of its target if none of them handled it. The
+// target is the focused component for requests made with the keyboard,
+// and the component under the pointer otherwise.
+func (w *Window) dispatchContextMenu(evt event.Event, keyboard bool) {
+	data := evt.Data.(event.MouseEvent)
+	var target component.Component
+	if keyboard {
+		target = w.FocusComp
+		if target.OnEvent(evt) {
+			return
+		}
+	} else {
+		if w.Overlay.OnEvent(evt) || w.Overlay.HasModal() {
+			return
+		}
+		if w.MenuBar != nil && w.MenuBar.Bounds.Contains(data.X, data.Y) {
+			return
+		}
+		if w.Root.OnEvent(evt) {
+			return
+		}
+		target = w.Root.FindComponentAt(data.X, data.Y)
+	}
+	if owner, ok := target.(component.ContextMenuOwner); ok {
+		if menu := owner.GetContextMenu(); menu != nil {
+			menu.Show(data.X, data.Y)
+		}
+	}
+}
+
+func convertEvent(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) (event.Event, bool) {
 	switch msg {
 	case WM_CLOSE:
 		return event.Event{Type: event.EventClose}, true
 	case WM_SIZE:
 		return event.Event{Type: event.EventResize}, true
 	case WM_MOUSEMOVE:
-		x := int32(lParam & 0xFFFF)
-		y := int32((lParam >> 16) & 0xFFFF)
+		x := int32(int16(lParam & 0xFFFF)) // Negative outside the window while captured
+		y := int32(int16((lParam >> 16) & 0xFFFF))
 		return event.Event{
 			Type: event.EventMouseMove,
-			Data: event.MouseEvent{X: x, Y: y},
+			Data: event.MouseEvent{X: x, Y: y, Modifiers: getModifiers()},
 		}, true
-	case WM_LBUTTONDOWN:
-		x := int32(lParam & 0xFFFF)
-		y := int32((lParam >> 16) & 0xFFFF)
+	case WM_LBUTTONDOWN, WM_LBUTTONDBLCLK:
+		// A double click arrives as a second click with Clicks == 2
+		x := int32(int16(lParam & 0xFFFF))
+		y := int32(int16((lParam >> 16) & 0xFFFF))
+		clicks := 1
+		if msg == WM_LBUTTONDBLCLK {
+			clicks = 2
+		}
 		return event.Event{
 			Type: event.EventMouseClick,
-			Data: event.MouseEvent{X: x, Y: y, Button: 1},
+			Data: event.MouseEvent{X: x, Y: y, Button: 1, Clicks: clicks, Modifiers: getModifiers()},
 		}, true
 	case WM_LBUTTONUP:
-		x := int32(lParam & 0xFFFF)
-		y := int32((lParam >> 16) & 0xFFFF)
+		x := int32(int16(lParam & 0xFFFF))
+		y := int32(int16((lParam >> 16) & 0xFFFF))
 		return event.Event{
 			Type: event.EventMouseRelease,
-			Data: event.MouseEvent{X: x, Y: y, Button: 1},
+			Data: event.MouseEvent{X: x, Y: y, Button: 1, Modifiers: getModifiers()},
+		}, true
+	case WM_RBUTTONUP:
+		x := int32(int16(lParam & 0xFFFF))
+		y := int32(int16((lParam >> 16) & 0xFFFF))
+		return event.Event{
+			Type: event.EventContextMenu,
+			Data: event.MouseEvent{X: x, Y: y, Button: 2, Modifiers: getModifiers()},
 		}, true
-	case WM_MOUSEWHEEL:
-		// High word of wParam is delta
-		delta := int16((wParam >> 16) & 0xFFFF)
+	case WM_MOUSEWHEEL, WM_MOUSEHWHEEL:
+		// High word of wParam is delta, lParam holds screen coordinates
+		delta := int(int16((wParam >> 16) & 0xFFFF))
+		pt := point{X: int32(int16(lParam & 0xFFFF)), Y: int32(int16((lParam >> 16) & 0xFFFF))}
+		procScreenToClient.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&pt)))
+		data := event.MouseEvent{X: pt.X, Y: pt.Y, Modifiers: getModifiers()}
+		if msg == WM_MOUSEHWHEEL {
+			data.DeltaX = delta
+		} else {
+			data.Delta = delta
+		}
 		return event.Event{
 			Type: event.EventMouseWheel,
-			Data: event.MouseEvent{Delta: int(delta)},
+			Data: data,
 		}, true
-	case WM_KEYDOWN:
+	case WM_KEYDOWN, WM_SYSKEYDOWN:
+		// Keys pressed with Alt arrive as system keys
 		return event.Event{
 			Type: event.EventKeyPress,
 			Data: event.KeyEvent{
@@ -351,7 +629,7 @@ func convertEvent(msg uint32, wParam, lParam uintptr) (event.Event, bool) {
 				Modifiers:      getModifiers(),
 			},
 		}, true
-	case WM_KEYUP:
+	case WM_KEYUP, WM_SYSKEYUP:
 		return event.Event{
 			Type: event.EventKeyRelease,
 			Data: event.KeyEvent{